@@ -1,15 +1,19 @@
 package main
 
 import (
+	"context"
+	"crypto/rand"
 	"database/sql"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
+	"strconv"
 	"time"
 
 	"github.com/cloudinary/cloudinary-go/v2"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/joho/godotenv"
 	"github.com/gin-contrib/cors"
 
@@ -24,11 +28,13 @@ import (
 	chatUseCase "github.com/Ramsi97/edu-social-backend/internal/chat/use_case"
 
 	// Auth feature
+	authDomain "github.com/Ramsi97/edu-social-backend/internal/auth/domain"
 	authHttp "github.com/Ramsi97/edu-social-backend/internal/auth/delivery/http"
 	authPostgres "github.com/Ramsi97/edu-social-backend/internal/auth/repository/postgres"
 	authUseCase "github.com/Ramsi97/edu-social-backend/internal/auth/use_case"
 
 	// Post feature
+	postDomain "github.com/Ramsi97/edu-social-backend/internal/post/domain"
 	postHttp "github.com/Ramsi97/edu-social-backend/internal/post/delivery/http"
 	postPostgres "github.com/Ramsi97/edu-social-backend/internal/post/repository/postgres"
 	postUseCase "github.com/Ramsi97/edu-social-backend/internal/post/use_case"
@@ -43,31 +49,96 @@ import (
 	commentPostgres "github.com/Ramsi97/edu-social-backend/internal/comment/repository/postgres"
 	commentUseCase "github.com/Ramsi97/edu-social-backend/internal/comment/use_case"
 
+	// Follow Feature
+	followHttp "github.com/Ramsi97/edu-social-backend/internal/follow/delivery/http"
+	followPostgres "github.com/Ramsi97/edu-social-backend/internal/follow/repository/postgres"
+	followUseCase "github.com/Ramsi97/edu-social-backend/internal/follow/use_case"
+
+	// Block Feature
+	blockHttp "github.com/Ramsi97/edu-social-backend/internal/block/delivery/http"
+	blockPostgres "github.com/Ramsi97/edu-social-backend/internal/block/repository/postgres"
+	blockUseCase "github.com/Ramsi97/edu-social-backend/internal/block/use_case"
+
+	// User profile Feature
+	userDomain "github.com/Ramsi97/edu-social-backend/internal/user/domain"
+	userHttp "github.com/Ramsi97/edu-social-backend/internal/user/delivery/http"
+	userPostgres "github.com/Ramsi97/edu-social-backend/internal/user/repository/postgres"
+	userUseCase "github.com/Ramsi97/edu-social-backend/internal/user/use_case"
+
 	// Group Chat Feature
 	groupHttp "github.com/Ramsi97/edu-social-backend/internal/group/delivery/http"
 	groupSocket "github.com/Ramsi97/edu-social-backend/internal/group/delivery/socket"
 	groupPostgres "github.com/Ramsi97/edu-social-backend/internal/group/repository/postgres"
 	groupUseCase "github.com/Ramsi97/edu-social-backend/internal/group/use_case"
 
+	// Media Feature
+	mediaDomain "github.com/Ramsi97/edu-social-backend/internal/media/domain"
+	mediaHttp "github.com/Ramsi97/edu-social-backend/internal/media/delivery/http"
+	mediaPostgres "github.com/Ramsi97/edu-social-backend/internal/media/repository/postgres"
+	mediaUseCase "github.com/Ramsi97/edu-social-backend/internal/media/use_case"
+
+	// Mention & Notification Features
+	mentionPostgres "github.com/Ramsi97/edu-social-backend/internal/mention/repository/postgres"
+	mentionUseCase "github.com/Ramsi97/edu-social-backend/internal/mention/use_case"
+	notificationDomain "github.com/Ramsi97/edu-social-backend/internal/notification/domain"
+	notificationHttp "github.com/Ramsi97/edu-social-backend/internal/notification/delivery/http"
+	notificationPostgres "github.com/Ramsi97/edu-social-backend/internal/notification/repository/postgres"
+	notificationUseCase "github.com/Ramsi97/edu-social-backend/internal/notification/use_case"
+
 	// Shared
 	"github.com/Ramsi97/edu-social-backend/internal/middleware"
+	sharedHttp "github.com/Ramsi97/edu-social-backend/internal/shared/delivery/http"
 	cloud "github.com/Ramsi97/edu-social-backend/internal/shared/infrastructure"
+	sharedInterfaces "github.com/Ramsi97/edu-social-backend/internal/shared/interfaces"
 	"github.com/Ramsi97/edu-social-backend/pkg/auth"
+	"github.com/Ramsi97/edu-social-backend/pkg/oidc"
+	"github.com/Ramsi97/edu-social-backend/pkg/websocket"
 )
 
 func main() {
 	// -------------------
 	// Load configuration
 	// -------------------
-	if err := godotenv.Load(); err != nil {
+	err := godotenv.Load()
+	if err != nil {
 		log.Println("No .env file found, using system env")
 	}
 
-	jwtSecret := os.Getenv("JWT_SECRET")
-	if jwtSecret == "" {
-		log.Fatal("JWT_SECRET not found in env")
+	// APP_ENV=development lets the server start without the keys and URLs a
+	// real deployment has to set, making up stand-ins for them instead
+	devMode := os.Getenv("APP_ENV") == "development"
+
+	// Access tokens are signed with the active key in JWT_KEYS_DIR; the other
+	// keys there keep verifying until the tokens they signed have expired
+	var signingKeys *auth.KeySet
+	if keysDir := os.Getenv("JWT_KEYS_DIR"); keysDir != "" {
+		signingKeys, err = auth.LoadKeySet(keysDir, os.Getenv("JWT_ACTIVE_KEY_ID"))
+		if err != nil {
+			log.Fatalf("Failed to load JWT signing keys: %v", err)
+		}
+	} else {
+		// Tokens signed with a key only this process knows stop working on
+		// restart and can't be checked by other instances
+		if !devMode {
+			log.Fatal("JWT_KEYS_DIR must be set")
+		}
+		log.Println("JWT_KEYS_DIR not set, signing tokens with an ephemeral key")
+		signingKeys, err = auth.GenerateEphemeralKeySet()
+		if err != nil {
+			log.Fatalf("Failed to generate JWT signing key: %v", err)
+		}
+	}
+	auth.SetSigningKeys(signingKeys)
+
+	jwtIssuer := os.Getenv("JWT_ISSUER")
+	if jwtIssuer == "" {
+		jwtIssuer = "edu-social"
 	}
-	auth.SetJWTSecret(jwtSecret)
+	jwtAudience := os.Getenv("JWT_AUDIENCE")
+	if jwtAudience == "" {
+		jwtAudience = "edu-social-api"
+	}
+	auth.SetIssuer(jwtIssuer, jwtAudience)
 
 	dbHost := os.Getenv("PGHOST")
 	dbUser := os.Getenv("PGUSER")
@@ -97,37 +168,254 @@ func main() {
 	log.Println("Connected to Neon PostgreSQL")
 
 	// -------------------
-	// Initialize Cloudinary
+	// Initialize Media Storage
 	// -------------------
-	cldInstance, err := cloudinary.NewFromParams(
-		os.Getenv("CLOUDINARY_CLOUD_NAME"),
-		os.Getenv("CLOUDINARY_API_KEY"),
-		os.Getenv("CLOUDINARY_API_SECRET"),
-	)
-	if err != nil {
-		log.Fatal("Failed to init Cloudinary")
+	// MEDIA_DRIVER picks the backend; without it Cloudinary is used when
+	// configured and files are kept on local disk otherwise
+	mediaDriver := os.Getenv("MEDIA_DRIVER")
+	if mediaDriver == "" {
+		mediaDriver = "local"
+		if os.Getenv("CLOUDINARY_CLOUD_NAME") != "" {
+			mediaDriver = "cloudinary"
+		}
+	}
+
+	var mediaBackend sharedInterfaces.MediaBackend
+	var localMedia *cloud.LocalStorageConfig
+	switch mediaDriver {
+	case "cloudinary":
+		cldInstance, err := cloudinary.NewFromParams(
+			os.Getenv("CLOUDINARY_CLOUD_NAME"),
+			os.Getenv("CLOUDINARY_API_KEY"),
+			os.Getenv("CLOUDINARY_API_SECRET"),
+		)
+		if err != nil {
+			log.Fatal("Failed to init Cloudinary")
+		}
+		mediaBackend = cloud.NewCloudinaryUploader(cldInstance)
+	case "s3":
+		useSSL, _ := strconv.ParseBool(os.Getenv("S3_USE_SSL"))
+		mediaBackend, err = cloud.NewS3Storage(cloud.S3Config{
+			Endpoint:  os.Getenv("S3_ENDPOINT"),
+			Region:    os.Getenv("S3_REGION"),
+			Bucket:    os.Getenv("S3_BUCKET"),
+			AccessKey: os.Getenv("S3_ACCESS_KEY"),
+			SecretKey: os.Getenv("S3_SECRET_KEY"),
+			UseSSL:    useSSL,
+			PublicURL: os.Getenv("S3_PUBLIC_URL"),
+		})
+		if err != nil {
+			log.Fatalf("Failed to init S3 storage: %v", err)
+		}
+	case "local":
+		localMedia = &cloud.LocalStorageConfig{
+			Dir:     os.Getenv("MEDIA_LOCAL_DIR"),
+			BaseURL: os.Getenv("MEDIA_BASE_URL"),
+			Secret:  []byte(os.Getenv("MEDIA_SIGNING_KEY")),
+		}
+		if localMedia.Dir == "" {
+			localMedia.Dir = "uploads"
+		}
+		if localMedia.BaseURL == "" {
+			if !devMode {
+				log.Fatal("MEDIA_BASE_URL must be set for MEDIA_DRIVER=local")
+			}
+			localMedia.BaseURL = "http://localhost:8080/media"
+		}
+		if len(localMedia.Secret) == 0 {
+			if !devMode {
+				log.Fatal("MEDIA_SIGNING_KEY must be set for MEDIA_DRIVER=local")
+			}
+			log.Println("MEDIA_SIGNING_KEY not set, signing media links with an ephemeral key")
+			localMedia.Secret = make([]byte, 32)
+			if _, err := rand.Read(localMedia.Secret); err != nil {
+				log.Fatalf("Failed to generate media signing key: %v", err)
+			}
+		}
+		mediaBackend = cloud.NewLocalStorage(*localMedia)
+	default:
+		log.Fatalf("Unknown MEDIA_DRIVER %q", mediaDriver)
+	}
+	// Thumbnails and medium sizes of uploaded images are made in the background
+	variantConfig := mediaDomain.DefaultVariantConfig()
+	if v, err := strconv.Atoi(os.Getenv("MEDIA_VARIANT_WORKERS")); err == nil && v > 0 {
+		variantConfig.Workers = v
+	}
+	imageProcessor := mediaUseCase.NewVariantProcessor(mediaPostgres.NewVariantRepository(db), mediaBackend, variantConfig)
+	mediaPolicies := cloud.DefaultMediaPolicies()
+	mediaUploader := cloud.NewValidatedStorage(mediaBackend, mediaPolicies, imageProcessor)
+
+	// Large files can be sent ahead in chunks and attached by media ID
+	uploadConfig := mediaDomain.DefaultUploadConfig()
+	if dir := os.Getenv("UPLOAD_STAGING_DIR"); dir != "" {
+		uploadConfig.StagingDir = dir
+	}
+	if v, err := strconv.Atoi(os.Getenv("UPLOAD_CHUNK_MB")); err == nil && v > 0 {
+		uploadConfig.ChunkSize = int64(v) << 20
+	}
+	if v, err := strconv.Atoi(os.Getenv("UPLOAD_MAX_SESSIONS")); err == nil && v > 0 {
+		uploadConfig.MaxOpenSessions = v
+	}
+	if v, err := strconv.Atoi(os.Getenv("UPLOAD_MAX_STAGED_MB")); err == nil && v > 0 {
+		uploadConfig.MaxStagedBytes = int64(v) << 20
+	}
+	mediaUC := mediaUseCase.NewMediaUseCase(mediaPostgres.NewUploadRepository(db), mediaUploader, mediaPolicies, uploadConfig)
+
+	// -------------------
+	// Initialize Mailer
+	// -------------------
+	var mailer sharedInterfaces.Mailer
+	switch os.Getenv("MAIL_DRIVER") {
+	case "smtp":
+		mailer = cloud.NewSMTPMailer(
+			os.Getenv("SMTP_HOST"),
+			os.Getenv("SMTP_PORT"),
+			os.Getenv("SMTP_USERNAME"),
+			os.Getenv("SMTP_PASSWORD"),
+			os.Getenv("MAIL_FROM"),
+		)
+	default:
+		// Local development: keep mails in memory and drop them in MAIL_DIR
+		mailer = cloud.NewMemoryMailer(os.Getenv("MAIL_DIR"))
+	}
+
+	// TOTP secrets are stored encrypted with MFA_ENCRYPTION_KEY, 32 bytes in
+	// base64; losing the key means everyone has to enroll their authenticator again
+	var totpSecrets *auth.SecretBox
+	if key := os.Getenv("MFA_ENCRYPTION_KEY"); key != "" {
+		totpSecrets, err = auth.ParseSecretBoxKey(key)
+		if err != nil {
+			log.Fatalf("Invalid MFA_ENCRYPTION_KEY: %v", err)
+		}
+	} else {
+		if !devMode {
+			log.Fatal("MFA_ENCRYPTION_KEY must be set")
+		}
+		log.Println("MFA_ENCRYPTION_KEY not set, encrypting TOTP secrets with an ephemeral key")
+		totpSecrets, err = auth.GenerateEphemeralSecretBox()
+		if err != nil {
+			log.Fatalf("Failed to generate MFA encryption key: %v", err)
+		}
+	}
+
+	authConfig := authDomain.AuthConfig{
+		AppBaseURL:      os.Getenv("APP_BASE_URL"),
+		TOTPIssuer:      "EduSocial",
+		TOTPSecrets:     totpSecrets,
+		AccountThrottle: authDomain.DefaultAccountThrottle(),
+		IPThrottle:      authDomain.DefaultIPThrottle(),
+	}
+
+	// University SSO is optional; without OIDC_ISSUER the endpoints answer 404
+	var identityProvider authDomain.IdentityProvider
+	if issuer := os.Getenv("OIDC_ISSUER"); issuer != "" {
+		claims := oidc.DefaultClaimMapping()
+		if v := os.Getenv("OIDC_CLAIM_STUDENT_ID"); v != "" {
+			claims.StudentID = v
+		}
+		if v := os.Getenv("OIDC_CLAIM_EMAIL"); v != "" {
+			claims.Email = v
+		}
+		if v := os.Getenv("OIDC_CLAIM_FIRST_NAME"); v != "" {
+			claims.FirstName = v
+		}
+		if v := os.Getenv("OIDC_CLAIM_LAST_NAME"); v != "" {
+			claims.LastName = v
+		}
+		if v := os.Getenv("OIDC_CLAIM_EMAIL_VERIFIED"); v != "" {
+			claims.EmailVerified = v
+		}
+		identityProvider = oidc.NewClient(oidc.Config{
+			Issuer:       issuer,
+			ClientID:     os.Getenv("OIDC_CLIENT_ID"),
+			ClientSecret: os.Getenv("OIDC_CLIENT_SECRET"),
+			RedirectURL:  os.Getenv("OIDC_REDIRECT_URL"),
+			Claims:       claims,
+		})
+	}
+
+	if v, err := strconv.Atoi(os.Getenv("LOGIN_LOCKOUT_THRESHOLD")); err == nil && v > 0 {
+		authConfig.AccountThrottle.LockoutThreshold = v
+	}
+	if v, err := strconv.Atoi(os.Getenv("LOGIN_LOCKOUT_MINUTES")); err == nil && v > 0 {
+		authConfig.AccountThrottle.LockoutDuration = time.Duration(v) * time.Minute
+	}
+
+	accountConfig := userDomain.DefaultAccountConfig()
+	if v, err := strconv.Atoi(os.Getenv("ACCOUNT_DELETION_GRACE_DAYS")); err == nil && v >= 0 {
+		accountConfig.DeletionGracePeriod = time.Duration(v) * 24 * time.Hour
+	}
+	switch policy := userDomain.DeletionPolicy(os.Getenv("ACCOUNT_DELETION_POLICY")); policy {
+	case userDomain.DeletionPolicyAnonymize, userDomain.DeletionPolicyDelete:
+		accountConfig.DeletionPolicy = policy
+	case "":
+	default:
+		log.Fatalf("Unknown ACCOUNT_DELETION_POLICY %q", policy)
+	}
+
+	attachmentConfig := postDomain.DefaultAttachmentConfig()
+	if v, err := strconv.Atoi(os.Getenv("POST_MAX_ATTACHMENTS")); err == nil && v > 0 {
+		attachmentConfig.MaxAttachments = v
+	}
+	for env, mediaType := range map[string]postDomain.MediaType{
+		"POST_MAX_IMAGE_MB":    postDomain.MediaImage,
+		"POST_MAX_VIDEO_MB":    postDomain.MediaVideo,
+		"POST_MAX_DOCUMENT_MB": postDomain.MediaDocument,
+	} {
+		if v, err := strconv.Atoi(os.Getenv(env)); err == nil && v > 0 {
+			attachmentConfig.MaxBytes[mediaType] = int64(v) << 20
+		}
+	}
+
+	tagConfig := postDomain.DefaultTagConfig()
+	if v, err := strconv.Atoi(os.Getenv("TRENDING_WINDOW_HOURS")); err == nil && v > 0 {
+		tagConfig.TrendingWindow = time.Duration(v) * time.Hour
+	}
+	if v, err := strconv.Atoi(os.Getenv("TRENDING_HALF_LIFE_HOURS")); err == nil && v > 0 {
+		tagConfig.TrendingHalfLife = time.Duration(v) * time.Hour
+	}
+	if v, err := strconv.Atoi(os.Getenv("TRENDING_MIN_AUTHORS")); err == nil && v > 0 {
+		tagConfig.TrendingMinAuthors = v
 	}
-	mediaUploader := cloud.NewCloudinaryUploader(cldInstance)
 
 	// -------------------
 	// Initialize Repositories
 	// -------------------
 	userRepo := authPostgres.NewUserRepository(db)
+	sessionRepo := authPostgres.NewSessionRepository(db)
+	oneTimeTokenRepo := authPostgres.NewOneTimeTokenRepository(db)
+	loginAttemptStore := authPostgres.NewLoginAttemptStore(db)
+	mfaRepo := authPostgres.NewMFARepository(db)
+	ssoRepo := authPostgres.NewSSORepository(db)
+	profileRepo := userPostgres.NewUserRepository(db)
+	accountRepo := userPostgres.NewAccountRepository(db)
+	followRepo := followPostgres.NewFollowRepository(db)
+	blockRepo := blockPostgres.NewBlockRepository(db)
 	postRepo := postPostgres.NewPostRepository(db)
 	likeRepo := likePostgres.NewLikeRepository(db)
 	commentRepo := commentPostgres.NewCommentRepository(db)
 	chatRepo := chatPostgres.NewChatRepository(db)
 	groupchatRepo := groupPostgres.NewGroupChatRepo(db)
+	mentionRepo := mentionPostgres.NewMentionRepository(db)
+	notificationRepo := notificationPostgres.NewNotificationRepository(db)
 
 	// -------------------
 	// Initialize Use Cases
 	// -------------------
-	authUC := authUseCase.NewAuthUseCase(userRepo, mediaUploader)
-	postUC := postUseCase.NewPostUseCase(postRepo)
+	authUC := authUseCase.NewAuthUseCase(userRepo, sessionRepo, oneTimeTokenRepo, loginAttemptStore, mfaRepo, ssoRepo, identityProvider, mediaUploader, mailer, authConfig)
+	if err := authUC.SealMFASecrets(context.Background()); err != nil {
+		log.Fatalf("Failed to encrypt stored TOTP secrets: %v", err)
+	}
+	followUC := followUseCase.NewFollowUseCase(followRepo)
+	blockUC := blockUseCase.NewBlockUseCase(blockRepo)
+	userUC := userUseCase.NewUserUseCase(profileRepo, accountRepo, followUC, mediaUploader, accountConfig)
+	notificationUC := notificationUseCase.NewNotificationUseCase(notificationRepo)
+	mentionUC := mentionUseCase.NewMentionUseCase(mentionRepo, userUC, notificationUC)
+	commentUC := commentUseCase.NewCommentUseCase(commentRepo, userUC, mediaUC, mentionUC)
+	postUC := postUseCase.NewPostUseCase(postRepo, commentUC, mediaUploader, mediaUC, attachmentConfig, tagConfig, mentionUC)
 	likeUC := likeUseCase.NewLikeUseCase(likeRepo)
-	commentUC := commentUseCase.NewCommentUseCase(commentRepo)
-	chatUC := chatUseCase.NewChatUseCase(chatRepo)
-	groupchatUC := groupUseCase.NewGroupChatUseCase(groupchatRepo)
+	chatUC := chatUseCase.NewChatUseCase(chatRepo, userUC, mediaUC, mentionUC)
+	groupchatUC := groupUseCase.NewGroupChatUseCase(groupchatRepo, mentionUC)
 
 	// -------------------
 	// Initialize Router
@@ -140,7 +428,7 @@ func main() {
 			"http://127.0.0.1:3000",
 		},
 		AllowMethods: []string{
-			"GET", "POST", "PUT", "DELETE", "OPTIONS",
+			"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS",
 		},
 		AllowHeaders: []string{
 			"Origin",
@@ -154,6 +442,13 @@ func main() {
 		MaxAge: 12 * time.Hour,
 	}))
 
+	// Files kept on local disk are served by the API itself
+	if localMedia != nil {
+		if err := sharedHttp.NewMediaHandler(router, *localMedia); err != nil {
+			log.Fatalf("Invalid MEDIA_BASE_URL: %v", err)
+		}
+	}
+
 	// Health check
 	router.GET("/health", func(ctx *gin.Context) {
 		ctx.JSON(http.StatusOK, gin.H{"status": "UP"})
@@ -165,15 +460,27 @@ func main() {
 	io := socket.NewServer(nil, nil)
 
 	// Register chat (1–1)
-	chatSocketHandler := chatSocket.NewSocketHandler(io, chatUC)
+	chatSocketHandler := chatSocket.NewSocketHandler(io, chatUC, authUC)
 	chatSocketHandler.RegisterMiddleWare()
 	chatSocketHandler.RegisterEvents()
 
 	// Register group chat
-	groupChatSocketHandler := groupSocket.NewSocketHandler(io, groupchatUC)
+	groupChatSocketHandler := groupSocket.NewSocketHandler(io, groupchatUC, authUC)
 	groupChatSocketHandler.RegisterMiddleWare()
 	groupChatSocketHandler.RegisterEvents()
 
+	// Drop live sockets as soon as their session is terminated
+	authUC.OnSessionsRevoked(func(sessionIDs ...uuid.UUID) {
+		for _, id := range sessionIDs {
+			io.In(socket.Room(websocket.SessionRoom(id.String()))).DisconnectSockets(true)
+		}
+	})
+
+	// Push notifications to every device the user is connected on
+	notificationUC.OnNotify(func(n notificationDomain.Notification) {
+		io.To(socket.Room(websocket.UserRoom(n.UserID.String()))).Emit("notification", n)
+	})
+
 	router.GET("/socket.io/*any", gin.WrapH(io.ServeHandler(nil)))
 	router.POST("/socket.io/*any", gin.WrapH(io.ServeHandler(nil)))
 
@@ -182,27 +489,70 @@ func main() {
 	// -------------------
 	api := router.Group("/api/v1")
 	authGroup := api.Group("/auth")
+	userGroup := api.Group("/users")
+	userGroup.Use(middleware.AuthMiddleWare(authUC))
+	followGroup := api.Group("/follow")
+	followGroup.Use(middleware.AuthMiddleWare(authUC))
 	postGroup := api.Group("/posts")
-	postGroup.Use(middleware.AuthMiddleWare())
+	postGroup.Use(middleware.AuthMiddleWare(authUC))
+	tagGroup := api.Group("/tags")
+	tagGroup.Use(middleware.AuthMiddleWare(authUC))
 	likeGroup := api.Group("/like")
-	likeGroup.Use(middleware.AuthMiddleWare())
+	likeGroup.Use(middleware.AuthMiddleWare(authUC))
 	commentGroup := api.Group("/comment")
-	commentGroup.Use(middleware.AuthMiddleWare())
-	likeGroup.Use(middleware.AuthMiddleWare())
+	commentGroup.Use(middleware.AuthMiddleWare(authUC))
+	likeGroup.Use(middleware.AuthMiddleWare(authUC))
 	chatGroup := api.Group("/chat")
-	chatGroup.Use(middleware.AuthMiddleWare())
+	chatGroup.Use(middleware.AuthMiddleWare(authUC))
 	groupApiGroup := api.Group("/group")
-	groupApiGroup.Use(middleware.AuthMiddleWare())
+	groupApiGroup.Use(middleware.AuthMiddleWare(authUC))
+	uploadGroup := api.Group("/uploads")
+	uploadGroup.Use(middleware.AuthMiddleWare(authUC))
+	notificationGroup := api.Group("/notifications")
+	notificationGroup.Use(middleware.AuthMiddleWare(authUC))
+	adminGroup := api.Group("/admin")
+	adminGroup.Use(middleware.AuthMiddleWare(authUC), middleware.RequireRoles(authDomain.RoleAdmin))
 
 	// -------------------
 	// Attach Handlers
 	// -------------------
 	authHttp.NewAuthHandler(authGroup, authUC)
-	postHttp.NewPostHandler(postGroup, postUC, mediaUploader)
+	authHttp.NewJWKSHandler(router, signingKeys)
+	authHttp.NewAdminHandler(adminGroup, authUC)
+	userHttp.NewUserHandler(userGroup, userUC)
+	blockHttp.NewBlockHandler(userGroup, blockUC)
+	followHttp.NewFollowHandler(followGroup, followUC)
+	postHttp.NewPostHandler(postGroup, postUC)
+	postHttp.NewTagHandler(tagGroup, postUC)
 	likeHttp.NewLikeHandler(likeGroup, likeUC)
 	commentHttp.NewCommentHandler(commentGroup, commentUC)
 	chatHttp.NewChatHandler(chatGroup, chatUC)
 	groupHttp.NewGroupHandler(groupchatUC, groupApiGroup)
+	mediaHttp.NewUploadHandler(uploadGroup, mediaUC)
+	notificationHttp.NewNotificationHandler(notificationGroup, notificationUC)
+
+	// -------------------
+	// Background jobs
+	// -------------------
+	go func() {
+		if err := postUC.BackfillTags(context.Background()); err != nil {
+			log.Printf("tag backfill failed: %v", err)
+		}
+	}()
+
+	go func() {
+		ticker := time.NewTicker(time.Hour)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			if err := userUC.PurgeDeletedAccounts(context.Background()); err != nil {
+				log.Printf("account purge failed: %v", err)
+			}
+			if err := mediaUC.CollectGarbage(context.Background()); err != nil {
+				log.Printf("upload cleanup failed: %v", err)
+			}
+		}
+	}()
 
 	// -------------------
 	// Run server
@@ -0,0 +1,167 @@
+package http
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Ramsi97/edu-social-backend/internal/auth/domain"
+	"github.com/Ramsi97/edu-social-backend/pkg/response"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type adminHandler struct {
+	usecase domain.AuthUseCase
+}
+
+// NewAdminHandler mounts the user administration routes. The group must
+// already be guarded by AuthMiddleWare and RequireRoles.
+func NewAdminHandler(rg *gin.RouterGroup, uc domain.AuthUseCase) {
+	handler := &adminHandler{
+		usecase: uc,
+	}
+
+	rg.GET("/users", handler.ListUsers)
+	rg.POST("/users/:user_id/suspend", handler.SuspendUser)
+	rg.DELETE("/users/:user_id/suspend", handler.UnsuspendUser)
+	rg.PUT("/users/:user_id/role", handler.AssignRole)
+}
+
+func (h *adminHandler) ListUsers(ctx *gin.Context) {
+	filter := domain.UserFilter{
+		Query: ctx.Query("q"),
+		Role:  domain.Role(ctx.Query("role")),
+	}
+
+	if v := ctx.Query("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil {
+			response.Error(ctx, http.StatusBadRequest, "Invalid limit", err.Error())
+			return
+		}
+		filter.Limit = limit
+	}
+
+	if v := ctx.Query("suspended"); v != "" {
+		suspended, err := strconv.ParseBool(v)
+		if err != nil {
+			response.Error(ctx, http.StatusBadRequest, "Invalid suspended filter", err.Error())
+			return
+		}
+		filter.Suspended = &suspended
+	}
+
+	if v := ctx.Query("before"); v != "" {
+		before, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			response.Error(ctx, http.StatusBadRequest, "Invalid before cursor", err.Error())
+			return
+		}
+		filter.Before = &before
+	}
+
+	users, err := h.usecase.ListUsers(ctx.Request.Context(), filter)
+	if err != nil {
+		writeAdminError(ctx, err)
+		return
+	}
+
+	result := make([]domain.AdminUserResponse, 0, len(users))
+	for i := range users {
+		result = append(result, domain.AdminUserResponse{
+			UserResponse:     users[i].ToResponse(),
+			SuspendedAt:      users[i].SuspendedAt,
+			SuspensionReason: users[i].SuspensionReason,
+		})
+	}
+
+	response.Success(ctx, http.StatusOK, "Users fetched", result)
+}
+
+func (h *adminHandler) SuspendUser(ctx *gin.Context) {
+	adminID, userID, ok := adminTarget(ctx)
+	if !ok {
+		return
+	}
+
+	var req domain.SuspendUserRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		response.Error(ctx, http.StatusBadRequest, "Invalid request", err.Error())
+		return
+	}
+
+	if err := h.usecase.SuspendUser(ctx.Request.Context(), adminID, userID, req.Reason); err != nil {
+		writeAdminError(ctx, err)
+		return
+	}
+
+	response.Success(ctx, http.StatusOK, "User suspended", nil)
+}
+
+func (h *adminHandler) UnsuspendUser(ctx *gin.Context) {
+	_, userID, ok := adminTarget(ctx)
+	if !ok {
+		return
+	}
+
+	if err := h.usecase.UnsuspendUser(ctx.Request.Context(), userID); err != nil {
+		writeAdminError(ctx, err)
+		return
+	}
+
+	response.Success(ctx, http.StatusOK, "User unsuspended", nil)
+}
+
+func (h *adminHandler) AssignRole(ctx *gin.Context) {
+	adminID, userID, ok := adminTarget(ctx)
+	if !ok {
+		return
+	}
+
+	var req domain.AssignRoleRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.Error(ctx, http.StatusBadRequest, "role is required", err.Error())
+		return
+	}
+
+	if err := h.usecase.AssignRole(ctx.Request.Context(), adminID, userID, req.Role); err != nil {
+		writeAdminError(ctx, err)
+		return
+	}
+
+	response.Success(ctx, http.StatusOK, "Role updated", nil)
+}
+
+// adminTarget reads the acting admin and the :user_id being acted upon,
+// writing the error response itself when either is invalid.
+func adminTarget(ctx *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	adminID, err := uuid.Parse(ctx.GetString("user_id"))
+	if err != nil {
+		response.Error(ctx, http.StatusUnauthorized, "Invalid session", err.Error())
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	userID, err := uuid.Parse(ctx.Param("user_id"))
+	if err != nil {
+		response.Error(ctx, http.StatusBadRequest, "Invalid user ID", err.Error())
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return adminID, userID, true
+}
+
+func writeAdminError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrUserNotFound):
+		response.Error(ctx, http.StatusNotFound, "User not found", err.Error())
+	case errors.Is(err, domain.ErrInvalidRole):
+		response.Error(ctx, http.StatusBadRequest, "Invalid role", err.Error())
+	case errors.Is(err, domain.ErrCannotModerateSelf):
+		response.Error(ctx, http.StatusConflict, "Cannot moderate your own account", err.Error())
+	default:
+		response.Error(ctx, http.StatusInternalServerError, "Server Error", err.Error())
+	}
+}
@@ -1,14 +1,16 @@
 package http
 
 import (
+	"errors"
 	"fmt"
-	"log"
 	"net/http"
-	"time"
 
 	"github.com/Ramsi97/edu-social-backend/internal/auth/domain"
+	"github.com/Ramsi97/edu-social-backend/internal/middleware"
+	"github.com/Ramsi97/edu-social-backend/pkg/media"
 	"github.com/Ramsi97/edu-social-backend/pkg/response"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
 type authHandler struct {
@@ -22,6 +24,31 @@ func NewAuthHandler(rg *gin.RouterGroup, uc domain.AuthUseCase) {
 
 	rg.POST("/register", handler.Register)
 	rg.POST("/login", handler.Login)
+	rg.POST("/refresh", handler.Refresh)
+	rg.POST("/logout", handler.Logout)
+	rg.GET("/verify-email", handler.VerifyEmail)
+	rg.POST("/verify-email/resend", handler.ResendVerification)
+
+	password := rg.Group("/password")
+	password.POST("/forgot", handler.ForgotPassword)
+	password.POST("/reset", handler.ResetPassword)
+	password.POST("/change", middleware.AuthMiddleWare(uc), handler.ChangePassword)
+
+	sso := rg.Group("/oidc")
+	sso.GET("/login", handler.BeginSSO)
+	sso.GET("/callback", handler.CompleteSSO)
+
+	mfa := rg.Group("/2fa")
+	mfa.POST("/verify", handler.VerifyMFA)
+	mfa.POST("/enroll", middleware.AuthMiddleWare(uc), handler.EnrollMFA)
+	mfa.POST("/confirm", middleware.AuthMiddleWare(uc), handler.ConfirmMFA)
+	mfa.POST("/disable", middleware.AuthMiddleWare(uc), handler.DisableMFA)
+
+	sessions := rg.Group("/sessions")
+	sessions.Use(middleware.AuthMiddleWare(uc))
+	sessions.GET("", handler.ListSessions)
+	sessions.DELETE("", handler.EndOtherSessions)
+	sessions.DELETE("/:session_id", handler.EndSession)
 }
 func (h *authHandler) Register(ctx *gin.Context) {
 	var req *domain.RegisterRequest
@@ -35,8 +62,19 @@ func (h *authHandler) Register(ctx *gin.Context) {
 	fmt.Println(req)
 
 	err := h.usecase.Register(ctx, req)
+	switch {
+	case errors.Is(err, media.ErrUnsupportedType), errors.Is(err, media.ErrCorruptImage),
+		errors.Is(err, media.ErrImageTooLarge), errors.Is(err, media.ErrPolyglot):
+		response.Error(ctx, http.StatusBadRequest, "Invalid profile picture", err.Error())
+		return
+	case errors.Is(err, media.ErrFileTooLarge):
+		response.Error(ctx, http.StatusRequestEntityTooLarge, "Profile picture too large", err.Error())
+		return
+	case errors.Is(err, domain.ErrUserAlreadyExists):
+		response.Error(ctx, http.StatusConflict, "User already exists", err.Error())
+		return
+	}
 	if err != nil {
-		log.Fatal(err)
 		response.Error(ctx, http.StatusInternalServerError, "Server Error", err.Error())
 		return
 	}
@@ -54,45 +92,502 @@ func (h *authHandler) Login(ctx *gin.Context) {
 		return
 	}
 	fmt.Println(req)
+	client := domain.ClientInfo{
+		DeviceLabel: req.DeviceLabel,
+		UserAgent:   ctx.Request.UserAgent(),
+		IPAddress:   ctx.ClientIP(),
+	}
 	if req.Email != nil && *req.Email != "" {
 		fmt.Println("email: " + *req.Email)
-		user, token, err := h.usecase.LoginWithEmail(ctx.Request.Context(), *req.Email, req.Password)
+		user, tokens, err := h.usecase.LoginWithEmail(ctx.Request.Context(), *req.Email, req.Password, client)
+		var mfaRequired *domain.MFARequiredError
+		if errors.As(err, &mfaRequired) {
+			writeMFARequired(ctx, mfaRequired)
+			return
+		}
+		var throttled *domain.ThrottledError
+		if errors.As(err, &throttled) {
+			writeThrottled(ctx, throttled)
+			return
+		}
+		if errors.Is(err, domain.ErrEmailNotVerified) {
+			response.Error(ctx, http.StatusForbidden, "Please verify your email before logging in", err.Error())
+			return
+		}
+		if errors.Is(err, domain.ErrAccountSuspended) {
+			response.Error(ctx, http.StatusForbidden, "Your account has been suspended", err.Error())
+			return
+		}
 		if err != nil {
 			response.Error(ctx, http.StatusUnauthorized, "invalid email or password", err.Error())
 			return
 		}
-		userResponse := domain.UserResponse{
-			ID:         user.ID.String(),
-			FirstName:  user.FirstName,
-			LastName:   user.LastName,
-			StudentID:  user.StudentID,
-			Email:      user.Email,
-			JoinedYear: user.JoinedYear,
-			ProfilePicture: user.ProfilePicture,
-			Gender:     user.Gender,
-			CreatedAt:  user.CreatedAt.Format(time.RFC3339),
-		}
+		userResponse := user.ToResponse()
 
 
 
 
 		response.Success(ctx, http.StatusOK, "Login Successful", domain.LoginResponse{
-			Token: token,
-			User: userResponse,
+			Token: tokens.AccessToken,
+			RefreshToken: tokens.RefreshToken,
+			ExpiresIn: tokens.ExpiresIn,
+			User: &userResponse,
 		})
 		return
 	} else if req.StudentID != nil && *req.StudentID != "" {
-		token, err := h.usecase.LoginWithId(ctx.Request.Context(), *req.StudentID, req.Password)
+		tokens, err := h.usecase.LoginWithId(ctx.Request.Context(), *req.StudentID, req.Password, client)
+		var mfaRequired *domain.MFARequiredError
+		if errors.As(err, &mfaRequired) {
+			writeMFARequired(ctx, mfaRequired)
+			return
+		}
+		var throttled *domain.ThrottledError
+		if errors.As(err, &throttled) {
+			writeThrottled(ctx, throttled)
+			return
+		}
+		if errors.Is(err, domain.ErrEmailNotVerified) {
+			response.Error(ctx, http.StatusForbidden, "Please verify your email before logging in", err.Error())
+			return
+		}
+		if errors.Is(err, domain.ErrAccountSuspended) {
+			response.Error(ctx, http.StatusForbidden, "Your account has been suspended", err.Error())
+			return
+		}
 		if err != nil {
 			response.Error(ctx, http.StatusUnauthorized, "invalid Id or password", err.Error())
 			return
 		}
 
 		response.Success(ctx, http.StatusOK, "Login Successful", domain.LoginResponse{
-			Token: token,
+			Token: tokens.AccessToken,
+			RefreshToken: tokens.RefreshToken,
+			ExpiresIn: tokens.ExpiresIn,
 		})
 		return
 	}
 	response.Error(ctx, http.StatusBadRequest, "Email or Student ID is required", "")
 
-}
\ No newline at end of file
+}
+
+func (h *authHandler) Refresh(ctx *gin.Context) {
+	var req domain.RefreshRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.Error(ctx, http.StatusBadRequest, "refresh_token is required", err.Error())
+		return
+	}
+
+	tokens, err := h.usecase.Refresh(ctx.Request.Context(), req.RefreshToken)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidRefreshToken) ||
+			errors.Is(err, domain.ErrRefreshTokenReused) ||
+			errors.Is(err, domain.ErrSessionRevoked) {
+			response.Error(ctx, http.StatusUnauthorized, "invalid refresh token", err.Error())
+			return
+		}
+		if errors.Is(err, domain.ErrAccountSuspended) {
+			response.Error(ctx, http.StatusForbidden, "Your account has been suspended", err.Error())
+			return
+		}
+		response.Error(ctx, http.StatusInternalServerError, "Server Error", err.Error())
+		return
+	}
+
+	response.Success(ctx, http.StatusOK, "Token refreshed", tokens)
+}
+
+func (h *authHandler) Logout(ctx *gin.Context) {
+	var req domain.RefreshRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.Error(ctx, http.StatusBadRequest, "refresh_token is required", err.Error())
+		return
+	}
+
+	err := h.usecase.Logout(ctx.Request.Context(), req.RefreshToken)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidRefreshToken) {
+			response.Error(ctx, http.StatusUnauthorized, "invalid refresh token", err.Error())
+			return
+		}
+		if errors.Is(err, domain.ErrAccountSuspended) {
+			response.Error(ctx, http.StatusForbidden, "Your account has been suspended", err.Error())
+			return
+		}
+		response.Error(ctx, http.StatusInternalServerError, "Server Error", err.Error())
+		return
+	}
+
+	response.Success(ctx, http.StatusOK, "Logged out", nil)
+}
+
+func (h *authHandler) VerifyEmail(ctx *gin.Context) {
+	token := ctx.Query("token")
+
+	if err := h.usecase.VerifyEmail(ctx.Request.Context(), token); err != nil {
+		if errors.Is(err, domain.ErrInvalidToken) {
+			response.Error(ctx, http.StatusBadRequest, "Verification link is invalid or has expired", err.Error())
+			return
+		}
+		response.Error(ctx, http.StatusInternalServerError, "Server Error", err.Error())
+		return
+	}
+
+	response.Success(ctx, http.StatusOK, "Email verified successfully", nil)
+}
+
+func (h *authHandler) ResendVerification(ctx *gin.Context) {
+	var req domain.ResendVerificationRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.Error(ctx, http.StatusBadRequest, "email is required", err.Error())
+		return
+	}
+
+	if err := h.usecase.ResendVerification(ctx.Request.Context(), req.Email); err != nil {
+		response.Error(ctx, http.StatusInternalServerError, "Server Error", err.Error())
+		return
+	}
+
+	response.Success(ctx, http.StatusOK, "If the account exists and is unverified, a new link has been sent", nil)
+}
+
+func (h *authHandler) ForgotPassword(ctx *gin.Context) {
+	var req domain.ForgotPasswordRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.Error(ctx, http.StatusBadRequest, "email is required", err.Error())
+		return
+	}
+
+	if err := h.usecase.ForgotPassword(ctx.Request.Context(), req.Email); err != nil {
+		response.Error(ctx, http.StatusInternalServerError, "Server Error", err.Error())
+		return
+	}
+
+	response.Success(ctx, http.StatusOK, "If the account exists, a reset link has been sent", nil)
+}
+
+func (h *authHandler) ResetPassword(ctx *gin.Context) {
+	var req domain.ResetPasswordRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.Error(ctx, http.StatusBadRequest, "token and new_password are required", err.Error())
+		return
+	}
+
+	if err := h.usecase.ResetPassword(ctx.Request.Context(), req.Token, req.NewPassword); err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidToken):
+			response.Error(ctx, http.StatusBadRequest, "Reset link is invalid or has expired", err.Error())
+		case errors.Is(err, domain.ErrWeakPassword):
+			response.Error(ctx, http.StatusBadRequest, "Invalid password", err.Error())
+		default:
+			response.Error(ctx, http.StatusInternalServerError, "Server Error", err.Error())
+		}
+		return
+	}
+
+	response.Success(ctx, http.StatusOK, "Password has been reset, please log in again", nil)
+}
+
+func (h *authHandler) ChangePassword(ctx *gin.Context) {
+	var req domain.ChangePasswordRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.Error(ctx, http.StatusBadRequest, "old_password and new_password are required", err.Error())
+		return
+	}
+
+	userID, _, err := currentSession(ctx)
+	if err != nil {
+		response.Error(ctx, http.StatusUnauthorized, "Invalid session", err.Error())
+		return
+	}
+
+	client := domain.ClientInfo{
+		UserAgent: ctx.Request.UserAgent(),
+		IPAddress: ctx.ClientIP(),
+	}
+
+	tokens, err := h.usecase.ChangePassword(ctx.Request.Context(), userID, req.OldPassword, req.NewPassword, client)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrInvalidCredentials):
+			response.Error(ctx, http.StatusUnauthorized, "Current password is incorrect", err.Error())
+		case errors.Is(err, domain.ErrWeakPassword):
+			response.Error(ctx, http.StatusBadRequest, "Invalid password", err.Error())
+		default:
+			response.Error(ctx, http.StatusInternalServerError, "Server Error", err.Error())
+		}
+		return
+	}
+
+	response.Success(ctx, http.StatusOK, "Password changed, other sessions have been signed out", tokens)
+}
+
+func (h *authHandler) EnrollMFA(ctx *gin.Context) {
+	userID, _, err := currentSession(ctx)
+	if err != nil {
+		response.Error(ctx, http.StatusUnauthorized, "Invalid session", err.Error())
+		return
+	}
+
+	enrollment, err := h.usecase.EnrollMFA(ctx.Request.Context(), userID)
+	if err != nil {
+		if errors.Is(err, domain.ErrMFAAlreadyEnabled) {
+			response.Error(ctx, http.StatusConflict, "Two-factor authentication is already enabled", err.Error())
+			return
+		}
+		response.Error(ctx, http.StatusInternalServerError, "Server Error", err.Error())
+		return
+	}
+
+	response.Success(ctx, http.StatusOK, "Scan the code with your authenticator app, then confirm it", enrollment)
+}
+
+func (h *authHandler) ConfirmMFA(ctx *gin.Context) {
+	var req domain.MFACodeRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.Error(ctx, http.StatusBadRequest, "code is required", err.Error())
+		return
+	}
+
+	userID, _, err := currentSession(ctx)
+	if err != nil {
+		response.Error(ctx, http.StatusUnauthorized, "Invalid session", err.Error())
+		return
+	}
+
+	codes, err := h.usecase.ConfirmMFA(ctx.Request.Context(), userID, req.Code)
+	if err != nil {
+		writeMFAError(ctx, err)
+		return
+	}
+
+	response.Success(ctx, http.StatusOK, "Two-factor authentication enabled, store your recovery codes safely", gin.H{
+		"recovery_codes": codes,
+	})
+}
+
+func (h *authHandler) DisableMFA(ctx *gin.Context) {
+	var req domain.MFADisableRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.Error(ctx, http.StatusBadRequest, "code or recovery_code is required", err.Error())
+		return
+	}
+
+	userID, _, err := currentSession(ctx)
+	if err != nil {
+		response.Error(ctx, http.StatusUnauthorized, "Invalid session", err.Error())
+		return
+	}
+
+	if err := h.usecase.DisableMFA(ctx.Request.Context(), userID, req.Code, req.RecoveryCode); err != nil {
+		writeMFAError(ctx, err)
+		return
+	}
+
+	response.Success(ctx, http.StatusOK, "Two-factor authentication disabled", nil)
+}
+
+func (h *authHandler) VerifyMFA(ctx *gin.Context) {
+	var req domain.MFAVerifyRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.Error(ctx, http.StatusBadRequest, "mfa_token is required", err.Error())
+		return
+	}
+
+	client := domain.ClientInfo{
+		DeviceLabel: req.DeviceLabel,
+		UserAgent:   ctx.Request.UserAgent(),
+		IPAddress:   ctx.ClientIP(),
+	}
+
+	user, tokens, err := h.usecase.VerifyMFA(ctx.Request.Context(), req.MFAToken, req.Code, req.RecoveryCode, client)
+	if err != nil {
+		var throttled *domain.ThrottledError
+		if errors.As(err, &throttled) {
+			writeThrottled(ctx, throttled)
+			return
+		}
+		if errors.Is(err, domain.ErrInvalidToken) {
+			response.Error(ctx, http.StatusUnauthorized, "Login attempt expired, please log in again", err.Error())
+			return
+		}
+		writeMFAError(ctx, err)
+		return
+	}
+
+	userResponse := user.ToResponse()
+	response.Success(ctx, http.StatusOK, "Login Successful", domain.LoginResponse{
+		Token: tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+		ExpiresIn: tokens.ExpiresIn,
+		User: &userResponse,
+	})
+}
+
+func (h *authHandler) BeginSSO(ctx *gin.Context) {
+	url, err := h.usecase.BeginSSO(ctx.Request.Context())
+	if err != nil {
+		if errors.Is(err, domain.ErrSSODisabled) {
+			response.Error(ctx, http.StatusNotFound, "Single sign-on is not available", err.Error())
+			return
+		}
+		response.Error(ctx, http.StatusBadGateway, "Identity provider unavailable", err.Error())
+		return
+	}
+
+	ctx.Redirect(http.StatusFound, url)
+}
+
+func (h *authHandler) CompleteSSO(ctx *gin.Context) {
+	if providerErr := ctx.Query("error"); providerErr != "" {
+		response.Error(ctx, http.StatusUnauthorized, "Sign-in was cancelled or refused", providerErr)
+		return
+	}
+
+	client := domain.ClientInfo{
+		DeviceLabel: "Single sign-on",
+		UserAgent:   ctx.Request.UserAgent(),
+		IPAddress:   ctx.ClientIP(),
+	}
+
+	user, tokens, err := h.usecase.CompleteSSO(ctx.Request.Context(), ctx.Query("code"), ctx.Query("state"), client)
+	if err != nil {
+		var mfaRequired *domain.MFARequiredError
+		switch {
+		case errors.As(err, &mfaRequired):
+			writeMFARequired(ctx, mfaRequired)
+		case errors.Is(err, domain.ErrSSODisabled):
+			response.Error(ctx, http.StatusNotFound, "Single sign-on is not available", err.Error())
+		case errors.Is(err, domain.ErrInvalidSSOState):
+			response.Error(ctx, http.StatusBadRequest, "Sign-in request expired, please try again", err.Error())
+		case errors.Is(err, domain.ErrAccountLinkConflict):
+			response.Error(ctx, http.StatusConflict, "Account already exists", err.Error())
+		case errors.Is(err, domain.ErrMissingSSOClaims):
+			response.Error(ctx, http.StatusBadRequest, "Identity provider response incomplete", err.Error())
+		case errors.Is(err, domain.ErrEmailNotVerified):
+			response.Error(ctx, http.StatusForbidden, "Please verify your email before logging in", err.Error())
+		case errors.Is(err, domain.ErrAccountSuspended):
+			response.Error(ctx, http.StatusForbidden, "Your account has been suspended", err.Error())
+		default:
+			response.Error(ctx, http.StatusUnauthorized, "Single sign-on failed", err.Error())
+		}
+		return
+	}
+
+	userResponse := user.ToResponse()
+	response.Success(ctx, http.StatusOK, "Login Successful", domain.LoginResponse{
+		Token: tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+		ExpiresIn: tokens.ExpiresIn,
+		User: &userResponse,
+	})
+}
+
+func writeMFAError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrInvalidMFACode):
+		response.Error(ctx, http.StatusUnauthorized, "Invalid two-factor code", err.Error())
+	case errors.Is(err, domain.ErrMFANotEnrolled):
+		response.Error(ctx, http.StatusBadRequest, "Two-factor authentication is not set up", err.Error())
+	case errors.Is(err, domain.ErrMFAAlreadyEnabled):
+		response.Error(ctx, http.StatusConflict, "Two-factor authentication is already enabled", err.Error())
+	case errors.Is(err, domain.ErrAccountSuspended):
+		response.Error(ctx, http.StatusForbidden, "Your account has been suspended", err.Error())
+	default:
+		response.Error(ctx, http.StatusInternalServerError, "Server Error", err.Error())
+	}
+}
+
+func writeMFARequired(ctx *gin.Context, err *domain.MFARequiredError) {
+	response.Success(ctx, http.StatusOK, "Two-factor authentication required", domain.LoginResponse{
+		MFARequired: true,
+		MFAToken: err.Challenge,
+		ExpiresIn: err.ExpiresIn,
+	})
+}
+
+func (h *authHandler) ListSessions(ctx *gin.Context) {
+	userID, sessionID, err := currentSession(ctx)
+	if err != nil {
+		response.Error(ctx, http.StatusUnauthorized, "Invalid session", err.Error())
+		return
+	}
+
+	sessions, err := h.usecase.ListSessions(ctx.Request.Context(), userID, sessionID)
+	if err != nil {
+		response.Error(ctx, http.StatusInternalServerError, "Server Error", err.Error())
+		return
+	}
+
+	response.Success(ctx, http.StatusOK, "Sessions fetched successfully", sessions)
+}
+
+func (h *authHandler) EndSession(ctx *gin.Context) {
+	userID, _, err := currentSession(ctx)
+	if err != nil {
+		response.Error(ctx, http.StatusUnauthorized, "Invalid session", err.Error())
+		return
+	}
+
+	sessionID, err := uuid.Parse(ctx.Param("session_id"))
+	if err != nil {
+		response.Error(ctx, http.StatusBadRequest, "Invalid session ID", err.Error())
+		return
+	}
+
+	if err := h.usecase.EndSession(ctx.Request.Context(), userID, sessionID); err != nil {
+		if errors.Is(err, domain.ErrSessionNotFound) {
+			response.Error(ctx, http.StatusNotFound, "Session not found", err.Error())
+			return
+		}
+		response.Error(ctx, http.StatusInternalServerError, "Server Error", err.Error())
+		return
+	}
+
+	response.Success(ctx, http.StatusOK, "Session ended", nil)
+}
+
+func (h *authHandler) EndOtherSessions(ctx *gin.Context) {
+	userID, sessionID, err := currentSession(ctx)
+	if err != nil {
+		response.Error(ctx, http.StatusUnauthorized, "Invalid session", err.Error())
+		return
+	}
+
+	if err := h.usecase.EndOtherSessions(ctx.Request.Context(), userID, sessionID); err != nil {
+		response.Error(ctx, http.StatusInternalServerError, "Server Error", err.Error())
+		return
+	}
+
+	response.Success(ctx, http.StatusOK, "Other sessions ended", nil)
+}
+
+func writeThrottled(ctx *gin.Context, err *domain.ThrottledError) {
+	code := http.StatusTooManyRequests
+	if err.Locked {
+		code = http.StatusLocked
+	}
+	response.ErrorRetryAfter(ctx, code, "Too many failed login attempts", err.Error(), err.RetryAfter)
+}
+
+func currentSession(ctx *gin.Context) (uuid.UUID, uuid.UUID, error) {
+	userID, err := uuid.Parse(ctx.GetString("user_id"))
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+
+	sessionID, err := uuid.Parse(ctx.GetString("session_id"))
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+
+	return userID, sessionID, nil
+}
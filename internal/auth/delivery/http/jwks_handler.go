@@ -0,0 +1,18 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/Ramsi97/edu-social-backend/pkg/auth"
+	"github.com/gin-gonic/gin"
+)
+
+// NewJWKSHandler publishes the public half of the signing keys so other
+// services can verify access tokens offline. The document is served bare,
+// not wrapped in the usual response envelope, as JWKS consumers expect.
+func NewJWKSHandler(r gin.IRouter, keys *auth.KeySet) {
+	r.GET("/.well-known/jwks.json", func(c *gin.Context) {
+		c.Header("Cache-Control", "public, max-age=300")
+		c.JSON(http.StatusOK, keys.JWKS())
+	})
+}
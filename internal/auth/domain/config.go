@@ -0,0 +1,21 @@
+package domain
+
+import "github.com/Ramsi97/edu-social-backend/pkg/auth"
+
+// AuthConfig holds the settings the auth use case needs from the environment.
+type AuthConfig struct {
+	// AppBaseURL is prepended to links sent by email, e.g. https://edu.example.com
+	AppBaseURL string
+
+	// TOTPIssuer is the account name authenticator apps show for our codes.
+	TOTPIssuer string
+
+	// TOTPSecrets encrypts TOTP secrets before they are stored, so reading
+	// the database alone does not give away anyone's second factor.
+	TOTPSecrets *auth.SecretBox
+
+	// AccountThrottle and IPThrottle limit failed logins per account
+	// identifier and per client IP respectively.
+	AccountThrottle LoginThrottleConfig
+	IPThrottle      LoginThrottleConfig
+}
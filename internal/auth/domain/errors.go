@@ -2,4 +2,26 @@ package domain
 
 import "errors"
 
-var ErrUserNotFound = errors.New("user not found")
\ No newline at end of file
+var (
+	ErrUserNotFound        = errors.New("user not found")
+	ErrUserAlreadyExists   = errors.New("user already exists")
+	ErrSessionNotFound     = errors.New("session not found")
+	ErrSessionRevoked      = errors.New("session has been revoked")
+	ErrInvalidRefreshToken = errors.New("invalid refresh token")
+	ErrRefreshTokenReused  = errors.New("refresh token reuse detected")
+	ErrEmailNotVerified    = errors.New("email address is not verified")
+	ErrInvalidToken        = errors.New("invalid or expired token")
+	ErrTooManyRequests     = errors.New("too many requests, please try again later")
+	ErrInvalidCredentials  = errors.New("invalid credentials")
+	ErrWeakPassword        = errors.New("password must be at least 8 characters long")
+	ErrMFANotEnrolled      = errors.New("two-factor authentication is not set up")
+	ErrMFAAlreadyEnabled   = errors.New("two-factor authentication is already enabled")
+	ErrInvalidMFACode      = errors.New("invalid two-factor code")
+	ErrSSODisabled         = errors.New("single sign-on is not configured")
+	ErrInvalidSSOState     = errors.New("sign-in request is invalid or has expired")
+	ErrAccountLinkConflict = errors.New("an account with this email or student ID already exists, sign in with your password first")
+	ErrMissingSSOClaims    = errors.New("identity provider did not return the required email and student ID")
+	ErrAccountSuspended    = errors.New("account has been suspended")
+	ErrInvalidRole         = errors.New("invalid role")
+	ErrCannotModerateSelf  = errors.New("administrators cannot suspend or change the role of their own account")
+)
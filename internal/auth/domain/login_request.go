@@ -4,4 +4,5 @@ type LoginRequest struct {
 	Email *string `json:"email"`
 	StudentID *string `json:"student_id"`
 	Password string `json:"password"`
+	DeviceLabel string `json:"device_label"`
 }
\ No newline at end of file
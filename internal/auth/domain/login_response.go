@@ -1,6 +1,10 @@
 package domain
 
 type LoginResponse struct{
-	Token string `json:"token"`
-	User UserResponse `json:"user"`
-}
\ No newline at end of file
+	Token string `json:"token,omitempty"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	ExpiresIn int64 `json:"expires_in"`
+	User *UserResponse `json:"user,omitempty"`
+	MFARequired bool `json:"mfa_required,omitempty"`
+	MFAToken string `json:"mfa_token,omitempty"`
+}
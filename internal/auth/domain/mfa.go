@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MFA is a user's TOTP enrollment. It only protects logins once EnabledAt is
+// set, which happens after the user proves the authenticator works.
+type MFA struct {
+	UserID       uuid.UUID
+	Secret       string
+	LastUsedStep *int64
+	EnabledAt    *time.Time
+	CreatedAt    time.Time
+}
+
+func (m *MFA) Enabled() bool {
+	return m != nil && m.EnabledAt != nil
+}
+
+type MFAEnrollment struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+type MFACodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// MFADisableRequest accepts either a current TOTP code or a recovery code.
+type MFADisableRequest struct {
+	Code         string `json:"code"`
+	RecoveryCode string `json:"recovery_code"`
+}
+
+type MFAVerifyRequest struct {
+	MFAToken     string `json:"mfa_token" binding:"required"`
+	Code         string `json:"code"`
+	RecoveryCode string `json:"recovery_code"`
+	DeviceLabel  string `json:"device_label"`
+}
+
+// MFARequiredError is returned by the login methods when the password was
+// correct but the account has two-factor authentication enabled. The client
+// has to exchange Challenge and a code for tokens.
+type MFARequiredError struct {
+	Challenge string
+	ExpiresIn int64
+}
+
+func (e *MFARequiredError) Error() string {
+	return "two-factor authentication required"
+}
@@ -0,0 +1,31 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Purposes a one-time token can be issued for. A token is only accepted for
+// the purpose it was created with.
+const (
+	TokenPurposeEmailVerification = "email_verification"
+	TokenPurposePasswordReset     = "password_reset"
+	TokenPurposeMFAChallenge      = "mfa_challenge"
+)
+
+// OneTimeToken is a single-use, expiring token mailed to a user. Only the
+// hash of the token is stored.
+type OneTimeToken struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	Purpose   string
+	TokenHash string
+	ExpiresAt time.Time
+	CreatedAt time.Time
+	UsedAt    *time.Time
+}
+
+type ResendVerificationRequest struct {
+	Email string `json:"email" binding:"required"`
+}
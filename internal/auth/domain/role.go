@@ -0,0 +1,48 @@
+package domain
+
+import "time"
+
+// Role is a platform-wide role. It is stored on the user and copied into
+// access tokens; the database stays authoritative on every request.
+type Role string
+
+const (
+	RoleStudent   Role = "student"
+	RoleStaff     Role = "staff"
+	RoleModerator Role = "moderator"
+	RoleAdmin     Role = "admin"
+)
+
+func (r Role) Valid() bool {
+	switch r {
+	case RoleStudent, RoleStaff, RoleModerator, RoleAdmin:
+		return true
+	}
+	return false
+}
+
+// UserFilter narrows the admin user listing. Users are returned newest
+// first; Before is the created_at of the last user of the previous page.
+type UserFilter struct {
+	Query     string
+	Role      Role
+	Suspended *bool
+	Before    *time.Time
+	Limit     int
+}
+
+// AdminUserResponse is the user as shown to administrators, including the
+// moderation state hidden from everybody else.
+type AdminUserResponse struct {
+	UserResponse
+	SuspendedAt      *time.Time `json:"suspended_at"`
+	SuspensionReason *string    `json:"suspension_reason"`
+}
+
+type SuspendUserRequest struct {
+	Reason string `json:"reason"`
+}
+
+type AssignRoleRequest struct {
+	Role Role `json:"role" binding:"required"`
+}
@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/Ramsi97/edu-social-backend/pkg/oidc"
+	"github.com/google/uuid"
+)
+
+// IdentityProvider is an external OpenID Connect provider users can sign in
+// with, typically the university's campus identity system.
+type IdentityProvider interface {
+	Issuer() string
+	AuthCodeURL(ctx context.Context, state, nonce, codeChallenge string) (string, error)
+	Exchange(ctx context.Context, code, codeVerifier, nonce string) (*oidc.Identity, error)
+}
+
+// SSOState is the server-side half of an authorization request, looked up by
+// the hash of the state parameter when the provider redirects back.
+type SSOState struct {
+	StateHash    string
+	Nonce        string
+	CodeVerifier string
+	ExpiresAt    time.Time
+}
+
+// ExternalIdentity links a provider account (issuer + subject) to a user.
+type ExternalIdentity struct {
+	Issuer    string
+	Subject   string
+	UserID    uuid.UUID
+	Email     string
+	CreatedAt time.Time
+}
@@ -3,6 +3,8 @@ package domain
 import (
 	"context"
 	"time"
+
+	"github.com/Ramsi97/edu-social-backend/pkg/auth"
 	"github.com/google/uuid"
 )
 
@@ -10,31 +12,120 @@ type User struct {
 	ID        uuid.UUID `json:"id"`
 	FirstName string `json:"first_name"`
 	LastName string  `json:"last_name"`
+	// Username is the handle the user claimed for @mentions, if any.
+	Username *string `json:"username"`
 	StudentID string `json:"student_id"`
 	Email     string `json:"email"`
 	Password  string `json:"password"`
 	JoinedYear string `json:"joined_year"`
 	ProfilePicture *string `json:"profile_picture"`
 	Gender string `json:"gender"`
+	IsPrivate bool `json:"is_private"`
+	EmailVerifiedAt *time.Time `json:"email_verified_at"`
+	Role Role `json:"role"`
+	SuspendedAt *time.Time `json:"suspended_at"`
+	SuspensionReason *string `json:"suspension_reason"`
 	CreatedAt time.Time `json:"created_at"`
 }
 
+func (u *User) EmailVerified() bool {
+	return u.EmailVerifiedAt != nil
+}
+
+func (u *User) Suspended() bool {
+	return u.SuspendedAt != nil
+}
+
 type UserResponse struct {
     ID             string  `json:"id"`
     FirstName      string  `json:"first_name"`
     LastName       string  `json:"last_name"`
-    StudentID      string  `json:"student_id"`
-    Email          string  `json:"email"`
+    Username       *string `json:"username"`
+    StudentID      string  `json:"student_id,omitempty"`
+    Email          string  `json:"email,omitempty"`
     JoinedYear     string  `json:"joined_year"`
     ProfilePicture *string `json:"profile_picture"`
     Gender         string  `json:"gender"`
+    IsPrivate      bool    `json:"is_private"`
+    EmailVerified  bool    `json:"email_verified"`
+    Role           Role    `json:"role"`
     CreatedAt      string  `json:"created_at"`
 }
 
+func (u *User) ToResponse() UserResponse {
+	return UserResponse{
+		ID:             u.ID.String(),
+		FirstName:      u.FirstName,
+		LastName:       u.LastName,
+		Username:       u.Username,
+		StudentID:      u.StudentID,
+		Email:          u.Email,
+		JoinedYear:     u.JoinedYear,
+		ProfilePicture: u.ProfilePicture,
+		Gender:         u.Gender,
+		IsPrivate:      u.IsPrivate,
+		EmailVerified:  u.EmailVerified(),
+		Role:           u.Role,
+		CreatedAt:      u.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+// ToPublicResponse is the profile other users get to see: no email address
+// or student ID.
+func (u *User) ToPublicResponse() UserResponse {
+	res := u.ToResponse()
+	res.StudentID = ""
+	res.Email = ""
+	return res
+}
 
 
+
+// Authenticator resolves an access token to its claims, rejecting tokens
+// whose session has been revoked.
+type Authenticator interface {
+	Authenticate(ctx context.Context, accessToken string) (*auth.Claims, error)
+}
+
 type AuthUseCase interface {
+	Authenticator
 	Register(ctx context.Context, user *RegisterRequest) error
-	LoginWithEmail(ctx context.Context, email string, password string) (*User, string, error)
-	LoginWithId(ctx context.Context, studentId string, password string) (string, error)
+	LoginWithEmail(ctx context.Context, email string, password string, client ClientInfo) (*User, *TokenPair, error)
+	LoginWithId(ctx context.Context, studentId string, password string, client ClientInfo) (*TokenPair, error)
+	Refresh(ctx context.Context, refreshToken string) (*TokenPair, error)
+	Logout(ctx context.Context, refreshToken string) error
+	VerifyEmail(ctx context.Context, token string) error
+	ResendVerification(ctx context.Context, email string) error
+	ForgotPassword(ctx context.Context, email string) error
+	ResetPassword(ctx context.Context, token, newPassword string) error
+	// ChangePassword ends every session of the user and returns a fresh
+	// token pair for the device that made the change.
+	ChangePassword(ctx context.Context, userID uuid.UUID, oldPassword, newPassword string, client ClientInfo) (*TokenPair, error)
+
+	EnrollMFA(ctx context.Context, userID uuid.UUID) (*MFAEnrollment, error)
+	// ConfirmMFA turns two-factor authentication on and returns the
+	// recovery codes, which are never shown again.
+	ConfirmMFA(ctx context.Context, userID uuid.UUID, code string) ([]string, error)
+	DisableMFA(ctx context.Context, userID uuid.UUID, code, recoveryCode string) error
+	VerifyMFA(ctx context.Context, challenge, code, recoveryCode string, client ClientInfo) (*User, *TokenPair, error)
+	// SealMFASecrets encrypts TOTP secrets stored before encryption at rest
+	// was introduced. It is run once at startup.
+	SealMFASecrets(ctx context.Context) error
+
+	// BeginSSO starts an OIDC login and returns the provider URL to visit.
+	BeginSSO(ctx context.Context) (string, error)
+	CompleteSSO(ctx context.Context, code, state string, client ClientInfo) (*User, *TokenPair, error)
+
+	ListSessions(ctx context.Context, userID, currentSessionID uuid.UUID) ([]Session, error)
+	EndSession(ctx context.Context, userID, sessionID uuid.UUID) error
+	EndOtherSessions(ctx context.Context, userID, currentSessionID uuid.UUID) error
+	// OnSessionsRevoked registers a callback fired after sessions are
+	// terminated, so live connections opened with them can be dropped.
+	OnSessionsRevoked(listener func(sessionIDs ...uuid.UUID))
+
+	ListUsers(ctx context.Context, filter UserFilter) ([]User, error)
+	// SuspendUser blocks the account and ends all of its sessions.
+	SuspendUser(ctx context.Context, adminID, userID uuid.UUID, reason string) error
+	UnsuspendUser(ctx context.Context, userID uuid.UUID) error
+	AssignRole(ctx context.Context, adminID, userID uuid.UUID, role Role) error
 }
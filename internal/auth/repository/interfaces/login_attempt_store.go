@@ -0,0 +1,20 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"github.com/Ramsi97/edu-social-backend/internal/auth/domain"
+)
+
+type LoginAttemptStore interface {
+	// Get returns the attempts recorded for key, or a zero value when there
+	// are none.
+	Get(ctx context.Context, key string) (*domain.LoginAttempts, error)
+	// RecordFailure adds a failure for key and returns the updated state.
+	// The count restarts when the previous failure is older than window or
+	// an earlier lock has run out.
+	RecordFailure(ctx context.Context, key string, window time.Duration) (*domain.LoginAttempts, error)
+	Lock(ctx context.Context, key string, until time.Time) error
+	Reset(ctx context.Context, key string) error
+}
@@ -0,0 +1,32 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/Ramsi97/edu-social-backend/internal/auth/domain"
+	"github.com/google/uuid"
+)
+
+type MFARepository interface {
+	// Get returns domain.ErrMFANotEnrolled when the user never enrolled.
+	Get(ctx context.Context, userID uuid.UUID) (*domain.MFA, error)
+	// SaveSecret stores a new, not yet enabled secret for the user,
+	// replacing an earlier unconfirmed one.
+	SaveSecret(ctx context.Context, userID uuid.UUID, secret string) error
+	// ListPlaintextSecrets returns the enrollments whose secret is not yet
+	// encrypted.
+	ListPlaintextSecrets(ctx context.Context) ([]domain.MFA, error)
+	// ReplaceSecret swaps the stored secret if it still equals old, leaving
+	// the enrollment state alone.
+	ReplaceSecret(ctx context.Context, userID uuid.UUID, old, new string) error
+	Enable(ctx context.Context, userID uuid.UUID) error
+	Delete(ctx context.Context, userID uuid.UUID) error
+	// UseStep records step as the last accepted TOTP step. It returns false
+	// when an equal or later step was already used, i.e. a replayed code.
+	UseStep(ctx context.Context, userID uuid.UUID, step int64) (bool, error)
+
+	ReplaceRecoveryCodes(ctx context.Context, userID uuid.UUID, codeHashes []string) error
+	// ConsumeRecoveryCode marks a matching unused code as used and reports
+	// whether one was found.
+	ConsumeRecoveryCode(ctx context.Context, userID uuid.UUID, codeHash string) (bool, error)
+}
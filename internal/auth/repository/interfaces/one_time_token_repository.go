@@ -0,0 +1,24 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"github.com/Ramsi97/edu-social-backend/internal/auth/domain"
+	"github.com/google/uuid"
+)
+
+type OneTimeTokenRepository interface {
+	Create(ctx context.Context, token *domain.OneTimeToken) error
+	// Consume atomically marks an unused, unexpired token as used and
+	// returns it. Anything else yields domain.ErrInvalidToken.
+	Consume(ctx context.Context, purpose, tokenHash string) (*domain.OneTimeToken, error)
+	// Find returns an unused, unexpired token without consuming it.
+	Find(ctx context.Context, purpose, tokenHash string) (*domain.OneTimeToken, error)
+	CountSince(ctx context.Context, userID uuid.UUID, purpose string, since time.Time) (int, error)
+	LatestCreatedAt(ctx context.Context, userID uuid.UUID, purpose string) (*time.Time, error)
+	// Invalidate marks every outstanding token of the purpose for the user
+	// as used, so only the newest one issued can be redeemed.
+	Invalidate(ctx context.Context, userID uuid.UUID, purpose string) error
+	DeleteExpired(ctx context.Context) error
+}
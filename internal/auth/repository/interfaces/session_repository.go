@@ -0,0 +1,26 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/Ramsi97/edu-social-backend/internal/auth/domain"
+	"github.com/google/uuid"
+)
+
+type SessionRepository interface {
+	CreateSession(ctx context.Context, session *domain.Session) error
+	GetSession(ctx context.Context, sessionID uuid.UUID) (*domain.Session, error)
+	ListActiveSessions(ctx context.Context, userID uuid.UUID) ([]domain.Session, error)
+	TouchSession(ctx context.Context, sessionID uuid.UUID) error
+	RevokeSession(ctx context.Context, sessionID uuid.UUID) error
+	// RevokeOtherSessions revokes every active session of the user except
+	// keepID and returns the IDs that were revoked.
+	RevokeOtherSessions(ctx context.Context, userID, keepID uuid.UUID) ([]uuid.UUID, error)
+	RevokeAllSessions(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error)
+
+	CreateRefreshToken(ctx context.Context, token *domain.RefreshToken) error
+	// ConsumeRefreshToken atomically marks a token as used and returns it.
+	// A token that was already used is returned with ErrRefreshTokenReused.
+	ConsumeRefreshToken(ctx context.Context, tokenHash string) (*domain.RefreshToken, error)
+	FindRefreshToken(ctx context.Context, tokenHash string) (*domain.RefreshToken, error)
+}
@@ -0,0 +1,19 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/Ramsi97/edu-social-backend/internal/auth/domain"
+)
+
+type SSORepository interface {
+	SaveState(ctx context.Context, state *domain.SSOState) error
+	// ConsumeState deletes and returns an unexpired state, or returns
+	// domain.ErrInvalidSSOState.
+	ConsumeState(ctx context.Context, stateHash string) (*domain.SSOState, error)
+
+	// FindIdentity returns domain.ErrUserNotFound when the provider account
+	// isn't linked yet.
+	FindIdentity(ctx context.Context, issuer, subject string) (*domain.ExternalIdentity, error)
+	LinkIdentity(ctx context.Context, identity *domain.ExternalIdentity) error
+}
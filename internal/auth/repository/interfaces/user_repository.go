@@ -3,6 +3,7 @@ package interfaces
 import (
 	"context"
 	"github.com/Ramsi97/edu-social-backend/internal/auth/domain"
+	"github.com/google/uuid"
 )
 
 
@@ -10,4 +11,11 @@ type UserRepository interface {
 	Create (ctx context.Context, user *domain.User) error
 	FindByEmail(ctx context.Context, email string) (*domain.User, error)
 	FindByStudentId(ctx context.Context, studentId string)(*domain.User, error)
+	FindByID(ctx context.Context, userID uuid.UUID) (*domain.User, error)
+	MarkEmailVerified(ctx context.Context, userID uuid.UUID) error
+	UpdatePassword(ctx context.Context, userID uuid.UUID, passwordHash string) error
+	List(ctx context.Context, filter domain.UserFilter) ([]domain.User, error)
+	Suspend(ctx context.Context, userID uuid.UUID, reason *string) error
+	Unsuspend(ctx context.Context, userID uuid.UUID) error
+	UpdateRole(ctx context.Context, userID uuid.UUID, role domain.Role) error
 }
\ No newline at end of file
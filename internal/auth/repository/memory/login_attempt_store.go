@@ -0,0 +1,75 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/Ramsi97/edu-social-backend/internal/auth/domain"
+	"github.com/Ramsi97/edu-social-backend/internal/auth/repository/interfaces"
+)
+
+type loginAttemptStore struct {
+	mu       sync.Mutex
+	attempts map[string]domain.LoginAttempts
+	now      func() time.Time
+}
+
+// NewLoginAttemptStore returns a process-local store, meant for tests and
+// single-instance development setups.
+func NewLoginAttemptStore() interfaces.LoginAttemptStore {
+	return &loginAttemptStore{
+		attempts: make(map[string]domain.LoginAttempts),
+		now:      time.Now,
+	}
+}
+
+func (s *loginAttemptStore) Get(ctx context.Context, key string) (*domain.LoginAttempts, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	a, ok := s.attempts[key]
+	if !ok {
+		return &domain.LoginAttempts{Key: key}, nil
+	}
+	return &a, nil
+}
+
+func (s *loginAttemptStore) RecordFailure(ctx context.Context, key string, window time.Duration) (*domain.LoginAttempts, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	a, ok := s.attempts[key]
+	lockExpired := a.LockedUntil != nil && !now.Before(*a.LockedUntil)
+
+	if !ok || now.Sub(a.LastFailureAt) > window || lockExpired {
+		a = domain.LoginAttempts{Key: key}
+	}
+
+	a.Failures++
+	a.LastFailureAt = now
+	s.attempts[key] = a
+
+	return &a, nil
+}
+
+func (s *loginAttemptStore) Lock(ctx context.Context, key string, until time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	a := s.attempts[key]
+	a.Key = key
+	a.LockedUntil = &until
+	s.attempts[key] = a
+
+	return nil
+}
+
+func (s *loginAttemptStore) Reset(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.attempts, key)
+	return nil
+}
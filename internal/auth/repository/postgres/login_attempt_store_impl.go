@@ -0,0 +1,93 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/Ramsi97/edu-social-backend/internal/auth/domain"
+	"github.com/Ramsi97/edu-social-backend/internal/auth/repository/interfaces"
+)
+
+type loginAttemptStore struct {
+	db *sql.DB
+}
+
+func NewLoginAttemptStore(db *sql.DB) interfaces.LoginAttemptStore {
+	return &loginAttemptStore{
+		db: db,
+	}
+}
+
+func (s *loginAttemptStore) Get(ctx context.Context, key string) (*domain.LoginAttempts, error) {
+	query := `
+		SELECT key, failures, last_failure_at, locked_until
+		FROM login_attempts
+		WHERE key = $1
+	`
+
+	var a domain.LoginAttempts
+	err := s.db.QueryRowContext(ctx, query, key).Scan(
+		&a.Key,
+		&a.Failures,
+		&a.LastFailureAt,
+		&a.LockedUntil,
+	)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return &domain.LoginAttempts{Key: key}, nil
+		}
+		return nil, err
+	}
+
+	return &a, nil
+}
+
+func (s *loginAttemptStore) RecordFailure(ctx context.Context, key string, window time.Duration) (*domain.LoginAttempts, error) {
+	query := `
+		INSERT INTO login_attempts (key, failures, last_failure_at)
+		VALUES ($1, 1, NOW())
+		ON CONFLICT (key) DO UPDATE SET
+			failures = CASE
+				WHEN login_attempts.last_failure_at < NOW() - make_interval(secs => $2)
+					OR login_attempts.locked_until <= NOW()
+				THEN 1
+				ELSE login_attempts.failures + 1
+			END,
+			locked_until = CASE
+				WHEN login_attempts.locked_until <= NOW() THEN NULL
+				ELSE login_attempts.locked_until
+			END,
+			last_failure_at = NOW()
+		RETURNING key, failures, last_failure_at, locked_until
+	`
+
+	var a domain.LoginAttempts
+	err := s.db.QueryRowContext(ctx, query, key, window.Seconds()).Scan(
+		&a.Key,
+		&a.Failures,
+		&a.LastFailureAt,
+		&a.LockedUntil,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &a, nil
+}
+
+func (s *loginAttemptStore) Lock(ctx context.Context, key string, until time.Time) error {
+	query := `UPDATE login_attempts SET locked_until = $2 WHERE key = $1`
+
+	_, err := s.db.ExecContext(ctx, query, key, until)
+	return err
+}
+
+func (s *loginAttemptStore) Reset(ctx context.Context, key string) error {
+	query := `DELETE FROM login_attempts WHERE key = $1`
+
+	_, err := s.db.ExecContext(ctx, query, key)
+	return err
+}
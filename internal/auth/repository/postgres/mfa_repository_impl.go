@@ -0,0 +1,189 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/Ramsi97/edu-social-backend/internal/auth/domain"
+	"github.com/Ramsi97/edu-social-backend/internal/auth/repository/interfaces"
+	"github.com/google/uuid"
+)
+
+type mfaRepository struct {
+	db *sql.DB
+}
+
+func NewMFARepository(db *sql.DB) interfaces.MFARepository {
+	return &mfaRepository{
+		db: db,
+	}
+}
+
+func (r *mfaRepository) Get(ctx context.Context, userID uuid.UUID) (*domain.MFA, error) {
+	query := `
+		SELECT user_id, secret, last_used_step, enabled_at, created_at
+		FROM user_mfa
+		WHERE user_id = $1
+	`
+
+	var mfa domain.MFA
+	err := r.db.QueryRowContext(ctx, query, userID).Scan(
+		&mfa.UserID,
+		&mfa.Secret,
+		&mfa.LastUsedStep,
+		&mfa.EnabledAt,
+		&mfa.CreatedAt,
+	)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrMFANotEnrolled
+		}
+		return nil, err
+	}
+
+	return &mfa, nil
+}
+
+func (r *mfaRepository) SaveSecret(ctx context.Context, userID uuid.UUID, secret string) error {
+	query := `
+		INSERT INTO user_mfa (user_id, secret, created_at)
+		VALUES ($1, $2, NOW())
+		ON CONFLICT (user_id) DO UPDATE
+		SET secret = EXCLUDED.secret,
+			last_used_step = NULL,
+			created_at = NOW()
+		WHERE user_mfa.enabled_at IS NULL
+	`
+
+	res, err := r.db.ExecContext(ctx, query, userID, secret)
+	if err != nil {
+		return err
+	}
+
+	rows, _ := res.RowsAffected()
+	if rows == 0 {
+		return domain.ErrMFAAlreadyEnabled
+	}
+	return nil
+}
+
+func (r *mfaRepository) ListPlaintextSecrets(ctx context.Context) ([]domain.MFA, error) {
+	query := `
+		SELECT user_id, secret, last_used_step, enabled_at, created_at
+		FROM user_mfa
+		WHERE secret NOT LIKE 'v1:%'
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []domain.MFA
+	for rows.Next() {
+		var mfa domain.MFA
+		if err := rows.Scan(
+			&mfa.UserID,
+			&mfa.Secret,
+			&mfa.LastUsedStep,
+			&mfa.EnabledAt,
+			&mfa.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		list = append(list, mfa)
+	}
+
+	return list, rows.Err()
+}
+
+func (r *mfaRepository) ReplaceSecret(ctx context.Context, userID uuid.UUID, old, new string) error {
+	query := `UPDATE user_mfa SET secret = $3 WHERE user_id = $1 AND secret = $2`
+
+	_, err := r.db.ExecContext(ctx, query, userID, old, new)
+	return err
+}
+
+func (r *mfaRepository) Enable(ctx context.Context, userID uuid.UUID) error {
+	query := `UPDATE user_mfa SET enabled_at = NOW() WHERE user_id = $1`
+
+	_, err := r.db.ExecContext(ctx, query, userID)
+	return err
+}
+
+func (r *mfaRepository) Delete(ctx context.Context, userID uuid.UUID) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM mfa_recovery_codes WHERE user_id = $1`, userID); err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM user_mfa WHERE user_id = $1`, userID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *mfaRepository) UseStep(ctx context.Context, userID uuid.UUID, step int64) (bool, error) {
+	query := `
+		UPDATE user_mfa
+		SET last_used_step = $2
+		WHERE user_id = $1 AND (last_used_step IS NULL OR last_used_step < $2)
+	`
+
+	res, err := r.db.ExecContext(ctx, query, userID, step)
+	if err != nil {
+		return false, err
+	}
+
+	rows, _ := res.RowsAffected()
+	return rows > 0, nil
+}
+
+func (r *mfaRepository) ReplaceRecoveryCodes(ctx context.Context, userID uuid.UUID, codeHashes []string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM mfa_recovery_codes WHERE user_id = $1`, userID); err != nil {
+		return err
+	}
+
+	for _, hash := range codeHashes {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO mfa_recovery_codes (user_id, code_hash, created_at)
+			VALUES ($1, $2, NOW())
+		`, userID, hash)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *mfaRepository) ConsumeRecoveryCode(ctx context.Context, userID uuid.UUID, codeHash string) (bool, error) {
+	query := `
+		UPDATE mfa_recovery_codes
+		SET used_at = NOW()
+		WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL
+	`
+
+	res, err := r.db.ExecContext(ctx, query, userID, codeHash)
+	if err != nil {
+		return false, err
+	}
+
+	rows, _ := res.RowsAffected()
+	return rows > 0, nil
+}
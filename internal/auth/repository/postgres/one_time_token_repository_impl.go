@@ -0,0 +1,153 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/Ramsi97/edu-social-backend/internal/auth/domain"
+	"github.com/Ramsi97/edu-social-backend/internal/auth/repository/interfaces"
+	"github.com/google/uuid"
+)
+
+type oneTimeTokenRepository struct {
+	db *sql.DB
+}
+
+func NewOneTimeTokenRepository(db *sql.DB) interfaces.OneTimeTokenRepository {
+	return &oneTimeTokenRepository{
+		db: db,
+	}
+}
+
+func (r *oneTimeTokenRepository) Create(ctx context.Context, token *domain.OneTimeToken) error {
+	newID, err := uuid.NewV7()
+	if err != nil {
+		return err
+	}
+	token.ID = newID
+	token.CreatedAt = time.Now().UTC()
+
+	query := `
+		INSERT INTO one_time_tokens (id, user_id, purpose, token_hash, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	_, err = r.db.ExecContext(
+		ctx,
+		query,
+		token.ID,
+		token.UserID,
+		token.Purpose,
+		token.TokenHash,
+		token.ExpiresAt,
+		token.CreatedAt,
+	)
+	return err
+}
+
+func (r *oneTimeTokenRepository) Consume(ctx context.Context, purpose, tokenHash string) (*domain.OneTimeToken, error) {
+	query := `
+		UPDATE one_time_tokens
+		SET used_at = NOW()
+		WHERE token_hash = $1
+			AND purpose = $2
+			AND used_at IS NULL
+			AND expires_at > NOW()
+		RETURNING id, user_id, purpose, token_hash, expires_at, created_at, used_at
+	`
+
+	var token domain.OneTimeToken
+	err := r.db.QueryRowContext(ctx, query, tokenHash, purpose).Scan(
+		&token.ID,
+		&token.UserID,
+		&token.Purpose,
+		&token.TokenHash,
+		&token.ExpiresAt,
+		&token.CreatedAt,
+		&token.UsedAt,
+	)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrInvalidToken
+		}
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+func (r *oneTimeTokenRepository) Find(ctx context.Context, purpose, tokenHash string) (*domain.OneTimeToken, error) {
+	query := `
+		SELECT id, user_id, purpose, token_hash, expires_at, created_at, used_at
+		FROM one_time_tokens
+		WHERE token_hash = $1
+			AND purpose = $2
+			AND used_at IS NULL
+			AND expires_at > NOW()
+	`
+
+	var token domain.OneTimeToken
+	err := r.db.QueryRowContext(ctx, query, tokenHash, purpose).Scan(
+		&token.ID,
+		&token.UserID,
+		&token.Purpose,
+		&token.TokenHash,
+		&token.ExpiresAt,
+		&token.CreatedAt,
+		&token.UsedAt,
+	)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrInvalidToken
+		}
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+func (r *oneTimeTokenRepository) CountSince(ctx context.Context, userID uuid.UUID, purpose string, since time.Time) (int, error) {
+	query := `
+		SELECT COUNT(*)
+		FROM one_time_tokens
+		WHERE user_id = $1 AND purpose = $2 AND created_at >= $3
+	`
+
+	var count int
+	err := r.db.QueryRowContext(ctx, query, userID, purpose, since).Scan(&count)
+	return count, err
+}
+
+func (r *oneTimeTokenRepository) LatestCreatedAt(ctx context.Context, userID uuid.UUID, purpose string) (*time.Time, error) {
+	query := `
+		SELECT MAX(created_at)
+		FROM one_time_tokens
+		WHERE user_id = $1 AND purpose = $2
+	`
+
+	var latest *time.Time
+	err := r.db.QueryRowContext(ctx, query, userID, purpose).Scan(&latest)
+	return latest, err
+}
+
+func (r *oneTimeTokenRepository) Invalidate(ctx context.Context, userID uuid.UUID, purpose string) error {
+	query := `
+		UPDATE one_time_tokens
+		SET used_at = NOW()
+		WHERE user_id = $1 AND purpose = $2 AND used_at IS NULL
+	`
+
+	_, err := r.db.ExecContext(ctx, query, userID, purpose)
+	return err
+}
+
+func (r *oneTimeTokenRepository) DeleteExpired(ctx context.Context) error {
+	query := `DELETE FROM one_time_tokens WHERE expires_at < NOW() - INTERVAL '7 days'`
+
+	_, err := r.db.ExecContext(ctx, query)
+	return err
+}
@@ -0,0 +1,273 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/Ramsi97/edu-social-backend/internal/auth/domain"
+	"github.com/Ramsi97/edu-social-backend/internal/auth/repository/interfaces"
+	"github.com/google/uuid"
+)
+
+type sessionRepository struct {
+	db *sql.DB
+}
+
+func NewSessionRepository(db *sql.DB) interfaces.SessionRepository {
+	return &sessionRepository{
+		db: db,
+	}
+}
+
+func (r *sessionRepository) CreateSession(ctx context.Context, session *domain.Session) error {
+	newID, err := uuid.NewV7()
+	if err != nil {
+		return err
+	}
+	session.ID = newID
+	session.CreatedAt = time.Now().UTC()
+	session.LastSeenAt = session.CreatedAt
+
+	query := `
+		INSERT INTO auth_sessions (
+			id, user_id, device_label, user_agent, ip_address,
+			created_at, last_seen_at, expires_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+
+	_, err = r.db.ExecContext(
+		ctx,
+		query,
+		session.ID,
+		session.UserID,
+		session.DeviceLabel,
+		session.UserAgent,
+		session.IPAddress,
+		session.CreatedAt,
+		session.LastSeenAt,
+		session.ExpiresAt,
+	)
+	return err
+}
+
+func (r *sessionRepository) GetSession(ctx context.Context, sessionID uuid.UUID) (*domain.Session, error) {
+	query := `
+		SELECT
+			id, user_id, device_label, user_agent, ip_address,
+			created_at, last_seen_at, expires_at, revoked_at
+		FROM auth_sessions
+		WHERE id = $1
+	`
+
+	session, err := scanSession(r.db.QueryRowContext(ctx, query, sessionID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrSessionNotFound
+		}
+		return nil, err
+	}
+
+	return session, nil
+}
+
+func (r *sessionRepository) ListActiveSessions(ctx context.Context, userID uuid.UUID) ([]domain.Session, error) {
+	query := `
+		SELECT
+			id, user_id, device_label, user_agent, ip_address,
+			created_at, last_seen_at, expires_at, revoked_at
+		FROM auth_sessions
+		WHERE user_id = $1 AND revoked_at IS NULL AND expires_at > NOW()
+		ORDER BY last_seen_at DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sessions := []domain.Session{}
+	for rows.Next() {
+		session, err := scanSession(rows)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, *session)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return sessions, nil
+}
+
+func (r *sessionRepository) TouchSession(ctx context.Context, sessionID uuid.UUID) error {
+	query := `UPDATE auth_sessions SET last_seen_at = NOW() WHERE id = $1`
+
+	_, err := r.db.ExecContext(ctx, query, sessionID)
+	return err
+}
+
+func (r *sessionRepository) RevokeSession(ctx context.Context, sessionID uuid.UUID) error {
+	query := `
+		UPDATE auth_sessions
+		SET revoked_at = NOW()
+		WHERE id = $1 AND revoked_at IS NULL
+	`
+
+	_, err := r.db.ExecContext(ctx, query, sessionID)
+	return err
+}
+
+func (r *sessionRepository) RevokeOtherSessions(ctx context.Context, userID, keepID uuid.UUID) ([]uuid.UUID, error) {
+	query := `
+		UPDATE auth_sessions
+		SET revoked_at = NOW()
+		WHERE user_id = $1 AND id <> $2 AND revoked_at IS NULL
+		RETURNING id
+	`
+
+	return r.revokeReturning(ctx, query, userID, keepID)
+}
+
+func (r *sessionRepository) RevokeAllSessions(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error) {
+	query := `
+		UPDATE auth_sessions
+		SET revoked_at = NOW()
+		WHERE user_id = $1 AND revoked_at IS NULL
+		RETURNING id
+	`
+
+	return r.revokeReturning(ctx, query, userID)
+}
+
+func (r *sessionRepository) revokeReturning(ctx context.Context, query string, args ...any) ([]uuid.UUID, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var revoked []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		revoked = append(revoked, id)
+	}
+
+	return revoked, rows.Err()
+}
+
+func (r *sessionRepository) CreateRefreshToken(ctx context.Context, token *domain.RefreshToken) error {
+	newID, err := uuid.NewV7()
+	if err != nil {
+		return err
+	}
+	token.ID = newID
+	token.CreatedAt = time.Now().UTC()
+
+	query := `
+		INSERT INTO refresh_tokens (id, session_id, user_id, token_hash, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	_, err = r.db.ExecContext(
+		ctx,
+		query,
+		token.ID,
+		token.SessionID,
+		token.UserID,
+		token.TokenHash,
+		token.ExpiresAt,
+		token.CreatedAt,
+	)
+	return err
+}
+
+func (r *sessionRepository) ConsumeRefreshToken(ctx context.Context, tokenHash string) (*domain.RefreshToken, error) {
+	query := `
+		UPDATE refresh_tokens
+		SET used_at = NOW()
+		WHERE token_hash = $1 AND used_at IS NULL
+		RETURNING id, session_id, user_id, token_hash, expires_at, created_at, used_at
+	`
+
+	token, err := scanRefreshToken(r.db.QueryRowContext(ctx, query, tokenHash))
+	if err == nil {
+		return token, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+
+	// Either the token never existed or it was already rotated. The latter
+	// means somebody replayed an old token, so the caller revokes the family.
+	token, err = r.FindRefreshToken(ctx, tokenHash)
+	if err != nil {
+		return nil, err
+	}
+	return token, domain.ErrRefreshTokenReused
+}
+
+func (r *sessionRepository) FindRefreshToken(ctx context.Context, tokenHash string) (*domain.RefreshToken, error) {
+	query := `
+		SELECT id, session_id, user_id, token_hash, expires_at, created_at, used_at
+		FROM refresh_tokens
+		WHERE token_hash = $1
+	`
+
+	token, err := scanRefreshToken(r.db.QueryRowContext(ctx, query, tokenHash))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrInvalidRefreshToken
+		}
+		return nil, err
+	}
+	return token, nil
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanSession(row rowScanner) (*domain.Session, error) {
+	var session domain.Session
+	err := row.Scan(
+		&session.ID,
+		&session.UserID,
+		&session.DeviceLabel,
+		&session.UserAgent,
+		&session.IPAddress,
+		&session.CreatedAt,
+		&session.LastSeenAt,
+		&session.ExpiresAt,
+		&session.RevokedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+func scanRefreshToken(row rowScanner) (*domain.RefreshToken, error) {
+	var token domain.RefreshToken
+	err := row.Scan(
+		&token.ID,
+		&token.SessionID,
+		&token.UserID,
+		&token.TokenHash,
+		&token.ExpiresAt,
+		&token.CreatedAt,
+		&token.UsedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
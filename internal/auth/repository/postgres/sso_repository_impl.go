@@ -0,0 +1,107 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/Ramsi97/edu-social-backend/internal/auth/domain"
+	"github.com/Ramsi97/edu-social-backend/internal/auth/repository/interfaces"
+)
+
+type ssoRepository struct {
+	db *sql.DB
+}
+
+func NewSSORepository(db *sql.DB) interfaces.SSORepository {
+	return &ssoRepository{
+		db: db,
+	}
+}
+
+func (r *ssoRepository) SaveState(ctx context.Context, state *domain.SSOState) error {
+	// Opportunistically drop abandoned login attempts.
+	if _, err := r.db.ExecContext(ctx, `DELETE FROM oidc_login_states WHERE expires_at < NOW()`); err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO oidc_login_states (state_hash, nonce, code_verifier, expires_at)
+		VALUES ($1, $2, $3, $4)
+	`
+
+	_, err := r.db.ExecContext(ctx, query, state.StateHash, state.Nonce, state.CodeVerifier, state.ExpiresAt)
+	return err
+}
+
+func (r *ssoRepository) ConsumeState(ctx context.Context, stateHash string) (*domain.SSOState, error) {
+	query := `
+		DELETE FROM oidc_login_states
+		WHERE state_hash = $1 AND expires_at > NOW()
+		RETURNING state_hash, nonce, code_verifier, expires_at
+	`
+
+	var state domain.SSOState
+	err := r.db.QueryRowContext(ctx, query, stateHash).Scan(
+		&state.StateHash,
+		&state.Nonce,
+		&state.CodeVerifier,
+		&state.ExpiresAt,
+	)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrInvalidSSOState
+		}
+		return nil, err
+	}
+
+	return &state, nil
+}
+
+func (r *ssoRepository) FindIdentity(ctx context.Context, issuer, subject string) (*domain.ExternalIdentity, error) {
+	query := `
+		SELECT issuer, subject, user_id, email, created_at
+		FROM user_identities
+		WHERE issuer = $1 AND subject = $2
+	`
+
+	var identity domain.ExternalIdentity
+	err := r.db.QueryRowContext(ctx, query, issuer, subject).Scan(
+		&identity.Issuer,
+		&identity.Subject,
+		&identity.UserID,
+		&identity.Email,
+		&identity.CreatedAt,
+	)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	return &identity, nil
+}
+
+func (r *ssoRepository) LinkIdentity(ctx context.Context, identity *domain.ExternalIdentity) error {
+	identity.CreatedAt = time.Now().UTC()
+
+	query := `
+		INSERT INTO user_identities (issuer, subject, user_id, email, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+
+	_, err := r.db.ExecContext(
+		ctx,
+		query,
+		identity.Issuer,
+		identity.Subject,
+		identity.UserID,
+		identity.Email,
+		identity.CreatedAt,
+	)
+	return err
+}
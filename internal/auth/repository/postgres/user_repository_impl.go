@@ -3,6 +3,7 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"time"
 
@@ -29,6 +30,9 @@ func (r *userRepository) Create(ctx context.Context, user *domain.User) error {
 	now := time.Now().UTC()
 	user.ID = newID
 	user.CreatedAt = now
+	if user.Role == "" {
+		user.Role = domain.RoleStudent
+	}
 
 	const layout = "2006-01-02"
 
@@ -42,9 +46,9 @@ func (r *userRepository) Create(ctx context.Context, user *domain.User) error {
 	query := `
 		INSERT INTO users (
 			id, first_name, last_name, student_id, email, 
-			password_hash, joined_year, profile_picture, gender, created_at
+			password_hash, joined_year, profile_picture, gender, role, created_at
 		) 
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`
 
 	_, err = r.db.ExecContext(
 		ctx,
@@ -58,35 +62,16 @@ func (r *userRepository) Create(ctx context.Context, user *domain.User) error {
 		joinedYear,
 		user.ProfilePicture,
 		user.Gender,
+		user.Role,
 		user.CreatedAt,
 	)
 	return err
 }
 
 func (r *userRepository) FindByEmail(ctx context.Context, email string) (*domain.User, error) {
-	query := `
-		SELECT 
-			id, first_name, last_name, student_id,
-			email, password_hash, joined_year,
-			profile_picture, gender, created_at
-		FROM users
-		WHERE email = $1
-	`
-	var user domain.User
-
-	err := r.db.QueryRowContext(ctx, query, email).Scan(
-		&user.ID,
-		&user.FirstName,
-		&user.LastName,
-		&user.StudentID,
-		&user.Email,
-		&user.Password,
-		&user.JoinedYear,
-		&user.ProfilePicture,
-		&user.Gender,
-		&user.CreatedAt,
-	)
+	query := `SELECT ` + userColumns + ` FROM users WHERE email = $1`
 
+	user, err := scanUser(r.db.QueryRowContext(ctx, query, email))
 	if err != nil {
 		if err == domain.ErrUserNotFound {
 			return nil, domain.ErrUserNotFound
@@ -94,23 +79,163 @@ func (r *userRepository) FindByEmail(ctx context.Context, email string) (*domain
 		return nil, err
 	}
 
-	return &user, nil
+	return user, nil
 }
 
 func (r *userRepository) FindByStudentId(ctx context.Context, studentID string) (*domain.User, error) {
+	query := `SELECT ` + userColumns + ` FROM users WHERE student_id = $1`
 
+	user, err := scanUser(r.db.QueryRowContext(ctx, query, studentID))
+	if err != nil {
+		if err == domain.ErrUserNotFound {
+			return nil, domain.ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	return user, nil
+}
+
+func (r *userRepository) FindByID(ctx context.Context, userID uuid.UUID) (*domain.User, error) {
+	query := `SELECT ` + userColumns + ` FROM users WHERE id = $1`
+
+	user, err := scanUser(r.db.QueryRowContext(ctx, query, userID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	return user, nil
+}
+
+func (r *userRepository) MarkEmailVerified(ctx context.Context, userID uuid.UUID) error {
 	query := `
-		SELECT
-			id, first_name, last_name, student_id,
-			email, password_hash, joined_year,
-			profile_picture, gender, created_at
-		FROM users
-		WHERE student_id = $1
+		UPDATE users
+		SET email_verified_at = NOW()
+		WHERE id = $1 AND email_verified_at IS NULL
 	`
 
-	var user domain.User
+	_, err := r.db.ExecContext(ctx, query, userID)
+	return err
+}
+
+func (r *userRepository) UpdatePassword(ctx context.Context, userID uuid.UUID, passwordHash string) error {
+	query := `UPDATE users SET password_hash = $2 WHERE id = $1`
+
+	res, err := r.db.ExecContext(ctx, query, userID, passwordHash)
+	if err != nil {
+		return err
+	}
+
+	rows, _ := res.RowsAffected()
+	if rows == 0 {
+		return domain.ErrUserNotFound
+	}
+	return nil
+}
+
+func (r *userRepository) List(ctx context.Context, filter domain.UserFilter) ([]domain.User, error) {
+	query := `SELECT ` + userColumns + ` FROM users WHERE TRUE`
+	args := []any{}
+
+	if filter.Query != "" {
+		args = append(args, "%"+filter.Query+"%")
+		n := len(args)
+		query += fmt.Sprintf(
+			" AND (first_name ILIKE $%d OR last_name ILIKE $%d OR email ILIKE $%d OR student_id ILIKE $%d)",
+			n, n, n, n,
+		)
+	}
+	if filter.Role != "" {
+		args = append(args, filter.Role)
+		query += fmt.Sprintf(" AND role = $%d", len(args))
+	}
+	if filter.Suspended != nil {
+		if *filter.Suspended {
+			query += " AND suspended_at IS NOT NULL"
+		} else {
+			query += " AND suspended_at IS NULL"
+		}
+	}
+	if filter.Before != nil {
+		args = append(args, *filter.Before)
+		query += fmt.Sprintf(" AND created_at < $%d", len(args))
+	}
+
+	args = append(args, filter.Limit)
+	query += fmt.Sprintf(" ORDER BY created_at DESC LIMIT $%d", len(args))
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := []domain.User{}
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, *user)
+	}
+
+	return users, rows.Err()
+}
+
+func (r *userRepository) Suspend(ctx context.Context, userID uuid.UUID, reason *string) error {
+	query := `
+		UPDATE users
+		SET suspended_at = COALESCE(suspended_at, NOW()), suspension_reason = $2
+		WHERE id = $1
+	`
+
+	return r.execAffectingUser(ctx, query, userID, reason)
+}
 
-	err := r.db.QueryRowContext(ctx, query, studentID).Scan(
+// Unsuspend leaves deleted accounts alone: they are suspended as part of
+// being anonymized and must not be brought back.
+func (r *userRepository) Unsuspend(ctx context.Context, userID uuid.UUID) error {
+	query := `
+		UPDATE users
+		SET suspended_at = NULL, suspension_reason = NULL
+		WHERE id = $1 AND deleted_at IS NULL
+	`
+
+	return r.execAffectingUser(ctx, query, userID)
+}
+
+func (r *userRepository) UpdateRole(ctx context.Context, userID uuid.UUID, role domain.Role) error {
+	query := `UPDATE users SET role = $2 WHERE id = $1`
+
+	return r.execAffectingUser(ctx, query, userID, role)
+}
+
+func (r *userRepository) execAffectingUser(ctx context.Context, query string, args ...any) error {
+	res, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+
+	rows, _ := res.RowsAffected()
+	if rows == 0 {
+		return domain.ErrUserNotFound
+	}
+	return nil
+}
+
+const userColumns = `
+	id, first_name, last_name, student_id,
+	email, password_hash, joined_year,
+	profile_picture, gender, is_private, email_verified_at,
+	role, suspended_at, suspension_reason, created_at, username
+`
+
+func scanUser(row rowScanner) (*domain.User, error) {
+	var user domain.User
+	err := row.Scan(
 		&user.ID,
 		&user.FirstName,
 		&user.LastName,
@@ -120,15 +245,16 @@ func (r *userRepository) FindByStudentId(ctx context.Context, studentID string)
 		&user.JoinedYear,
 		&user.ProfilePicture,
 		&user.Gender,
+		&user.IsPrivate,
+		&user.EmailVerifiedAt,
+		&user.Role,
+		&user.SuspendedAt,
+		&user.SuspensionReason,
 		&user.CreatedAt,
+		&user.Username,
 	)
-
 	if err != nil {
-		if err == domain.ErrUserNotFound {
-			return nil, domain.ErrUserNotFound
-		}
 		return nil, err
 	}
-
 	return &user, nil
 }
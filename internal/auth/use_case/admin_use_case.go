@@ -0,0 +1,71 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Ramsi97/edu-social-backend/internal/auth/domain"
+	"github.com/google/uuid"
+)
+
+const (
+	defaultUserPageSize = 20
+	maxUserPageSize     = 100
+)
+
+func (a *authUseCase) ListUsers(ctx context.Context, filter domain.UserFilter) ([]domain.User, error) {
+	if filter.Role != "" && !filter.Role.Valid() {
+		return nil, domain.ErrInvalidRole
+	}
+
+	if filter.Limit <= 0 {
+		filter.Limit = defaultUserPageSize
+	}
+	if filter.Limit > maxUserPageSize {
+		filter.Limit = maxUserPageSize
+	}
+	filter.Query = strings.TrimSpace(filter.Query)
+
+	return a.userRepo.List(ctx, filter)
+}
+
+func (a *authUseCase) SuspendUser(ctx context.Context, adminID, userID uuid.UUID, reason string) error {
+	if adminID == userID {
+		return domain.ErrCannotModerateSelf
+	}
+
+	var stored *string
+	if reason = strings.TrimSpace(reason); reason != "" {
+		stored = &reason
+	}
+
+	if err := a.userRepo.Suspend(ctx, userID, stored); err != nil {
+		return err
+	}
+
+	// Authenticate already rejects suspended users; revoking the sessions
+	// also drops their open sockets and dead-ends their refresh tokens.
+	revoked, err := a.sessionRepo.RevokeAllSessions(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	a.notifySessionsRevoked(revoked...)
+	return nil
+}
+
+func (a *authUseCase) UnsuspendUser(ctx context.Context, userID uuid.UUID) error {
+	return a.userRepo.Unsuspend(ctx, userID)
+}
+
+func (a *authUseCase) AssignRole(ctx context.Context, adminID, userID uuid.UUID, role domain.Role) error {
+	if !role.Valid() {
+		return domain.ErrInvalidRole
+	}
+
+	if adminID == userID {
+		return domain.ErrCannotModerateSelf
+	}
+
+	return a.userRepo.UpdateRole(ctx, userID, role)
+}
@@ -3,74 +3,132 @@ package usecase
 import (
 	"context"
 	"errors"
-	"time"
+	"log"
+	"sync"
 
 	"github.com/Ramsi97/edu-social-backend/internal/auth/domain"
 	repoInterface "github.com/Ramsi97/edu-social-backend/internal/auth/repository/interfaces"
 	cldInterface "github.com/Ramsi97/edu-social-backend/internal/shared/interfaces"
-	"github.com/Ramsi97/edu-social-backend/pkg/auth"
+	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
 )
 
+const passwordHashCost = 14
+
 type authUseCase struct {
 	userRepo repoInterface.UserRepository
+	sessionRepo repoInterface.SessionRepository
+	tokenRepo repoInterface.OneTimeTokenRepository
+	attemptStore repoInterface.LoginAttemptStore
+	mfaRepo repoInterface.MFARepository
+	ssoRepo repoInterface.SSORepository
+	idp domain.IdentityProvider
 	cld cldInterface.MediaStorage
+	mailer cldInterface.Mailer
+	cfg domain.AuthConfig
+
+	listenersMu sync.RWMutex
+	revokeListeners []func(sessionIDs ...uuid.UUID)
 }
 
-func NewAuthUseCase(repo repoInterface.UserRepository, cld cldInterface.MediaStorage) domain.AuthUseCase {
+func NewAuthUseCase(
+	repo repoInterface.UserRepository,
+	sessionRepo repoInterface.SessionRepository,
+	tokenRepo repoInterface.OneTimeTokenRepository,
+	attemptStore repoInterface.LoginAttemptStore,
+	mfaRepo repoInterface.MFARepository,
+	ssoRepo repoInterface.SSORepository,
+	idp domain.IdentityProvider,
+	cld cldInterface.MediaStorage,
+	mailer cldInterface.Mailer,
+	cfg domain.AuthConfig,
+) domain.AuthUseCase {
 	return &authUseCase{
 		userRepo: repo,
+		sessionRepo: sessionRepo,
+		tokenRepo: tokenRepo,
+		attemptStore: attemptStore,
+		mfaRepo: mfaRepo,
+		ssoRepo: ssoRepo,
+		idp: idp,
 		cld: cld,
+		mailer: mailer,
+		cfg: cfg,
 	}
 }
 
-func (a *authUseCase) LoginWithEmail(ctx context.Context, email string, password string) (*domain.User, string, error) {
-	user, err := a.userRepo.FindByEmail(ctx, email)
-	if err != nil {
-		return nil, "", errors.New("invalid credentials")
+func (a *authUseCase) LoginWithEmail(ctx context.Context, email string, password string, client domain.ClientInfo) (*domain.User, *domain.TokenPair, error) {
+	keys := a.loginKeys("email:"+email, client)
+	if err := a.checkLoginAllowed(ctx, keys); err != nil {
+		return nil, nil, err
+	}
+
+	user, _ := a.userRepo.FindByEmail(ctx, email)
+	if !checkPassword(user, password) {
+		a.recordLoginFailure(ctx, keys)
+		return nil, nil, domain.ErrInvalidCredentials
 	}
+	a.resetLoginFailures(ctx, keys)
 
-	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil{
-		return nil, "", errors.New("invalid credentials")
+	if !user.EmailVerified() {
+		return nil, nil, domain.ErrEmailNotVerified
 	}
 
-	token, err := auth.GenerateToken(user.ID, time.Hour*72)
+	tokens, err := a.completeLogin(ctx, user, client)
+	var mfaRequired *domain.MFARequiredError
+	if errors.As(err, &mfaRequired) {
+		return user, nil, err
+	}
+	if errors.Is(err, domain.ErrAccountSuspended) {
+		return nil, nil, err
+	}
 	if(err != nil){
-		return nil, "", errors.New("please, try again")
+		return nil, nil, errors.New("please, try again")
 	}
-	return user, token, nil
+	return user, tokens, nil
 }
 
-func (a *authUseCase) LoginWithId(ctx context.Context, studentId string, password string) (string, error) {
-	user, err := a.userRepo.FindByStudentId(ctx, studentId)
-	if err != nil {
-		return "", errors.New("invalid credentials")
+func (a *authUseCase) LoginWithId(ctx context.Context, studentId string, password string, client domain.ClientInfo) (*domain.TokenPair, error) {
+	keys := a.loginKeys("student_id:"+studentId, client)
+	if err := a.checkLoginAllowed(ctx, keys); err != nil {
+		return nil, err
+	}
+
+	user, _ := a.userRepo.FindByStudentId(ctx, studentId)
+	if !checkPassword(user, password) {
+		a.recordLoginFailure(ctx, keys)
+		return nil, domain.ErrInvalidCredentials
 	}
+	a.resetLoginFailures(ctx, keys)
 
-	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil{
-		return "", errors.New("invalid credentials")
+	if !user.EmailVerified() {
+		return nil, domain.ErrEmailNotVerified
 	}
 
-	token, err := auth.GenerateToken(user.ID, time.Hour*144)
+	tokens, err := a.completeLogin(ctx, user, client)
+	var mfaRequired *domain.MFARequiredError
+	if errors.As(err, &mfaRequired) || errors.Is(err, domain.ErrAccountSuspended) {
+		return nil, err
+	}
 	if(err != nil){
-		return "", errors.New("please, try again")
+		return nil, errors.New("please, try again")
 	}
-	return token, nil
+	return tokens, nil
 }
 
 
 func (a *authUseCase) Register(ctx context.Context, req *domain.RegisterRequest) error{
 	existingUser, _ := a.userRepo.FindByEmail(ctx, req.Email)
 	if existingUser != nil {
-		return errors.New("user already exists")
+		return domain.ErrUserAlreadyExists
 	}
 
 	existingUser, _ = a.userRepo.FindByStudentId(ctx, req.StudentID)
 	if existingUser != nil {
-		return errors.New("user already exists")
+		return domain.ErrUserAlreadyExists
 	}
 
-	hashedByte, err := bcrypt.GenerateFromPassword([]byte(req.Password), 14)
+	hashedByte, err := bcrypt.GenerateFromPassword([]byte(req.Password), passwordHashCost)
 	if err != nil{
 		return err
 	}
@@ -78,7 +136,7 @@ func (a *authUseCase) Register(ctx context.Context, req *domain.RegisterRequest)
 	var profileURL *string
 
 	if req.ProfilePictureFile != nil {
-		url, err := a.cld.UploadToCloudinary(ctx, req.ProfilePictureFile)
+		url, err := a.cld.Upload(ctx, req.ProfilePictureFile, cldInterface.PurposeAvatar)
 		if err != nil {
 			return err
 		}
@@ -95,9 +153,20 @@ func (a *authUseCase) Register(ctx context.Context, req *domain.RegisterRequest)
 		JoinedYear:     req.JoinedYear,
 		ProfilePicture: profileURL, 
 		Gender:         req.Gender,
+		Role:           domain.RoleStudent,
 	}
 
 	user.Password = string(hashedByte)
-	return a.userRepo.Create(ctx, user)
+	if err := a.userRepo.Create(ctx, user); err != nil {
+		return err
+	}
+
+	// The account exists even if the mail can't go out; the user can ask
+	// for another link through the resend endpoint.
+	if err := a.sendVerification(ctx, user); err != nil {
+		log.Printf("failed to send verification email to %s: %v", user.Email, err)
+	}
+
+	return nil
 }
 
@@ -0,0 +1,130 @@
+package usecase
+
+import (
+	"context"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Ramsi97/edu-social-backend/internal/auth/domain"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// dummyPasswordHash stands in for the stored hash when no account matches,
+// so an unknown identifier takes as long to reject as a wrong password.
+var dummyPasswordHash = sync.OnceValue(func() []byte {
+	hash, err := bcrypt.GenerateFromPassword([]byte("edu-social-no-such-account"), passwordHashCost)
+	if err != nil {
+		log.Printf("failed to generate dummy password hash: %v", err)
+	}
+	return hash
+})
+
+// checkPassword reports whether password matches the user's hash. A nil
+// user still pays for a bcrypt comparison; together with throttling every
+// identifier alike, this keeps login from revealing which accounts exist.
+func checkPassword(user *domain.User, password string) bool {
+	if user == nil {
+		bcrypt.CompareHashAndPassword(dummyPasswordHash(), []byte(password))
+		return false
+	}
+	return bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)) == nil
+}
+
+type throttleKey struct {
+	key     string
+	cfg     domain.LoginThrottleConfig
+	account bool
+}
+
+func (a *authUseCase) loginKeys(identifier string, client domain.ClientInfo) []throttleKey {
+	keys := []throttleKey{{
+		key:     "account:" + strings.ToLower(strings.TrimSpace(identifier)),
+		cfg:     a.cfg.AccountThrottle,
+		account: true,
+	}}
+
+	if client.IPAddress != "" {
+		keys = append(keys, throttleKey{
+			key: "ip:" + client.IPAddress,
+			cfg: a.cfg.IPThrottle,
+		})
+	}
+
+	return keys
+}
+
+// checkLoginAllowed refuses the attempt before any password is hashed when
+// one of the keys is locked or still inside its backoff delay.
+func (a *authUseCase) checkLoginAllowed(ctx context.Context, keys []throttleKey) error {
+	now := time.Now()
+
+	for _, k := range keys {
+		attempts, err := a.attemptStore.Get(ctx, k.key)
+		if err != nil {
+			return err
+		}
+
+		if attempts.LockedUntil != nil && now.Before(*attempts.LockedUntil) {
+			return &domain.ThrottledError{
+				RetryAfter: attempts.LockedUntil.Sub(now),
+				Locked:     k.account,
+			}
+		}
+
+		if wait := backoff(k.cfg, attempts.Failures); wait > 0 {
+			if next := attempts.LastFailureAt.Add(wait); now.Before(next) {
+				return &domain.ThrottledError{RetryAfter: next.Sub(now)}
+			}
+		}
+	}
+
+	return nil
+}
+
+func (a *authUseCase) recordLoginFailure(ctx context.Context, keys []throttleKey) {
+	for _, k := range keys {
+		attempts, err := a.attemptStore.RecordFailure(ctx, k.key, k.cfg.Window)
+		if err != nil {
+			log.Printf("failed to record login failure for %s: %v", k.key, err)
+			continue
+		}
+
+		if k.cfg.LockoutThreshold > 0 && attempts.Failures >= k.cfg.LockoutThreshold {
+			if err := a.attemptStore.Lock(ctx, k.key, time.Now().Add(k.cfg.LockoutDuration)); err != nil {
+				log.Printf("failed to lock %s: %v", k.key, err)
+			}
+		}
+	}
+}
+
+// resetLoginFailures clears the account counter after a successful login.
+// The IP counter is left alone so one valid account can't be used to keep
+// guessing others from the same address.
+func (a *authUseCase) resetLoginFailures(ctx context.Context, keys []throttleKey) {
+	for _, k := range keys {
+		if !k.account {
+			continue
+		}
+		if err := a.attemptStore.Reset(ctx, k.key); err != nil {
+			log.Printf("failed to reset login failures for %s: %v", k.key, err)
+		}
+	}
+}
+
+func backoff(cfg domain.LoginThrottleConfig, failures int) time.Duration {
+	over := failures - cfg.FreeAttempts
+	if over <= 0 || cfg.BackoffBase <= 0 {
+		return 0
+	}
+
+	wait := cfg.BackoffBase
+	for i := 1; i < over; i++ {
+		wait *= 2
+		if cfg.BackoffMax > 0 && wait >= cfg.BackoffMax {
+			return cfg.BackoffMax
+		}
+	}
+	return wait
+}
@@ -0,0 +1,188 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Ramsi97/edu-social-backend/internal/auth/domain"
+	repoInterface "github.com/Ramsi97/edu-social-backend/internal/auth/repository/interfaces"
+	"github.com/Ramsi97/edu-social-backend/internal/auth/repository/memory"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// fakeUsers answers the lookups login needs; every other method panics
+// through the nil embedded interface.
+type fakeUsers struct {
+	repoInterface.UserRepository
+	byEmail map[string]*domain.User
+}
+
+func (f *fakeUsers) FindByEmail(ctx context.Context, email string) (*domain.User, error) {
+	if u, ok := f.byEmail[email]; ok {
+		return u, nil
+	}
+	return nil, domain.ErrUserNotFound
+}
+
+func newThrottleTestUseCase(t *testing.T, account domain.LoginThrottleConfig) *authUseCase {
+	t.Helper()
+
+	hash, err := bcrypt.GenerateFromPassword([]byte("correct horse"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &authUseCase{
+		userRepo: &fakeUsers{byEmail: map[string]*domain.User{
+			"known@students.example": {Email: "known@students.example", Password: string(hash)},
+		}},
+		attemptStore: memory.NewLoginAttemptStore(),
+		cfg: domain.AuthConfig{
+			AccountThrottle: account,
+			IPThrottle:      domain.LoginThrottleConfig{Window: time.Hour},
+		},
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	cfg := domain.LoginThrottleConfig{
+		FreeAttempts: 3,
+		BackoffBase:  time.Second,
+		BackoffMax:   10 * time.Second,
+	}
+
+	tests := []struct {
+		failures int
+		want     time.Duration
+	}{
+		{0, 0},
+		{3, 0},
+		{4, time.Second},
+		{5, 2 * time.Second},
+		{6, 4 * time.Second},
+		{7, 8 * time.Second},
+		{8, 10 * time.Second},
+		{20, 10 * time.Second},
+	}
+
+	for _, tt := range tests {
+		if got := backoff(cfg, tt.failures); got != tt.want {
+			t.Errorf("backoff(%d) = %s, want %s", tt.failures, got, tt.want)
+		}
+	}
+
+	if got := backoff(domain.LoginThrottleConfig{FreeAttempts: 1}, 5); got != 0 {
+		t.Errorf("backoff without a base = %s, want 0", got)
+	}
+}
+
+func TestLoginThrottle(t *testing.T) {
+	lockout := domain.LoginThrottleConfig{
+		LockoutThreshold: 3,
+		LockoutDuration:  15 * time.Minute,
+		Window:           time.Hour,
+	}
+	delayed := domain.LoginThrottleConfig{
+		FreeAttempts: 1,
+		BackoffBase:  time.Minute,
+		Window:       time.Hour,
+	}
+
+	tests := []struct {
+		name       string
+		cfg        domain.LoginThrottleConfig
+		failures   int
+		reset      bool
+		wantLocked bool
+		wantDelay  bool
+	}{
+		{name: "below threshold", cfg: lockout, failures: 2},
+		{name: "locked at threshold", cfg: lockout, failures: 3, wantLocked: true},
+		{name: "free attempts", cfg: delayed, failures: 1},
+		{name: "backoff after free attempts", cfg: delayed, failures: 2, wantDelay: true},
+		{name: "reset on success", cfg: lockout, failures: 2, reset: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := newThrottleTestUseCase(t, tt.cfg)
+			ctx := context.Background()
+			keys := a.loginKeys("email:known@students.example", domain.ClientInfo{IPAddress: "10.0.0.1"})
+
+			for i := 0; i < tt.failures; i++ {
+				a.recordLoginFailure(ctx, keys)
+			}
+			if tt.reset {
+				a.resetLoginFailures(ctx, keys)
+			}
+
+			err := a.checkLoginAllowed(ctx, keys)
+			var throttled *domain.ThrottledError
+			switch {
+			case tt.wantLocked:
+				if !errors.As(err, &throttled) || !throttled.Locked {
+					t.Fatalf("err = %v, want a lockout", err)
+				}
+			case tt.wantDelay:
+				if !errors.As(err, &throttled) || throttled.Locked || throttled.RetryAfter <= 0 {
+					t.Fatalf("err = %v, want a backoff delay", err)
+				}
+			case err != nil:
+				t.Fatalf("err = %v, want the attempt allowed", err)
+			}
+
+			// The IP counter survives a successful login.
+			if tt.reset {
+				ip, _ := a.attemptStore.Get(ctx, "ip:10.0.0.1")
+				if ip.Failures != tt.failures {
+					t.Fatalf("ip failures = %d after reset, want %d", ip.Failures, tt.failures)
+				}
+			}
+		})
+	}
+}
+
+// TestLoginUnknownAndExistingAlike checks that a wrong password for a real
+// account and any password for a missing one are indistinguishable, both
+// before and after the identifier is locked.
+func TestLoginUnknownAndExistingAlike(t *testing.T) {
+	cfg := domain.LoginThrottleConfig{
+		LockoutThreshold: 2,
+		LockoutDuration:  15 * time.Minute,
+		Window:           time.Hour,
+	}
+
+	tests := []struct {
+		name  string
+		email string
+	}{
+		{"existing account", "known@students.example"},
+		{"unknown account", "nobody@students.example"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := newThrottleTestUseCase(t, cfg)
+			ctx := context.Background()
+			client := domain.ClientInfo{IPAddress: "10.0.0.2"}
+
+			for i := 0; i < cfg.LockoutThreshold; i++ {
+				_, _, err := a.LoginWithEmail(ctx, tt.email, "wrong password", client)
+				if !errors.Is(err, domain.ErrInvalidCredentials) {
+					t.Fatalf("attempt %d: err = %v, want %v", i+1, err, domain.ErrInvalidCredentials)
+				}
+			}
+
+			_, _, err := a.LoginWithEmail(ctx, tt.email, "correct horse", client)
+			var throttled *domain.ThrottledError
+			if !errors.As(err, &throttled) || !throttled.Locked {
+				t.Fatalf("err = %v, want a lockout", err)
+			}
+			if throttled.RetryAfter <= 0 || throttled.RetryAfter > cfg.LockoutDuration {
+				t.Fatalf("RetryAfter = %s", throttled.RetryAfter)
+			}
+		})
+	}
+}
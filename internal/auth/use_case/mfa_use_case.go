@@ -0,0 +1,277 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/Ramsi97/edu-social-backend/internal/auth/domain"
+	"github.com/Ramsi97/edu-social-backend/pkg/auth"
+	"github.com/google/uuid"
+)
+
+const (
+	mfaChallengeTTL   = 5 * time.Minute
+	recoveryCodeCount = 10
+)
+
+func (a *authUseCase) EnrollMFA(ctx context.Context, userID uuid.UUID) (*domain.MFAEnrollment, error) {
+	user, err := a.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := auth.GenerateTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	sealed, err := a.cfg.TOTPSecrets.Seal(secret, userID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := a.mfaRepo.SaveSecret(ctx, userID, sealed); err != nil {
+		return nil, err
+	}
+
+	return &domain.MFAEnrollment{
+		Secret:          secret,
+		ProvisioningURI: auth.TOTPProvisioningURI(a.cfg.TOTPIssuer, user.Email, secret),
+	}, nil
+}
+
+func (a *authUseCase) ConfirmMFA(ctx context.Context, userID uuid.UUID, code string) ([]string, error) {
+	mfa, err := a.mfaRepo.Get(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if mfa.Enabled() {
+		return nil, domain.ErrMFAAlreadyEnabled
+	}
+
+	if err := a.checkTOTP(ctx, mfa, code); err != nil {
+		return nil, err
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := a.mfaRepo.ReplaceRecoveryCodes(ctx, userID, hashes); err != nil {
+		return nil, err
+	}
+
+	if err := a.mfaRepo.Enable(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+func (a *authUseCase) DisableMFA(ctx context.Context, userID uuid.UUID, code, recoveryCode string) error {
+	mfa, err := a.mfaRepo.Get(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if mfa.Enabled() {
+		if err := a.checkSecondFactor(ctx, mfa, code, recoveryCode); err != nil {
+			return err
+		}
+	}
+
+	return a.mfaRepo.Delete(ctx, userID)
+}
+
+func (a *authUseCase) VerifyMFA(
+	ctx context.Context,
+	challenge, code, recoveryCode string,
+	client domain.ClientInfo,
+) (*domain.User, *domain.TokenPair, error) {
+	hash := auth.HashToken(challenge)
+
+	pending, err := a.tokenRepo.Find(ctx, domain.TokenPurposeMFAChallenge, hash)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	keys := []throttleKey{{
+		key:     "mfa:" + pending.UserID.String(),
+		cfg:     a.cfg.AccountThrottle,
+		account: true,
+	}}
+	if err := a.checkLoginAllowed(ctx, keys); err != nil {
+		return nil, nil, err
+	}
+
+	mfa, err := a.mfaRepo.Get(ctx, pending.UserID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := a.checkSecondFactor(ctx, mfa, code, recoveryCode); err != nil {
+		if errors.Is(err, domain.ErrInvalidMFACode) {
+			a.recordLoginFailure(ctx, keys)
+		}
+		return nil, nil, err
+	}
+	a.resetLoginFailures(ctx, keys)
+
+	// Consume only after a correct code so a typo doesn't burn the challenge.
+	if _, err := a.tokenRepo.Consume(ctx, domain.TokenPurposeMFAChallenge, hash); err != nil {
+		return nil, nil, err
+	}
+
+	user, err := a.userRepo.FindByID(ctx, pending.UserID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tokens, err := a.startSession(ctx, user, client)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return user, tokens, nil
+}
+
+// completeLogin runs after the password was accepted. Accounts with 2FA get a
+// challenge to answer instead of tokens.
+func (a *authUseCase) completeLogin(ctx context.Context, user *domain.User, client domain.ClientInfo) (*domain.TokenPair, error) {
+	if user.Suspended() {
+		return nil, domain.ErrAccountSuspended
+	}
+
+	mfa, err := a.mfaRepo.Get(ctx, user.ID)
+	if err != nil && !errors.Is(err, domain.ErrMFANotEnrolled) {
+		return nil, err
+	}
+
+	if mfa.Enabled() {
+		challenge, err := a.issueOneTimeToken(ctx, user.ID, domain.TokenPurposeMFAChallenge, mfaChallengeTTL)
+		if err != nil {
+			return nil, err
+		}
+		return nil, &domain.MFARequiredError{
+			Challenge: challenge,
+			ExpiresIn: int64(mfaChallengeTTL.Seconds()),
+		}
+	}
+
+	return a.startSession(ctx, user, client)
+}
+
+func (a *authUseCase) checkSecondFactor(ctx context.Context, mfa *domain.MFA, code, recoveryCode string) error {
+	if code != "" {
+		return a.checkTOTP(ctx, mfa, code)
+	}
+
+	if recoveryCode != "" {
+		ok, err := a.mfaRepo.ConsumeRecoveryCode(ctx, mfa.UserID, auth.HashToken(normalizeRecoveryCode(recoveryCode)))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return domain.ErrInvalidMFACode
+		}
+		return nil
+	}
+
+	return domain.ErrInvalidMFACode
+}
+
+func (a *authUseCase) checkTOTP(ctx context.Context, mfa *domain.MFA, code string) error {
+	secret, err := a.totpSecret(ctx, mfa)
+	if err != nil {
+		return err
+	}
+
+	step, ok := auth.ValidateTOTP(secret, code, time.Now())
+	if !ok {
+		return domain.ErrInvalidMFACode
+	}
+
+	fresh, err := a.mfaRepo.UseStep(ctx, mfa.UserID, step)
+	if err != nil {
+		return err
+	}
+	if !fresh {
+		return domain.ErrInvalidMFACode
+	}
+
+	return nil
+}
+
+// totpSecret decrypts the stored secret. One still in plaintext, e.g. written
+// by an older instance during a rollout, is encrypted on the spot.
+func (a *authUseCase) totpSecret(ctx context.Context, mfa *domain.MFA) (string, error) {
+	if auth.IsSealed(mfa.Secret) {
+		return a.cfg.TOTPSecrets.Open(mfa.Secret, mfa.UserID.String())
+	}
+
+	if err := a.sealTOTPSecret(ctx, mfa); err != nil {
+		log.Printf("failed to encrypt TOTP secret for %s: %v", mfa.UserID, err)
+	}
+	return mfa.Secret, nil
+}
+
+func (a *authUseCase) sealTOTPSecret(ctx context.Context, mfa *domain.MFA) error {
+	sealed, err := a.cfg.TOTPSecrets.Seal(mfa.Secret, mfa.UserID.String())
+	if err != nil {
+		return err
+	}
+	return a.mfaRepo.ReplaceSecret(ctx, mfa.UserID, mfa.Secret, sealed)
+}
+
+func (a *authUseCase) SealMFASecrets(ctx context.Context) error {
+	pending, err := a.mfaRepo.ListPlaintextSecrets(ctx)
+	if err != nil {
+		return err
+	}
+
+	for i := range pending {
+		if err := a.sealTOTPSecret(ctx, &pending[i]); err != nil {
+			return err
+		}
+	}
+
+	if len(pending) > 0 {
+		log.Printf("encrypted %d stored TOTP secrets", len(pending))
+	}
+	return nil
+}
+
+var recoveryEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// generateRecoveryCodes returns codes formatted for display alongside the
+// hashes to store.
+func generateRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+
+	for i := range codes {
+		buf := make([]byte, 6)
+		if _, err := rand.Read(buf); err != nil {
+			return nil, nil, err
+		}
+
+		raw := strings.ToLower(recoveryEncoding.EncodeToString(buf))[:10]
+		codes[i] = raw[:5] + "-" + raw[5:]
+		hashes[i] = auth.HashToken(raw)
+	}
+
+	return codes, hashes, nil
+}
+
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(code)
+	code = strings.ReplaceAll(code, "-", "")
+	return strings.ReplaceAll(code, " ", "")
+}
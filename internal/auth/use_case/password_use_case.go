@@ -0,0 +1,127 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Ramsi97/edu-social-backend/internal/auth/domain"
+	cldInterface "github.com/Ramsi97/edu-social-backend/internal/shared/interfaces"
+	"github.com/Ramsi97/edu-social-backend/pkg/auth"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	passwordResetTokenTTL = time.Hour
+	minPasswordLength     = 8
+)
+
+// ForgotPassword answers the same for every address: unknown ones, throttled
+// requests and mail failures all look like a link went out, so the endpoint
+// can't be used to find out which addresses have accounts.
+func (a *authUseCase) ForgotPassword(ctx context.Context, email string) error {
+	user, err := a.userRepo.FindByEmail(ctx, email)
+	if err != nil {
+		return nil
+	}
+
+	if err := a.sendPasswordReset(ctx, user); err != nil {
+		log.Printf("password reset mail for %s not sent: %v", user.ID, err)
+	}
+	return nil
+}
+
+func (a *authUseCase) sendPasswordReset(ctx context.Context, user *domain.User) error {
+	if err := a.checkMailThrottle(ctx, user.ID, domain.TokenPurposePasswordReset); err != nil {
+		return err
+	}
+
+	raw, err := a.issueOneTimeToken(ctx, user.ID, domain.TokenPurposePasswordReset, passwordResetTokenTTL)
+	if err != nil {
+		return err
+	}
+
+	link := a.link("/reset-password", raw)
+
+	return a.mailer.Send(ctx, cldInterface.Mail{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nSomeone asked to reset the password for your account. Open the link below to choose a new one:\n\n%s\n\nThe link expires in %d minutes and can only be used once. If you didn't ask for this you can ignore this email.\n",
+			user.FirstName, link, int(passwordResetTokenTTL.Minutes()),
+		),
+	})
+}
+
+func (a *authUseCase) ResetPassword(ctx context.Context, token, newPassword string) error {
+	if err := validatePassword(newPassword); err != nil {
+		return err
+	}
+
+	consumed, err := a.tokenRepo.Consume(ctx, domain.TokenPurposePasswordReset, auth.HashToken(token))
+	if err != nil {
+		return err
+	}
+
+	return a.setPassword(ctx, consumed.UserID, newPassword)
+}
+
+func (a *authUseCase) ChangePassword(
+	ctx context.Context,
+	userID uuid.UUID,
+	oldPassword, newPassword string,
+	client domain.ClientInfo,
+) (*domain.TokenPair, error) {
+	user, err := a.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(oldPassword)); err != nil {
+		return nil, domain.ErrInvalidCredentials
+	}
+
+	if err := validatePassword(newPassword); err != nil {
+		return nil, err
+	}
+
+	if err := a.setPassword(ctx, userID, newPassword); err != nil {
+		return nil, err
+	}
+
+	return a.startSession(ctx, user, client)
+}
+
+// setPassword stores the new password and ends every session of the user,
+// along with any reset links still outstanding.
+func (a *authUseCase) setPassword(ctx context.Context, userID uuid.UUID, password string) error {
+	hashed, err := bcrypt.GenerateFromPassword([]byte(password), passwordHashCost)
+	if err != nil {
+		return err
+	}
+
+	if err := a.userRepo.UpdatePassword(ctx, userID, string(hashed)); err != nil {
+		return err
+	}
+
+	revoked, err := a.sessionRepo.RevokeAllSessions(ctx, userID)
+	if err != nil {
+		return err
+	}
+	a.notifySessionsRevoked(revoked...)
+
+	if err := a.tokenRepo.Invalidate(ctx, userID, domain.TokenPurposePasswordReset); err != nil {
+		log.Printf("failed to invalidate reset tokens for %s: %v", userID, err)
+	}
+
+	return nil
+}
+
+func validatePassword(password string) error {
+	if len(password) < minPasswordLength {
+		return domain.ErrWeakPassword
+	}
+	return nil
+}
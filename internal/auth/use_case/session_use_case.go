@@ -0,0 +1,241 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/Ramsi97/edu-social-backend/internal/auth/domain"
+	"github.com/Ramsi97/edu-social-backend/pkg/auth"
+	"github.com/google/uuid"
+)
+
+const (
+	accessTokenTTL  = 15 * time.Minute
+	refreshTokenTTL = 7 * 24 * time.Hour
+	sessionTTL      = 30 * 24 * time.Hour
+
+	// lastSeenResolution limits how often an authenticated request writes
+	// the session's last_seen_at.
+	lastSeenResolution = time.Minute
+)
+
+// startSession opens a new session (refresh token family) for the user and
+// issues its first token pair.
+func (a *authUseCase) startSession(ctx context.Context, user *domain.User, client domain.ClientInfo) (*domain.TokenPair, error) {
+	if user.Suspended() {
+		return nil, domain.ErrAccountSuspended
+	}
+
+	session := &domain.Session{
+		UserID:      user.ID,
+		DeviceLabel: client.DeviceLabel,
+		UserAgent:   client.UserAgent,
+		IPAddress:   client.IPAddress,
+		ExpiresAt:   time.Now().UTC().Add(sessionTTL),
+	}
+	if err := a.sessionRepo.CreateSession(ctx, session); err != nil {
+		return nil, err
+	}
+
+	return a.issueTokens(ctx, session, user.Role)
+}
+
+func (a *authUseCase) issueTokens(ctx context.Context, session *domain.Session, role domain.Role) (*domain.TokenPair, error) {
+	accessToken, err := auth.GenerateToken(session.UserID, session.ID, string(role), accessTokenTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, hash, err := auth.GenerateRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+
+	expiresAt := time.Now().UTC().Add(refreshTokenTTL)
+	if session.ExpiresAt.Before(expiresAt) {
+		expiresAt = session.ExpiresAt
+	}
+
+	refresh := &domain.RefreshToken{
+		SessionID: session.ID,
+		UserID:    session.UserID,
+		TokenHash: hash,
+		ExpiresAt: expiresAt,
+	}
+	if err := a.sessionRepo.CreateRefreshToken(ctx, refresh); err != nil {
+		return nil, err
+	}
+
+	return &domain.TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: raw,
+		ExpiresIn:    int64(accessTokenTTL.Seconds()),
+	}, nil
+}
+
+func (a *authUseCase) Refresh(ctx context.Context, refreshToken string) (*domain.TokenPair, error) {
+	token, err := a.sessionRepo.ConsumeRefreshToken(ctx, auth.HashToken(refreshToken))
+	if errors.Is(err, domain.ErrRefreshTokenReused) {
+		// A rotated token came back: assume it was stolen and kill the family.
+		if err := a.revokeSession(ctx, token.SessionID); err != nil {
+			return nil, err
+		}
+		return nil, domain.ErrRefreshTokenReused
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if time.Now().After(token.ExpiresAt) {
+		return nil, domain.ErrInvalidRefreshToken
+	}
+
+	session, err := a.activeSession(ctx, token.SessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := a.userRepo.FindByID(ctx, session.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.Suspended() {
+		return nil, domain.ErrAccountSuspended
+	}
+
+	return a.issueTokens(ctx, session, user.Role)
+}
+
+func (a *authUseCase) Logout(ctx context.Context, refreshToken string) error {
+	token, err := a.sessionRepo.FindRefreshToken(ctx, auth.HashToken(refreshToken))
+	if err != nil {
+		return err
+	}
+
+	return a.revokeSession(ctx, token.SessionID)
+}
+
+func (a *authUseCase) Authenticate(ctx context.Context, accessToken string) (*auth.Claims, error) {
+	claims, err := auth.ValidateToken(accessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	sessionID, err := uuid.Parse(claims.SessionID)
+	if err != nil {
+		return nil, errors.New("invalid session id")
+	}
+
+	session, err := a.activeSession(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if session.UserID.String() != claims.UserID {
+		return nil, domain.ErrSessionRevoked
+	}
+
+	// Suspensions and role changes apply at once, not when the token expires.
+	user, err := a.userRepo.FindByID(ctx, session.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.Suspended() {
+		return nil, domain.ErrAccountSuspended
+	}
+	claims.Role = string(user.Role)
+
+	if time.Since(session.LastSeenAt) > lastSeenResolution {
+		if err := a.sessionRepo.TouchSession(ctx, session.ID); err != nil {
+			log.Printf("failed to update last seen for session %s: %v", session.ID, err)
+		}
+	}
+
+	return claims, nil
+}
+
+func (a *authUseCase) ListSessions(ctx context.Context, userID, currentSessionID uuid.UUID) ([]domain.Session, error) {
+	sessions, err := a.sessionRepo.ListActiveSessions(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range sessions {
+		sessions[i].Current = sessions[i].ID == currentSessionID
+	}
+
+	return sessions, nil
+}
+
+func (a *authUseCase) EndSession(ctx context.Context, userID, sessionID uuid.UUID) error {
+	session, err := a.sessionRepo.GetSession(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+
+	// Don't reveal whether someone else's session ID exists.
+	if session.UserID != userID {
+		return domain.ErrSessionNotFound
+	}
+
+	return a.revokeSession(ctx, sessionID)
+}
+
+func (a *authUseCase) EndOtherSessions(ctx context.Context, userID, currentSessionID uuid.UUID) error {
+	revoked, err := a.sessionRepo.RevokeOtherSessions(ctx, userID, currentSessionID)
+	if err != nil {
+		return err
+	}
+
+	a.notifySessionsRevoked(revoked...)
+	return nil
+}
+
+func (a *authUseCase) OnSessionsRevoked(listener func(sessionIDs ...uuid.UUID)) {
+	a.listenersMu.Lock()
+	defer a.listenersMu.Unlock()
+
+	a.revokeListeners = append(a.revokeListeners, listener)
+}
+
+func (a *authUseCase) revokeSession(ctx context.Context, sessionID uuid.UUID) error {
+	if err := a.sessionRepo.RevokeSession(ctx, sessionID); err != nil {
+		return err
+	}
+
+	a.notifySessionsRevoked(sessionID)
+	return nil
+}
+
+func (a *authUseCase) notifySessionsRevoked(sessionIDs ...uuid.UUID) {
+	if len(sessionIDs) == 0 {
+		return
+	}
+
+	a.listenersMu.RLock()
+	defer a.listenersMu.RUnlock()
+
+	for _, listener := range a.revokeListeners {
+		listener(sessionIDs...)
+	}
+}
+
+func (a *authUseCase) activeSession(ctx context.Context, sessionID uuid.UUID) (*domain.Session, error) {
+	session, err := a.sessionRepo.GetSession(ctx, sessionID)
+	if err != nil {
+		if errors.Is(err, domain.ErrSessionNotFound) {
+			return nil, domain.ErrSessionRevoked
+		}
+		return nil, err
+	}
+
+	if !session.Active(time.Now()) {
+		return nil, domain.ErrSessionRevoked
+	}
+
+	return session, nil
+}
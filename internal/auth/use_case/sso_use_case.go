@@ -0,0 +1,177 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/Ramsi97/edu-social-backend/internal/auth/domain"
+	"github.com/Ramsi97/edu-social-backend/pkg/auth"
+	"github.com/Ramsi97/edu-social-backend/pkg/oidc"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const ssoStateTTL = 10 * time.Minute
+
+func (a *authUseCase) BeginSSO(ctx context.Context) (string, error) {
+	if a.idp == nil {
+		return "", domain.ErrSSODisabled
+	}
+
+	state, err := oidc.RandomString()
+	if err != nil {
+		return "", err
+	}
+	nonce, err := oidc.RandomString()
+	if err != nil {
+		return "", err
+	}
+	verifier, err := oidc.RandomString()
+	if err != nil {
+		return "", err
+	}
+
+	err = a.ssoRepo.SaveState(ctx, &domain.SSOState{
+		StateHash:    auth.HashToken(state),
+		Nonce:        nonce,
+		CodeVerifier: verifier,
+		ExpiresAt:    time.Now().UTC().Add(ssoStateTTL),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return a.idp.AuthCodeURL(ctx, state, nonce, oidc.S256Challenge(verifier))
+}
+
+func (a *authUseCase) CompleteSSO(
+	ctx context.Context,
+	code, state string,
+	client domain.ClientInfo,
+) (*domain.User, *domain.TokenPair, error) {
+	if a.idp == nil {
+		return nil, nil, domain.ErrSSODisabled
+	}
+
+	pending, err := a.ssoRepo.ConsumeState(ctx, auth.HashToken(state))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	identity, err := a.idp.Exchange(ctx, code, pending.CodeVerifier, pending.Nonce)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	user, err := a.resolveSSOUser(ctx, identity)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !user.EmailVerified() {
+		return nil, nil, domain.ErrEmailNotVerified
+	}
+
+	tokens, err := a.completeLogin(ctx, user, client)
+	if err != nil {
+		return user, nil, err
+	}
+
+	return user, tokens, nil
+}
+
+// resolveSSOUser finds the local account for a provider identity, linking or
+// creating one on first sign-in. An existing account is only linked when both
+// the provider and our own records say the email address has been verified;
+// otherwise anybody able to register that address at the provider could take
+// the account over.
+func (a *authUseCase) resolveSSOUser(ctx context.Context, identity *oidc.Identity) (*domain.User, error) {
+	linked, err := a.ssoRepo.FindIdentity(ctx, identity.Issuer, identity.Subject)
+	if err == nil {
+		return a.userRepo.FindByID(ctx, linked.UserID)
+	}
+	if !errors.Is(err, domain.ErrUserNotFound) {
+		return nil, err
+	}
+
+	email := strings.TrimSpace(identity.Email)
+	if email == "" || identity.StudentID == "" {
+		return nil, domain.ErrMissingSSOClaims
+	}
+
+	existing, err := a.userRepo.FindByEmail(ctx, email)
+	if err == nil {
+		if !identity.EmailVerified || !existing.EmailVerified() {
+			return nil, domain.ErrAccountLinkConflict
+		}
+		if err := a.linkIdentity(ctx, identity, existing); err != nil {
+			return nil, err
+		}
+		return existing, nil
+	}
+
+	if other, _ := a.userRepo.FindByStudentId(ctx, identity.StudentID); other != nil {
+		return nil, domain.ErrAccountLinkConflict
+	}
+
+	user, err := a.createSSOUser(ctx, identity, email)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := a.linkIdentity(ctx, identity, user); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+func (a *authUseCase) createSSOUser(ctx context.Context, identity *oidc.Identity, email string) (*domain.User, error) {
+	// SSO accounts get an unguessable password; the owner can set a real
+	// one through the forgot-password flow if they ever need it.
+	random, err := oidc.RandomString()
+	if err != nil {
+		return nil, err
+	}
+	hashed, err := bcrypt.GenerateFromPassword([]byte(random), passwordHashCost)
+	if err != nil {
+		return nil, err
+	}
+
+	user := &domain.User{
+		FirstName:  identity.FirstName,
+		LastName:   identity.LastName,
+		StudentID:  identity.StudentID,
+		Email:      email,
+		Password:   string(hashed),
+		JoinedYear: time.Now().Format("2006") + "-01-01",
+	}
+	if err := a.userRepo.Create(ctx, user); err != nil {
+		return nil, err
+	}
+
+	if identity.EmailVerified {
+		if err := a.userRepo.MarkEmailVerified(ctx, user.ID); err != nil {
+			return nil, err
+		}
+		now := time.Now().UTC()
+		user.EmailVerifiedAt = &now
+		return user, nil
+	}
+
+	if err := a.sendVerification(ctx, user); err != nil {
+		log.Printf("failed to send verification email to %s: %v", user.Email, err)
+	}
+	return user, nil
+}
+
+func (a *authUseCase) linkIdentity(ctx context.Context, identity *oidc.Identity, user *domain.User) error {
+	return a.ssoRepo.LinkIdentity(ctx, &domain.ExternalIdentity{
+		Issuer:  identity.Issuer,
+		Subject: identity.Subject,
+		UserID:  user.ID,
+		Email:   identity.Email,
+	})
+}
@@ -0,0 +1,131 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Ramsi97/edu-social-backend/internal/auth/domain"
+	cldInterface "github.com/Ramsi97/edu-social-backend/internal/shared/interfaces"
+	"github.com/Ramsi97/edu-social-backend/pkg/auth"
+	"github.com/google/uuid"
+)
+
+const (
+	verificationTokenTTL = 24 * time.Hour
+
+	// A user may request a new verification mail at most once per
+	// resendInterval and resendDailyLimit times a day.
+	resendInterval   = time.Minute
+	resendDailyLimit = 5
+)
+
+func (a *authUseCase) VerifyEmail(ctx context.Context, token string) error {
+	if token == "" {
+		return domain.ErrInvalidToken
+	}
+
+	consumed, err := a.tokenRepo.Consume(ctx, domain.TokenPurposeEmailVerification, auth.HashToken(token))
+	if err != nil {
+		return err
+	}
+
+	return a.userRepo.MarkEmailVerified(ctx, consumed.UserID)
+}
+
+// ResendVerification answers the same whether or not the address has an
+// account; throttled requests and mail failures are only logged.
+func (a *authUseCase) ResendVerification(ctx context.Context, email string) error {
+	user, err := a.userRepo.FindByEmail(ctx, email)
+	if err != nil || user.EmailVerified() {
+		return nil
+	}
+
+	if err := a.checkMailThrottle(ctx, user.ID, domain.TokenPurposeEmailVerification); err != nil {
+		log.Printf("verification mail for %s not sent: %v", user.ID, err)
+		return nil
+	}
+
+	if err := a.sendVerification(ctx, user); err != nil {
+		log.Printf("verification mail for %s not sent: %v", user.ID, err)
+	}
+	return nil
+}
+
+// sendVerification replaces any outstanding verification token of the user
+// with a fresh one and mails the link.
+func (a *authUseCase) sendVerification(ctx context.Context, user *domain.User) error {
+	raw, err := a.issueOneTimeToken(ctx, user.ID, domain.TokenPurposeEmailVerification, verificationTokenTTL)
+	if err != nil {
+		return err
+	}
+
+	link := a.link("/api/v1/auth/verify-email", raw)
+
+	return a.mailer.Send(ctx, cldInterface.Mail{
+		To:      user.Email,
+		Subject: "Verify your email address",
+		Body: fmt.Sprintf(
+			"Hi %s,\n\nPlease confirm your email address by opening the link below:\n\n%s\n\nThe link expires in %d hours. If you didn't create an account you can ignore this email.\n",
+			user.FirstName, link, int(verificationTokenTTL.Hours()),
+		),
+	})
+}
+
+// checkMailThrottle rejects a request for another mailed token when the
+// user asked for one too recently or too often today.
+func (a *authUseCase) checkMailThrottle(ctx context.Context, userID uuid.UUID, purpose string) error {
+	latest, err := a.tokenRepo.LatestCreatedAt(ctx, userID, purpose)
+	if err != nil {
+		return err
+	}
+	if latest != nil && time.Since(*latest) < resendInterval {
+		return domain.ErrTooManyRequests
+	}
+
+	sent, err := a.tokenRepo.CountSince(ctx, userID, purpose, time.Now().Add(-24*time.Hour))
+	if err != nil {
+		return err
+	}
+	if sent >= resendDailyLimit {
+		return domain.ErrTooManyRequests
+	}
+
+	return nil
+}
+
+// issueOneTimeToken invalidates the user's outstanding tokens for purpose
+// and stores a new one, returning the raw value to mail out.
+func (a *authUseCase) issueOneTimeToken(ctx context.Context, userID uuid.UUID, purpose string, ttl time.Duration) (string, error) {
+	if err := a.tokenRepo.DeleteExpired(ctx); err != nil {
+		log.Printf("failed to purge expired tokens: %v", err)
+	}
+
+	if err := a.tokenRepo.Invalidate(ctx, userID, purpose); err != nil {
+		return "", err
+	}
+
+	raw, hash, err := auth.GenerateRefreshToken()
+	if err != nil {
+		return "", err
+	}
+
+	token := &domain.OneTimeToken{
+		UserID:    userID,
+		Purpose:   purpose,
+		TokenHash: hash,
+		ExpiresAt: time.Now().UTC().Add(ttl),
+	}
+	if err := a.tokenRepo.Create(ctx, token); err != nil {
+		return "", err
+	}
+
+	return raw, nil
+}
+
+func (a *authUseCase) link(path, token string) string {
+	return strings.TrimRight(a.cfg.AppBaseURL, "/") + path + "?token=" + url.QueryEscape(token)
+}
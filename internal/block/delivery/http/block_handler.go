@@ -0,0 +1,108 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/Ramsi97/edu-social-backend/internal/block/domain"
+	"github.com/Ramsi97/edu-social-backend/pkg/response"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type blockHandler struct {
+	useCase domain.BlockUseCase
+}
+
+// NewBlockHandler mounts the block and mute lists under the /users group,
+// next to the rest of the caller's own account (/users/me/...).
+func NewBlockHandler(rg *gin.RouterGroup, uc domain.BlockUseCase) {
+	handler := &blockHandler{
+		useCase: uc,
+	}
+
+	rg.GET("/me/blocks", handler.ListBlocked)
+	rg.POST("/me/blocks/:user_id", handler.Block)
+	rg.DELETE("/me/blocks/:user_id", handler.Unblock)
+
+	rg.GET("/me/mutes", handler.ListMuted)
+	rg.POST("/me/mutes/:user_id", handler.Mute)
+	rg.DELETE("/me/mutes/:user_id", handler.Unmute)
+}
+
+func (b *blockHandler) Block(ctx *gin.Context) {
+	b.apply(ctx, b.useCase.Block, "User blocked")
+}
+
+func (b *blockHandler) Unblock(ctx *gin.Context) {
+	b.apply(ctx, b.useCase.Unblock, "User unblocked")
+}
+
+func (b *blockHandler) Mute(ctx *gin.Context) {
+	b.apply(ctx, b.useCase.Mute, "User muted")
+}
+
+func (b *blockHandler) Unmute(ctx *gin.Context) {
+	b.apply(ctx, b.useCase.Unmute, "User unmuted")
+}
+
+func (b *blockHandler) ListBlocked(ctx *gin.Context) {
+	b.list(ctx, b.useCase.ListBlocked, "Blocked users fetched")
+}
+
+func (b *blockHandler) ListMuted(ctx *gin.Context) {
+	b.list(ctx, b.useCase.ListMuted, "Muted users fetched")
+}
+
+func (b *blockHandler) apply(
+	ctx *gin.Context,
+	action func(ctx context.Context, userID, targetID uuid.UUID) error,
+	message string,
+) {
+	userID, err := uuid.Parse(ctx.GetString("user_id"))
+	if err != nil {
+		response.Error(ctx, http.StatusUnauthorized, "Invalid user ID", "")
+		return
+	}
+
+	targetID, err := uuid.Parse(ctx.Param("user_id"))
+	if err != nil {
+		response.Error(ctx, http.StatusBadRequest, "Invalid user ID", err.Error())
+		return
+	}
+
+	if err := action(ctx.Request.Context(), userID, targetID); err != nil {
+		switch {
+		case errors.Is(err, domain.ErrUserNotFound):
+			response.Error(ctx, http.StatusNotFound, "User not found", err.Error())
+		case errors.Is(err, domain.ErrCannotTargetSelf):
+			response.Error(ctx, http.StatusBadRequest, "Cannot target yourself", err.Error())
+		default:
+			response.Error(ctx, http.StatusInternalServerError, "Internal server Error", err.Error())
+		}
+		return
+	}
+
+	response.Success(ctx, http.StatusOK, message, nil)
+}
+
+func (b *blockHandler) list(
+	ctx *gin.Context,
+	fetch func(ctx context.Context, userID uuid.UUID) ([]domain.BlockedUser, error),
+	message string,
+) {
+	userID, err := uuid.Parse(ctx.GetString("user_id"))
+	if err != nil {
+		response.Error(ctx, http.StatusUnauthorized, "Invalid user ID", "")
+		return
+	}
+
+	users, err := fetch(ctx.Request.Context(), userID)
+	if err != nil {
+		response.Error(ctx, http.StatusInternalServerError, "Internal server Error", err.Error())
+		return
+	}
+
+	response.Success(ctx, http.StatusOK, message, users)
+}
@@ -0,0 +1,37 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrUserNotFound     = errors.New("user not found")
+	ErrCannotTargetSelf = errors.New("you cannot block or mute yourself")
+)
+
+// BlockedUser is an entry of the caller's block or mute list.
+type BlockedUser struct {
+	ID             uuid.UUID `json:"id"`
+	FirstName      string    `json:"first_name"`
+	LastName       string    `json:"last_name"`
+	ProfilePicture *string   `json:"profile_picture"`
+	Since          time.Time `json:"since"`
+}
+
+// BlockUseCase manages block and mute lists. A block works both ways: the
+// two users stop seeing each other's posts and comments and can no longer
+// like, comment on or message each other. A mute only keeps the muted
+// user's posts out of the muter's feeds.
+type BlockUseCase interface {
+	Block(ctx context.Context, userID, targetID uuid.UUID) error
+	Unblock(ctx context.Context, userID, targetID uuid.UUID) error
+	ListBlocked(ctx context.Context, userID uuid.UUID) ([]BlockedUser, error)
+
+	Mute(ctx context.Context, userID, targetID uuid.UUID) error
+	Unmute(ctx context.Context, userID, targetID uuid.UUID) error
+	ListMuted(ctx context.Context, userID uuid.UUID) ([]BlockedUser, error)
+}
@@ -0,0 +1,19 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/Ramsi97/edu-social-backend/internal/block/domain"
+	"github.com/google/uuid"
+)
+
+type BlockRepository interface {
+	// Block also drops any follow between the two users.
+	Block(ctx context.Context, blockerID, blockedID uuid.UUID) error
+	Unblock(ctx context.Context, blockerID, blockedID uuid.UUID) error
+	ListBlocked(ctx context.Context, blockerID uuid.UUID) ([]domain.BlockedUser, error)
+
+	Mute(ctx context.Context, muterID, mutedID uuid.UUID) error
+	Unmute(ctx context.Context, muterID, mutedID uuid.UUID) error
+	ListMuted(ctx context.Context, muterID uuid.UUID) ([]domain.BlockedUser, error)
+}
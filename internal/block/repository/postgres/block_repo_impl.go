@@ -0,0 +1,125 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/Ramsi97/edu-social-backend/internal/block/domain"
+	"github.com/Ramsi97/edu-social-backend/internal/block/repository/interfaces"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// foreignKeyViolation is returned when the target user does not exist.
+const foreignKeyViolation = "23503"
+
+type blockRepository struct {
+	db *sql.DB
+}
+
+func NewBlockRepository(db *sql.DB) interfaces.BlockRepository {
+	return &blockRepository{
+		db: db,
+	}
+}
+
+func (b *blockRepository) Block(ctx context.Context, blockerID, blockedID uuid.UUID) error {
+	tx, err := b.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO user_blocks (blocker_id, blocked_id)
+		VALUES ($1, $2)
+		ON CONFLICT DO NOTHING
+	`, blockerID, blockedID)
+	if err != nil {
+		return mapTargetError(err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		DELETE FROM follows
+		WHERE (follower_id = $1 AND followee_id = $2)
+			OR (follower_id = $2 AND followee_id = $1)
+	`, blockerID, blockedID)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (b *blockRepository) Unblock(ctx context.Context, blockerID, blockedID uuid.UUID) error {
+	query := `DELETE FROM user_blocks WHERE blocker_id = $1 AND blocked_id = $2`
+
+	_, err := b.db.ExecContext(ctx, query, blockerID, blockedID)
+	return err
+}
+
+func (b *blockRepository) ListBlocked(ctx context.Context, blockerID uuid.UUID) ([]domain.BlockedUser, error) {
+	return b.list(ctx, `
+		SELECT u.id, u.first_name, u.last_name, u.profile_picture, b.created_at
+		FROM user_blocks b
+		JOIN users u ON u.id = b.blocked_id
+		WHERE b.blocker_id = $1
+		ORDER BY b.created_at DESC
+	`, blockerID)
+}
+
+func (b *blockRepository) Mute(ctx context.Context, muterID, mutedID uuid.UUID) error {
+	query := `
+		INSERT INTO user_mutes (muter_id, muted_id)
+		VALUES ($1, $2)
+		ON CONFLICT DO NOTHING
+	`
+
+	_, err := b.db.ExecContext(ctx, query, muterID, mutedID)
+	return mapTargetError(err)
+}
+
+func (b *blockRepository) Unmute(ctx context.Context, muterID, mutedID uuid.UUID) error {
+	query := `DELETE FROM user_mutes WHERE muter_id = $1 AND muted_id = $2`
+
+	_, err := b.db.ExecContext(ctx, query, muterID, mutedID)
+	return err
+}
+
+func (b *blockRepository) ListMuted(ctx context.Context, muterID uuid.UUID) ([]domain.BlockedUser, error) {
+	return b.list(ctx, `
+		SELECT u.id, u.first_name, u.last_name, u.profile_picture, m.created_at
+		FROM user_mutes m
+		JOIN users u ON u.id = m.muted_id
+		WHERE m.muter_id = $1
+		ORDER BY m.created_at DESC
+	`, muterID)
+}
+
+func (b *blockRepository) list(ctx context.Context, query string, userID uuid.UUID) ([]domain.BlockedUser, error) {
+	rows, err := b.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := []domain.BlockedUser{}
+	for rows.Next() {
+		var u domain.BlockedUser
+		if err := rows.Scan(&u.ID, &u.FirstName, &u.LastName, &u.ProfilePicture, &u.Since); err != nil {
+			return nil, err
+		}
+		users = append(users, u)
+	}
+
+	return users, rows.Err()
+}
+
+func mapTargetError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == foreignKeyViolation {
+		return domain.ErrUserNotFound
+	}
+	return err
+}
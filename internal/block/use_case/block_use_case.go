@@ -0,0 +1,49 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/Ramsi97/edu-social-backend/internal/block/domain"
+	"github.com/Ramsi97/edu-social-backend/internal/block/repository/interfaces"
+	"github.com/google/uuid"
+)
+
+type blockUseCase struct {
+	repo interfaces.BlockRepository
+}
+
+func NewBlockUseCase(repo interfaces.BlockRepository) domain.BlockUseCase {
+	return &blockUseCase{
+		repo: repo,
+	}
+}
+
+func (u *blockUseCase) Block(ctx context.Context, userID, targetID uuid.UUID) error {
+	if userID == targetID {
+		return domain.ErrCannotTargetSelf
+	}
+	return u.repo.Block(ctx, userID, targetID)
+}
+
+func (u *blockUseCase) Unblock(ctx context.Context, userID, targetID uuid.UUID) error {
+	return u.repo.Unblock(ctx, userID, targetID)
+}
+
+func (u *blockUseCase) ListBlocked(ctx context.Context, userID uuid.UUID) ([]domain.BlockedUser, error) {
+	return u.repo.ListBlocked(ctx, userID)
+}
+
+func (u *blockUseCase) Mute(ctx context.Context, userID, targetID uuid.UUID) error {
+	if userID == targetID {
+		return domain.ErrCannotTargetSelf
+	}
+	return u.repo.Mute(ctx, userID, targetID)
+}
+
+func (u *blockUseCase) Unmute(ctx context.Context, userID, targetID uuid.UUID) error {
+	return u.repo.Unmute(ctx, userID, targetID)
+}
+
+func (u *blockUseCase) ListMuted(ctx context.Context, userID uuid.UUID) ([]domain.BlockedUser, error) {
+	return u.repo.ListMuted(ctx, userID)
+}
@@ -1,10 +1,13 @@
 package http
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/Ramsi97/edu-social-backend/internal/chat/domain"
+	mediaDomain "github.com/Ramsi97/edu-social-backend/internal/media/domain"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
 type ChatHandler struct {
@@ -25,7 +28,21 @@ func (h *ChatHandler) SendMessage(ctx *gin.Context) {
 		return
 	}
 
-	if err := h.usecase.SendMessage(ctx, &msg); err != nil {
+	// The sender is always the authenticated user, never the request body.
+	senderID, err := uuid.Parse(ctx.GetString("user_id"))
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "invalid user id"})
+		return
+	}
+	msg.SenderID = senderID
+
+	if err := h.usecase.SendMessage(ctx, &msg); errors.Is(err, domain.ErrBlocked) || errors.Is(err, domain.ErrNotAllowed) {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	} else if errors.Is(err, mediaDomain.ErrMediaNotFound) || errors.Is(err, domain.ErrRecipientRequired) {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	} else if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -34,8 +51,23 @@ func (h *ChatHandler) SendMessage(ctx *gin.Context) {
 }
 
 func (h *ChatHandler) GetMessages(ctx *gin.Context) {
-	roomID := ctx.Param("room_id")
-	messages, err := h.usecase.GetMessages(ctx, roomID)
+	roomID, err := uuid.Parse(ctx.Param("room_id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "invalid room id"})
+		return
+	}
+
+	viewerID, err := uuid.Parse(ctx.GetString("user_id"))
+	if err != nil {
+		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "invalid user id"})
+		return
+	}
+
+	messages, err := h.usecase.GetMessages(ctx, roomID, viewerID)
+	if errors.Is(err, domain.ErrRoomNotFound) {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -2,11 +2,30 @@ package domain
 
 import (
 	"context"
+	"errors"
 	"time"
 
+	mediaDomain "github.com/Ramsi97/edu-social-backend/internal/media/domain"
+	mentionDomain "github.com/Ramsi97/edu-social-backend/internal/mention/domain"
 	"github.com/google/uuid"
 )
 
+// ErrBlocked is returned when the sender and another member of the room have
+// blocked one another.
+var ErrBlocked = errors.New("you cannot send messages to this user")
+
+// ErrRecipientRequired is returned when a message would open a room without
+// naming who it is for.
+var ErrRecipientRequired = errors.New("recipient_id is required to start a conversation")
+
+// ErrRoomNotFound is returned when the user has no part in the room, so
+// rooms other people talk in can't be told apart from missing ones.
+var ErrRoomNotFound = errors.New("chat room not found")
+
+// ErrNotAllowed is returned when a recipient's who_can_message setting
+// leaves the sender out.
+var ErrNotAllowed = errors.New("this user does not accept messages from you")
+
 // Message represents a chat message
 type Message struct {
 	ID        uuid.UUID `json:"id"`
@@ -14,18 +33,35 @@ type Message struct {
 	RoomID    uuid.UUID `json:"room_id"`
 	Content   string    `json:"content"`
 	CreatedAt time.Time `json:"created_at"`
+	// RecipientID names who a conversation is opened with. Rooms have no
+	// member list, so it is kept with the message and is how a recipient
+	// who has not replied yet is known.
+	RecipientID *uuid.UUID `json:"recipient_id,omitempty"`
+	// MediaID attaches a finalized upload; Media is what it resolved to.
+	MediaID *uuid.UUID         `json:"media_id,omitempty"`
+	Media   *mediaDomain.Media `json:"media,omitempty"`
+	// Mentions are the users @mentioned in Content.
+	Mentions mentionDomain.Mentions `json:"mentions"`
 }
 
 // ChatRepository defines repository actions
 type ChatRepository interface {
-	GetChatHistory(ctx context.Context, roomID string) ([]Message, error)
+	GetChatHistory(ctx context.Context, roomID uuid.UUID) ([]Message, error)
 	SaveMessage(ctx context.Context, msg Message) error
+	// Participants lists everyone but excludeID who has written in the room
+	// or been sent a message there.
+	Participants(ctx context.Context, roomID, excludeID uuid.UUID) ([]uuid.UUID, error)
+	// IsMember reports whether the user has written in the room or been
+	// sent a message there.
+	IsMember(ctx context.Context, roomID, userID uuid.UUID) (bool, error)
 }
 
 // ChatUseCase defines the business logic layer
 type ChatUseCase interface {
 	SendMessage(ctx context.Context, msg *Message) error
-	GetMessages(ctx context.Context, roomID string) ([]Message, error)
+	// GetMessages returns ErrRoomNotFound unless the viewer is a member of
+	// the room.
+	GetMessages(ctx context.Context, roomID, viewerID uuid.UUID) ([]Message, error)
 }
 
 // ChatError is a custom error for chat validation
@@ -6,6 +6,8 @@ import (
 	"time"
 
 	"github.com/Ramsi97/edu-social-backend/internal/chat/domain"
+	notificationDomain "github.com/Ramsi97/edu-social-backend/internal/notification/domain"
+	sharedPostgres "github.com/Ramsi97/edu-social-backend/internal/shared/infrastructure/postgres"
 	"github.com/google/uuid"
 )
 
@@ -17,7 +19,18 @@ func NewChatRepository(db *sql.DB) domain.ChatRepository {
 	return &chatRepo{db: db}
 }
 
-// SaveMessage saves a chat message in DB
+// roomMembers lists everyone who has written in room $1 or been sent a
+// message there.
+const roomMembers = `
+	SELECT sender_id AS user_id FROM chat_messages WHERE room_id = $1
+	UNION
+	SELECT recipient_id FROM chat_messages WHERE room_id = $1 AND recipient_id IS NOT NULL
+`
+
+// SaveMessage saves a chat message in DB. The insert is refused when the
+// message would reach nobody but the sender, since there is then nobody to
+// check blocks against, and when anyone in the room, or the recipient the
+// message is addressed to, is on either side of a block with the sender.
 func (r *chatRepo) SaveMessage(ctx context.Context, msg domain.Message) error {
 	if msg.ID == uuid.Nil {
 		msg.ID = uuid.New()
@@ -26,18 +39,51 @@ func (r *chatRepo) SaveMessage(ctx context.Context, msg domain.Message) error {
 		msg.CreatedAt = time.Now()
 	}
 
-	_, err := r.db.ExecContext(ctx,
-		`INSERT INTO chat_messages (id, sender_id, room_id, content, created_at) 
-		 VALUES ($1, $2, $3, $4, $5)`,
-		msg.ID, msg.SenderID, msg.RoomID, msg.Content, msg.CreatedAt,
-	)
-	return err
+	var inserted, addressed bool
+	err := r.db.QueryRowContext(ctx,
+		`WITH members AS (
+			SELECT user_id FROM (`+roomMembers+`
+				UNION
+				SELECT $6::uuid WHERE $6::uuid IS NOT NULL
+			) m
+			WHERE user_id <> $3
+		 ),
+		 saved AS (
+			INSERT INTO chat_messages (id, room_id, sender_id, content, created_at, recipient_id, media_id)
+			SELECT $2, $1, $3, $4, $5, $6, $7
+			WHERE EXISTS (SELECT 1 FROM members)
+			AND NOT EXISTS (
+				SELECT 1
+				FROM members m
+				JOIN user_blocks b
+					ON (b.blocker_id = $3 AND b.blocked_id = m.user_id)
+					OR (b.blocker_id = m.user_id AND b.blocked_id = $3)
+			)
+			RETURNING id
+		 )
+		 SELECT EXISTS (SELECT 1 FROM saved), EXISTS (SELECT 1 FROM members)`,
+		msg.RoomID, msg.ID, msg.SenderID, msg.Content, msg.CreatedAt, msg.RecipientID, msg.MediaID,
+	).Scan(&inserted, &addressed)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case inserted:
+		return nil
+	case !addressed:
+		return domain.ErrRecipientRequired
+	default:
+		return domain.ErrBlocked
+	}
 }
 
 // GetChatHistory retrieves messages for a room
-func (r *chatRepo) GetChatHistory(ctx context.Context, roomID string) ([]domain.Message, error) {
+func (r *chatRepo) GetChatHistory(ctx context.Context, roomID uuid.UUID) ([]domain.Message, error) {
 	rows, err := r.db.QueryContext(ctx,
-		`SELECT id, sender_id, room_id, content, created_at 
+		`SELECT id, sender_id, room_id, content, created_at, media_id,
+			`+sharedPostgres.MediaColumn("media_id")+`,
+			`+sharedPostgres.MentionsColumn(string(notificationDomain.SourceMessage), "chat_messages.id")+`
 		 FROM chat_messages 
 		 WHERE room_id=$1
 		 ORDER BY created_at ASC`, roomID)
@@ -49,10 +95,40 @@ func (r *chatRepo) GetChatHistory(ctx context.Context, roomID string) ([]domain.
 	messages := []domain.Message{}
 	for rows.Next() {
 		var msg domain.Message
-		if err := rows.Scan(&msg.ID, &msg.SenderID, &msg.RoomID, &msg.Content, &msg.CreatedAt); err != nil {
+		if err := rows.Scan(&msg.ID, &msg.SenderID, &msg.RoomID, &msg.Content, &msg.CreatedAt, &msg.MediaID, &msg.Media, &msg.Mentions); err != nil {
 			return nil, err
 		}
 		messages = append(messages, msg)
 	}
 	return messages, nil
 }
+
+func (r *chatRepo) Participants(ctx context.Context, roomID, excludeID uuid.UUID) ([]uuid.UUID, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT user_id FROM (`+roomMembers+`) members
+		 WHERE user_id <> $2`, roomID, excludeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	participants := []uuid.UUID{}
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		participants = append(participants, id)
+	}
+	return participants, rows.Err()
+}
+
+func (r *chatRepo) IsMember(ctx context.Context, roomID, userID uuid.UUID) (bool, error) {
+	var member bool
+	err := r.db.QueryRowContext(ctx,
+		`SELECT EXISTS (
+			SELECT 1 FROM (`+roomMembers+`) members
+			WHERE user_id = $2
+		 )`, roomID, userID).Scan(&member)
+	return member, err
+}
@@ -2,26 +2,31 @@ package socket
 
 import (
 	"context"
+	"errors"
 	"log"
 
+	authDomain "github.com/Ramsi97/edu-social-backend/internal/auth/domain"
 	"github.com/Ramsi97/edu-social-backend/internal/chat/domain"
-	"github.com/Ramsi97/edu-social-backend/pkg/auth"
+	"github.com/Ramsi97/edu-social-backend/pkg/websocket"
 	"github.com/google/uuid"
 	"github.com/zishang520/socket.io/v2/socket"
 )
 
 type SocketHandler struct {
-	io          *socket.Server
-	chatUsecase domain.ChatUseCase
+	io            *socket.Server
+	chatUsecase   domain.ChatUseCase
+	authenticator authDomain.Authenticator
 }
 
 func NewSocketHandler(
 	io *socket.Server,
 	chatUC domain.ChatUseCase,
+	authenticator authDomain.Authenticator,
 ) *SocketHandler {
 	return &SocketHandler{
-		io:          io,
-		chatUsecase: chatUC,
+		io:            io,
+		chatUsecase:   chatUC,
+		authenticator: authenticator,
 	}
 }
 
@@ -93,6 +98,24 @@ func (h *SocketHandler) RegisterEvents() {
 				Content:  content,
 			}
 
+			if recipientStr, _ := payload["recipient_id"].(string); recipientStr != "" {
+				recipientID, err := uuid.Parse(recipientStr)
+				if err != nil {
+					client.Emit("error", "invalid recipient id")
+					return
+				}
+				msg.RecipientID = &recipientID
+			}
+
+			if mediaStr, _ := payload["media_id"].(string); mediaStr != "" {
+				mediaID, err := uuid.Parse(mediaStr)
+				if err != nil {
+					client.Emit("error", "invalid media id")
+					return
+				}
+				msg.MediaID = &mediaID
+			}
+
 			if err := h.chatUsecase.SendMessage(context.Background(), msg); err != nil {
 				client.Emit("error", err.Error())
 				return
@@ -132,14 +155,21 @@ func (h *SocketHandler) RegisterMiddleWare() {
 			return
 		}
 
-		userID, err := auth.ValidateToken(token)
+		claims, err := h.authenticator.Authenticate(context.Background(), token)
+
+		if errors.Is(err, authDomain.ErrAccountSuspended) {
+			next(socket.NewExtendedError("Account suspended", nil))
+			return
+		}
 
 		if err != nil {
 			next(socket.NewExtendedError("Invalid token", nil))
 			return
 		}
 
-		s.SetData(userID)
+		s.Join(socket.Room(websocket.SessionRoom(claims.SessionID)))
+		s.Join(socket.Room(websocket.UserRoom(claims.UserID)))
+		s.SetData(claims.UserID)
 
 		next(nil)
 	})
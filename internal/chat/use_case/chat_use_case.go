@@ -2,25 +2,161 @@ package usecase
 
 import (
 	"context"
+	"log"
+	"time"
 
 	"github.com/Ramsi97/edu-social-backend/internal/chat/domain"
+	mediaDomain "github.com/Ramsi97/edu-social-backend/internal/media/domain"
+	mentionDomain "github.com/Ramsi97/edu-social-backend/internal/mention/domain"
+	notificationDomain "github.com/Ramsi97/edu-social-backend/internal/notification/domain"
+	sharedInterfaces "github.com/Ramsi97/edu-social-backend/internal/shared/interfaces"
+	userDomain "github.com/Ramsi97/edu-social-backend/internal/user/domain"
+	"github.com/google/uuid"
 )
 
 type chatUseCase struct {
-	repo domain.ChatRepository
+	repo     domain.ChatRepository
+	privacy  userDomain.PrivacyPolicy
+	uploads  mediaDomain.MediaUseCase
+	mentions mentionDomain.MentionUseCase
 }
 
-func NewChatUseCase(r domain.ChatRepository) domain.ChatUseCase {
-	return &chatUseCase{repo: r}
+func NewChatUseCase(
+	r domain.ChatRepository,
+	privacy userDomain.PrivacyPolicy,
+	uploads mediaDomain.MediaUseCase,
+	mentions mentionDomain.MentionUseCase,
+) domain.ChatUseCase {
+	return &chatUseCase{repo: r, privacy: privacy, uploads: uploads, mentions: mentions}
 }
 
 func (u *chatUseCase) SendMessage(ctx context.Context, msg *domain.Message) error {
-	if msg.Content == "" {
+	if msg.Content == "" && msg.MediaID == nil {
 		return &domain.ChatError{Message: "message cannot be empty"}
 	}
-	return u.repo.SaveMessage(ctx, *msg)
+
+	recipients, err := u.checkRecipients(ctx, msg)
+	if err != nil {
+		return err
+	}
+
+	// The ID is set here rather than by the repository so the message
+	// handed back to the caller carries it.
+	msg.ID = uuid.New()
+	msg.CreatedAt = time.Now()
+
+	// Media is only ever what the server resolved MediaID to.
+	msg.Media = nil
+	if msg.MediaID == nil {
+		if err := u.repo.SaveMessage(ctx, *msg); err != nil {
+			return err
+		}
+		u.recordMentions(ctx, msg, recipients)
+		return nil
+	}
+
+	ids := []uuid.UUID{*msg.MediaID}
+	claimed, err := u.uploads.Claim(ctx, msg.SenderID, sharedInterfaces.PurposeMessage, ids)
+	if err != nil {
+		return err
+	}
+	msg.Media = &claimed[0]
+
+	if err := u.repo.SaveMessage(ctx, *msg); err != nil {
+		u.uploads.Release(ctx, msg.SenderID, claimed)
+		return err
+	}
+	u.recordMentions(ctx, msg, recipients)
+
+	// Attachments are private to the room, so they go out as signed links.
+	if err := u.uploads.Sign(ctx, msg.Media); err != nil {
+		log.Printf("failed to sign media of message %s: %v", msg.ID, err)
+	}
+	return nil
+}
+
+// recordMentions links the message's @handles once it is saved. Only the
+// people the message reaches are notified. A failure is only logged.
+func (u *chatUseCase) recordMentions(ctx context.Context, msg *domain.Message, recipients map[uuid.UUID]bool) {
+	mentions, err := u.mentions.Record(ctx, mentionDomain.Source{
+		Source: notificationDomain.Source{
+			Type:     notificationDomain.SourceMessage,
+			ID:       msg.ID,
+			ParentID: msg.RoomID,
+		},
+		AuthorID: msg.SenderID,
+		Text:     msg.Content,
+		CanRead: func(ctx context.Context, userID uuid.UUID) (bool, error) {
+			return recipients[userID], nil
+		},
+	})
+	if err != nil {
+		log.Printf("failed to record mentions in message %s: %v", msg.ID, err)
+		mentions = mentionDomain.Mentions{}
+	}
+	msg.Mentions = mentions
+}
+
+// checkRecipients applies the who_can_message setting of everyone the
+// message would reach: the room's earlier participants and, when opening a
+// conversation, the named recipient. A message reaching nobody else is
+// refused, so the check can't be skipped by leaving the recipient out. It
+// returns who the recipients are.
+func (u *chatUseCase) checkRecipients(ctx context.Context, msg *domain.Message) (map[uuid.UUID]bool, error) {
+	recipients, err := u.repo.Participants(ctx, msg.RoomID, msg.SenderID)
+	if err != nil {
+		return nil, err
+	}
+
+	if msg.RecipientID != nil && *msg.RecipientID != msg.SenderID {
+		recipients = append(recipients, *msg.RecipientID)
+	}
+	if len(recipients) == 0 {
+		return nil, domain.ErrRecipientRequired
+	}
+
+	seen := make(map[uuid.UUID]bool, len(recipients))
+	for _, recipient := range recipients {
+		if seen[recipient] {
+			continue
+		}
+		seen[recipient] = true
+
+		allowed, err := u.privacy.Allowed(ctx, msg.SenderID, recipient, userDomain.ActionMessage)
+		if err != nil {
+			return nil, err
+		}
+		if !allowed {
+			return nil, domain.ErrNotAllowed
+		}
+	}
+
+	return seen, nil
 }
 
-func (u *chatUseCase) GetMessages(ctx context.Context, roomID string) ([]domain.Message, error) {
-	return u.repo.GetChatHistory(ctx, roomID)
+func (u *chatUseCase) GetMessages(ctx context.Context, roomID, viewerID uuid.UUID) ([]domain.Message, error) {
+	// Checked before anything is loaded or signed: the history carries
+	// links to the room's private attachments.
+	member, err := u.repo.IsMember(ctx, roomID, viewerID)
+	if err != nil {
+		return nil, err
+	}
+	if !member {
+		return nil, domain.ErrRoomNotFound
+	}
+
+	messages, err := u.repo.GetChatHistory(ctx, roomID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range messages {
+		if messages[i].Media == nil {
+			continue
+		}
+		if err := u.uploads.Sign(ctx, messages[i].Media); err != nil {
+			return nil, err
+		}
+	}
+	return messages, nil
 }
@@ -1,11 +1,16 @@
 package http
 
 import (
+	"errors"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/Ramsi97/edu-social-backend/internal/comment/domain"
+	mediaDomain "github.com/Ramsi97/edu-social-backend/internal/media/domain"
 	"github.com/Ramsi97/edu-social-backend/pkg/response"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
 type commentHandler struct {
@@ -31,7 +36,19 @@ func (h *commentHandler) Comment(c *gin.Context) {
 
 	userID := c.GetString("user_id")
 
-	err := h.usecase.Create(c, userID, req.PostID, req.Content)
+	err := h.usecase.Create(c, userID, req.PostID, req.Content, req.MediaID)
+	if errors.Is(err, mediaDomain.ErrMediaNotFound) {
+		response.Error(c, http.StatusBadRequest, "Invalid media", err.Error())
+		return
+	}
+	if errors.Is(err, domain.ErrPostNotFound) {
+		response.Error(c, http.StatusNotFound, "Post not found", err.Error())
+		return
+	}
+	if errors.Is(err, domain.ErrNotAllowed) {
+		response.Error(c, http.StatusForbidden, "Comments restricted", err.Error())
+		return
+	}
 	if err != nil {
 		response.Error(c, http.StatusInternalServerError, "Server Failure", err.Error())
 		return
@@ -65,7 +82,39 @@ func (h *commentHandler) GetByPostID(c *gin.Context) {
 		return
 	}
 
-	comments, err := h.usecase.GetByPostID(c.Request.Context(), postID)
+	userID := c.GetString("user_id")
+
+	q := domain.ListQuery{}
+	if v := c.Query("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil {
+			response.Error(c, http.StatusBadRequest, "Invalid limit", err.Error())
+			return
+		}
+		q.Limit = limit
+	}
+	if v := c.Query("after"); v != "" {
+		after, err := time.Parse(time.RFC3339Nano, v)
+		if err != nil {
+			response.Error(c, http.StatusBadRequest, "Invalid after cursor", err.Error())
+			return
+		}
+		q.After = &after
+	}
+	if v := c.Query("after_id"); v != "" {
+		afterID, err := uuid.Parse(v)
+		if err != nil {
+			response.Error(c, http.StatusBadRequest, "Invalid after_id cursor", err.Error())
+			return
+		}
+		q.AfterID = &afterID
+	}
+
+	comments, err := h.usecase.GetByPostID(c.Request.Context(), userID, postID, q)
+	if errors.Is(err, domain.ErrPostNotFound) {
+		response.Error(c, http.StatusNotFound, "Post not found", err.Error())
+		return
+	}
 	if err != nil {
 		response.Error(c, http.StatusInternalServerError, "Server Error", err.Error())
 		return
@@ -5,15 +5,22 @@ import (
 	"errors"
 	"time"
 
+	mediaDomain "github.com/Ramsi97/edu-social-backend/internal/media/domain"
+	mentionDomain "github.com/Ramsi97/edu-social-backend/internal/mention/domain"
 	"github.com/google/uuid"
 )
 
-var ErrCommentNotFound = errors.New("comment not found")
+var (
+	ErrCommentNotFound = errors.New("comment not found")
+	ErrPostNotFound    = errors.New("post not found")
+	ErrNotAllowed      = errors.New("the author does not accept comments from you")
+)
 
 type User struct {
-	Name           string    `json:"author_name"`
-	UserID         uuid.UUID `json:"user_id"`
-	ProfilePicture string    `json:"profile_picture"`
+	Name                   string                `json:"author_name"`
+	UserID                 uuid.UUID             `json:"user_id"`
+	ProfilePicture         string                `json:"profile_picture"`
+	ProfilePictureVariants *mediaDomain.Variants `json:"profile_picture_variants,omitempty"`
 }
 type Comment struct {
 	ID        uuid.UUID `json:"id"`
@@ -21,16 +28,36 @@ type Comment struct {
 	User      User      `json:"user"`
 	PostID    uuid.UUID `json:"post_id"`
 	CreatedAT time.Time `json:"created_at"`
+	// MediaID is the finalized upload attached to the comment, if any.
+	MediaID *uuid.UUID         `json:"-"`
+	Media   *mediaDomain.Media `json:"media,omitempty"`
+	// Mentions are the users @mentioned in Content.
+	Mentions mentionDomain.Mentions `json:"mentions"`
 }
 
 type CommentRequest struct {
-	Content string `json:"content"`
-	UserID  string `json:"user_id"`
-	PostID  string `json:"post_id"`
+	Content string     `json:"content"`
+	UserID  string     `json:"user_id"`
+	PostID  string     `json:"post_id"`
+	MediaID *uuid.UUID `json:"media_id"`
+}
+
+// ListQuery pages through a post's comments oldest first; After and AfterID
+// are the created_at and id of the last comment of the previous page, so
+// comments sharing a timestamp aren't lost at a page boundary. Without
+// AfterID the page starts after every comment made at After. A zero Limit
+// with no After lists them all.
+type ListQuery struct {
+	After   *time.Time
+	AfterID *uuid.UUID
+	Limit   int
 }
 
 type CommentUseCase interface {
-	Create(ctx context.Context, userID, postID, content string) error
+	// Create may leave content empty when a finalized upload is attached.
+	Create(ctx context.Context, userID, postID, content string, mediaID *uuid.UUID) error
 	Delete(ctx context.Context, userID, commentID string) error
-	GetByPostID(ctx context.Context, postID string) ([]Comment, error)
+	// GetByPostID returns ErrPostNotFound when the user may not see the
+	// post.
+	GetByPostID(ctx context.Context, userID, postID string, q ListQuery) ([]Comment, error)
 }
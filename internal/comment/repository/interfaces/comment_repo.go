@@ -9,7 +9,12 @@ import (
 
 type CommentRepository interface {
 	Create(ctx context.Context,comment *domain.Comment) error
-	Delete(ctx context.Context, commentID uuid.UUID) error
-	GetByPostID(ctx context.Context, postID uuid.UUID) ([]domain.Comment, error)
+	// Delete returns the ID of the media the comment carried, if any.
+	Delete(ctx context.Context, commentID uuid.UUID) (*uuid.UUID, error)
+	GetByPostID(ctx context.Context, viewerID, postID uuid.UUID, q domain.ListQuery) ([]domain.Comment, error)
 	GetByID(ctx context.Context, commentID uuid.UUID) (domain.Comment, error)
+	PostAuthor(ctx context.Context, postID uuid.UUID) (uuid.UUID, error)
+	// CanViewPost reports whether the viewer may see the post and so its
+	// comments.
+	CanViewPost(ctx context.Context, viewerID, postID uuid.UUID) (bool, error)
 }
\ No newline at end of file
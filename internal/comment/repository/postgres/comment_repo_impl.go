@@ -7,6 +7,8 @@ import (
 
 	"github.com/Ramsi97/edu-social-backend/internal/comment/domain"
 	"github.com/Ramsi97/edu-social-backend/internal/comment/repository/interfaces"
+	notificationDomain "github.com/Ramsi97/edu-social-backend/internal/notification/domain"
+	sharedPostgres "github.com/Ramsi97/edu-social-backend/internal/shared/infrastructure/postgres"
 	"github.com/google/uuid"
 )
 
@@ -20,39 +22,54 @@ func NewCommentRepository(db *sql.DB) interfaces.CommentRepository {
 	}
 }
 
+// Create inserts the comment only if the post exists and the commenter can
+// see it, which rules out either side having blocked the other.
 func (c *commentRepository) Create(ctx context.Context, comment *domain.Comment) error {
 	query := `
-		INSERT INTO comments (id, content, user_id, post_id, created_at)
-		VALUES ($1, $2, $3, $4, $5)
+		INSERT INTO comments (id, content, user_id, post_id, created_at, media_id)
+		SELECT $1, $2, $3, p.id, $5, $6
+		FROM posts p
+		JOIN users u ON u.id = p.author_id
+		WHERE p.id = $4
+			AND p.deleted_at IS NULL
+			AND` + sharedPostgres.VisibleTo("u", "$3") + `
 	`
-	_, err := c.db.ExecContext(ctx, query,
+	res, err := c.db.ExecContext(ctx, query,
 		comment.ID,
 		comment.Content,
 		comment.User.UserID,
 		comment.PostID,
 		comment.CreatedAT,
+		comment.MediaID,
 	)
-	return err
-
-}
-
-func (c *commentRepository) Delete(ctx context.Context, commentID uuid.UUID) error {
-	
-	query := `DELETE FROM comments WHERE id = $1`
-	
-	res, err := c.db.ExecContext(ctx, query, commentID)
 	if err != nil {
 		return err
 	}
 
 	rows, _ := res.RowsAffected()
 	if rows == 0 {
-		return domain.ErrCommentNotFound
+		return domain.ErrPostNotFound
 	}
 
 	return nil
 }
 
+func (c *commentRepository) Delete(ctx context.Context, commentID uuid.UUID) (*uuid.UUID, error) {
+	
+	query := `DELETE FROM comments WHERE id = $1 RETURNING media_id`
+	
+	var mediaID *uuid.UUID
+	err := c.db.QueryRowContext(ctx, query, commentID).Scan(&mediaID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrCommentNotFound
+		}
+		return nil, err
+	}
+
+	return mediaID, nil
+}
+
 func (c *commentRepository) GetByID(ctx context.Context, commentID uuid.UUID) (domain.Comment, error) {
 	query := `
 		SELECT id, content, user_id, post_id, created_at 
@@ -77,7 +94,10 @@ func (c *commentRepository) GetByID(ctx context.Context, commentID uuid.UUID) (d
 
 	return comment, nil
 }
-func (c *commentRepository) GetByPostID(ctx context.Context, postID uuid.UUID) ([]domain.Comment, error) {
+
+// GetByPostID lists the post's comments, leaving out those written by users
+// on either side of a block with the viewer.
+func (c *commentRepository) GetByPostID(ctx context.Context, viewerID, postID uuid.UUID, q domain.ListQuery) ([]domain.Comment, error) {
 	query := `
 		SELECT 
 			c.id,
@@ -86,14 +106,26 @@ func (c *commentRepository) GetByPostID(ctx context.Context, postID uuid.UUID) (
 			c.created_at,
 			u.id AS user_id,
 			u.first_name || ' ' || u.last_name AS user_name,
-			u.profile_picture
+			COALESCE(u.profile_picture, ''),
+			` + sharedPostgres.VariantsColumn("u.profile_picture") + `,
+			` + sharedPostgres.MediaColumn("c.media_id") + `,
+			` + sharedPostgres.MentionsColumn(string(notificationDomain.SourceComment), "c.id") + `
 		FROM comments c
 		JOIN users u ON c.user_id = u.id
+		JOIN posts p ON p.id = c.post_id AND p.deleted_at IS NULL
 		WHERE c.post_id = $1
-		ORDER BY c.created_at ASC
+			AND NOT EXISTS (
+				SELECT 1 FROM user_blocks b
+				WHERE (b.blocker_id = $2 AND b.blocked_id = u.id)
+					OR (b.blocker_id = u.id AND b.blocked_id = $2)
+			)
+			AND ($3::timestamptz IS NULL
+				OR (c.created_at, c.id) > ($3, COALESCE($5::uuid, 'ffffffff-ffff-ffff-ffff-ffffffffffff')))
+		ORDER BY c.created_at ASC, c.id ASC
+		LIMIT NULLIF($4::int, 0)
 	`
 
-	rows, err := c.db.QueryContext(ctx, query, postID)
+	rows, err := c.db.QueryContext(ctx, query, postID, viewerID, q.After, q.Limit, q.AfterID)
 	if err != nil {
 		return nil, err
 	}
@@ -112,6 +144,9 @@ func (c *commentRepository) GetByPostID(ctx context.Context, postID uuid.UUID) (
 			&user.UserID,
 			&user.Name,
 			&user.ProfilePicture,
+			&user.ProfilePictureVariants,
+			&comment.Media,
+			&comment.Mentions,
 		)
 		if err != nil {
 			return nil, err
@@ -127,3 +162,35 @@ func (c *commentRepository) GetByPostID(ctx context.Context, postID uuid.UUID) (
 
 	return comments, nil
 }
+
+func (c *commentRepository) PostAuthor(ctx context.Context, postID uuid.UUID) (uuid.UUID, error) {
+	query := `SELECT author_id FROM posts WHERE id = $1 AND deleted_at IS NULL`
+
+	var authorID uuid.UUID
+	err := c.db.QueryRowContext(ctx, query, postID).Scan(&authorID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return uuid.Nil, domain.ErrPostNotFound
+		}
+		return uuid.Nil, err
+	}
+
+	return authorID, nil
+}
+
+func (c *commentRepository) CanViewPost(ctx context.Context, viewerID, postID uuid.UUID) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1
+			FROM posts p
+			JOIN users u ON u.id = p.author_id
+			WHERE p.id = $1
+				AND p.deleted_at IS NULL
+				AND` + sharedPostgres.VisibleTo("u", "$2") + `
+		)
+	`
+
+	var visible bool
+	err := c.db.QueryRowContext(ctx, query, postID, viewerID).Scan(&visible)
+	return visible, err
+}
@@ -3,27 +3,49 @@ package usecase
 import (
 	"context"
 	"errors"
+	"log"
 	"time"
 
 	"github.com/Ramsi97/edu-social-backend/internal/comment/domain"
 	"github.com/Ramsi97/edu-social-backend/internal/comment/repository/interfaces"
+	mediaDomain "github.com/Ramsi97/edu-social-backend/internal/media/domain"
+	mentionDomain "github.com/Ramsi97/edu-social-backend/internal/mention/domain"
+	notificationDomain "github.com/Ramsi97/edu-social-backend/internal/notification/domain"
+	sharedInterfaces "github.com/Ramsi97/edu-social-backend/internal/shared/interfaces"
+	userDomain "github.com/Ramsi97/edu-social-backend/internal/user/domain"
 	"github.com/google/uuid"
 )
 
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
 type commentUseCase struct {
-	repo interfaces.CommentRepository
+	repo     interfaces.CommentRepository
+	privacy  userDomain.PrivacyPolicy
+	uploads  mediaDomain.MediaUseCase
+	mentions mentionDomain.MentionUseCase
 }
 
-func NewCommentUseCase(repo interfaces.CommentRepository) domain.CommentUseCase {
+func NewCommentUseCase(
+	repo interfaces.CommentRepository,
+	privacy userDomain.PrivacyPolicy,
+	uploads mediaDomain.MediaUseCase,
+	mentions mentionDomain.MentionUseCase,
+) domain.CommentUseCase {
 	return &commentUseCase{
-		repo: repo,
+		repo:     repo,
+		privacy:  privacy,
+		uploads:  uploads,
+		mentions: mentions,
 	}
 }
 
 // Create implements domain.CommentUseCase.
-func (c *commentUseCase) Create(ctx context.Context, userID string, postID string, content string) error {
+func (c *commentUseCase) Create(ctx context.Context, userID string, postID string, content string, mediaID *uuid.UUID) error {
 	
-	if content == ""{
+	if content == "" && mediaID == nil {
 		return errors.New("comment content cannot be empty")
 	}
 
@@ -37,15 +59,78 @@ func (c *commentUseCase) Create(ctx context.Context, userID string, postID strin
 		return errors.New("invalid user id")
 	}
 
+	// Only a post the user can see can be commented on; anything else looks
+	// like a missing post, as it does for GetPost.
+	visible, err := c.repo.CanViewPost(ctx, uID, pID)
+	if err != nil {
+		return err
+	}
+	if !visible {
+		return domain.ErrPostNotFound
+	}
+
+	// The post author's who_can_comment setting decides who may comment.
+	authorID, err := c.repo.PostAuthor(ctx, pID)
+	if err != nil {
+		return err
+	}
+
+	allowed, err := c.privacy.Allowed(ctx, uID, authorID, userDomain.ActionComment)
+	if err != nil {
+		return err
+	}
+	if !allowed {
+		return domain.ErrNotAllowed
+	}
+
 	comment := domain.Comment{
 		ID: uuid.New(),
 		User: domain.User{UserID: uID},
 		PostID: pID,
 		Content: content,
 		CreatedAT: time.Now(),
+		MediaID: mediaID,
+	}
+
+	if mediaID == nil {
+		if err := c.repo.Create(ctx, &comment); err != nil {
+			return err
+		}
+		c.recordMentions(ctx, &comment)
+		return nil
+	}
+
+	ids := []uuid.UUID{*mediaID}
+	claimed, err := c.uploads.Claim(ctx, uID, sharedInterfaces.PurposeComment, ids)
+	if err != nil {
+		return err
+	}
+	if err := c.repo.Create(ctx, &comment); err != nil {
+		c.uploads.Release(ctx, uID, claimed)
+		return err
 	}
+	c.recordMentions(ctx, &comment)
+	return nil
+}
 
-	return c.repo.Create(ctx, &comment)
+// recordMentions links the comment's @handles once it is saved, notifying
+// those who can see the post. A failure is only logged.
+func (c *commentUseCase) recordMentions(ctx context.Context, comment *domain.Comment) {
+	_, err := c.mentions.Record(ctx, mentionDomain.Source{
+		Source: notificationDomain.Source{
+			Type:     notificationDomain.SourceComment,
+			ID:       comment.ID,
+			ParentID: comment.PostID,
+		},
+		AuthorID: comment.User.UserID,
+		Text:     comment.Content,
+		CanRead: func(ctx context.Context, userID uuid.UUID) (bool, error) {
+			return c.repo.CanViewPost(ctx, userID, comment.PostID)
+		},
+	})
+	if err != nil {
+		log.Printf("failed to record mentions in comment %s: %v", comment.ID, err)
+	}
 }
 
 func (c *commentUseCase) Delete(ctx context.Context,userID, commentID string) error {
@@ -69,19 +154,62 @@ func (c *commentUseCase) Delete(ctx context.Context,userID, commentID string) er
 		return errors.New("you are not authorized to delete this comment")
 	}
 
-	return c.repo.Delete(ctx, cID)
+	mediaID, err := c.repo.Delete(ctx, cID)
+	if err != nil {
+		return err
+	}
+
+	if mediaID != nil {
+		if err := c.uploads.Discard(ctx, *mediaID); err != nil {
+			log.Printf("failed to discard media %s of comment %s: %v", *mediaID, cID, err)
+		}
+	}
+	if err := c.mentions.Remove(ctx, notificationDomain.SourceComment, cID); err != nil {
+		log.Printf("failed to remove mentions in comment %s: %v", cID, err)
+	}
+	return nil
 }
 
-func (c *commentUseCase) GetByPostID(ctx context.Context, postID string) ([]domain.Comment, error) {
+func (c *commentUseCase) GetByPostID(ctx context.Context, userID, postID string, q domain.ListQuery) ([]domain.Comment, error) {
+	uID, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, errors.New("invalid user id")
+	}
+
 	pID, err := uuid.Parse(postID)
 	if err != nil {
 		return nil, errors.New("invalid post id")
 	}
 
-	comments, err := c.repo.GetByPostID(ctx, pID)
+	// Comments are only as visible as the post they are on.
+	visible, err := c.repo.CanViewPost(ctx, uID, pID)
+	if err != nil {
+		return nil, err
+	}
+	if !visible {
+		return nil, domain.ErrPostNotFound
+	}
+
+	comments, err := c.repo.GetByPostID(ctx, uID, pID, pageQuery(q))
 	if err != nil {
 		return nil, err
 	}
 
 	return comments, nil
 }
+
+// pageQuery bounds the page size. A request with neither a limit nor a
+// cursor still gets every comment, as clients written before paging expect.
+func pageQuery(q domain.ListQuery) domain.ListQuery {
+	if q.Limit <= 0 {
+		if q.After == nil {
+			q.Limit = 0
+			return q
+		}
+		q.Limit = defaultPageSize
+	}
+	if q.Limit > maxPageSize {
+		q.Limit = maxPageSize
+	}
+	return q
+}
@@ -0,0 +1,228 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Ramsi97/edu-social-backend/internal/follow/domain"
+	"github.com/Ramsi97/edu-social-backend/pkg/response"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type followHandler struct {
+	useCase domain.FollowUseCase
+}
+
+func NewFollowHandler(rg *gin.RouterGroup, uc domain.FollowUseCase) {
+	handler := &followHandler{
+		useCase: uc,
+	}
+
+	rg.POST("/:user_id", handler.Follow)
+	rg.DELETE("/:user_id", handler.Unfollow)
+	rg.GET("/:user_id/followers", handler.Followers)
+	rg.GET("/:user_id/following", handler.Following)
+	rg.DELETE("/followers/:user_id", handler.RemoveFollower)
+
+	rg.GET("/requests", handler.Requests)
+	rg.POST("/requests/:user_id/approve", handler.ApproveRequest)
+	rg.DELETE("/requests/:user_id", handler.RejectRequest)
+}
+
+func (f *followHandler) Follow(ctx *gin.Context) {
+	userID, targetID, ok := f.parseIDs(ctx)
+	if !ok {
+		return
+	}
+
+	status, err := f.useCase.Follow(ctx.Request.Context(), userID, targetID)
+	if err != nil {
+		writeFollowError(ctx, err)
+		return
+	}
+
+	message := "Successfully followed"
+	if status == domain.StatusPending {
+		message = "Follow request sent"
+	}
+
+	response.Success(ctx, http.StatusOK, message, gin.H{
+		"status": status,
+	})
+}
+
+func (f *followHandler) Unfollow(ctx *gin.Context) {
+	userID, targetID, ok := f.parseIDs(ctx)
+	if !ok {
+		return
+	}
+
+	if err := f.useCase.Unfollow(ctx.Request.Context(), userID, targetID); err != nil {
+		writeFollowError(ctx, err)
+		return
+	}
+
+	response.Success(ctx, http.StatusOK, "Successfully unfollowed", nil)
+}
+
+func (f *followHandler) RemoveFollower(ctx *gin.Context) {
+	userID, followerID, ok := f.parseIDs(ctx)
+	if !ok {
+		return
+	}
+
+	if err := f.useCase.RemoveFollower(ctx.Request.Context(), userID, followerID); err != nil {
+		writeFollowError(ctx, err)
+		return
+	}
+
+	response.Success(ctx, http.StatusOK, "Follower removed", nil)
+}
+
+func (f *followHandler) Followers(ctx *gin.Context) {
+	viewerID, userID, ok := f.parseIDs(ctx)
+	if !ok {
+		return
+	}
+
+	q, ok := parseListQuery(ctx)
+	if !ok {
+		return
+	}
+
+	users, err := f.useCase.Followers(ctx.Request.Context(), viewerID, userID, q)
+	if err != nil {
+		writeFollowError(ctx, err)
+		return
+	}
+
+	response.Success(ctx, http.StatusOK, "Followers fetched", users)
+}
+
+func (f *followHandler) Following(ctx *gin.Context) {
+	viewerID, userID, ok := f.parseIDs(ctx)
+	if !ok {
+		return
+	}
+
+	q, ok := parseListQuery(ctx)
+	if !ok {
+		return
+	}
+
+	users, err := f.useCase.Following(ctx.Request.Context(), viewerID, userID, q)
+	if err != nil {
+		writeFollowError(ctx, err)
+		return
+	}
+
+	response.Success(ctx, http.StatusOK, "Following fetched", users)
+}
+
+func (f *followHandler) Requests(ctx *gin.Context) {
+	userID, err := uuid.Parse(ctx.GetString("user_id"))
+	if err != nil {
+		response.Error(ctx, http.StatusUnauthorized, "Invalid user ID", "")
+		return
+	}
+
+	q, ok := parseListQuery(ctx)
+	if !ok {
+		return
+	}
+
+	users, err := f.useCase.Requests(ctx.Request.Context(), userID, q)
+	if err != nil {
+		writeFollowError(ctx, err)
+		return
+	}
+
+	response.Success(ctx, http.StatusOK, "Follow requests fetched", users)
+}
+
+func (f *followHandler) ApproveRequest(ctx *gin.Context) {
+	userID, requesterID, ok := f.parseIDs(ctx)
+	if !ok {
+		return
+	}
+
+	if err := f.useCase.ApproveRequest(ctx.Request.Context(), userID, requesterID); err != nil {
+		writeFollowError(ctx, err)
+		return
+	}
+
+	response.Success(ctx, http.StatusOK, "Follow request approved", nil)
+}
+
+func (f *followHandler) RejectRequest(ctx *gin.Context) {
+	userID, requesterID, ok := f.parseIDs(ctx)
+	if !ok {
+		return
+	}
+
+	if err := f.useCase.RejectRequest(ctx.Request.Context(), userID, requesterID); err != nil {
+		writeFollowError(ctx, err)
+		return
+	}
+
+	response.Success(ctx, http.StatusOK, "Follow request rejected", nil)
+}
+
+// parseIDs returns the authenticated user and the :user_id path parameter.
+func (f *followHandler) parseIDs(ctx *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	userID, err := uuid.Parse(ctx.GetString("user_id"))
+	if err != nil {
+		response.Error(ctx, http.StatusUnauthorized, "Invalid user ID", "")
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	otherID, err := uuid.Parse(ctx.Param("user_id"))
+	if err != nil {
+		response.Error(ctx, http.StatusBadRequest, "Invalid user ID", err.Error())
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return userID, otherID, true
+}
+
+func parseListQuery(ctx *gin.Context) (domain.ListQuery, bool) {
+	var q domain.ListQuery
+
+	if v := ctx.Query("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil {
+			response.Error(ctx, http.StatusBadRequest, "Invalid limit", err.Error())
+			return q, false
+		}
+		q.Limit = limit
+	}
+
+	if v := ctx.Query("before"); v != "" {
+		before, err := time.Parse(time.RFC3339Nano, v)
+		if err != nil {
+			response.Error(ctx, http.StatusBadRequest, "Invalid before cursor", err.Error())
+			return q, false
+		}
+		q.Before = &before
+	}
+
+	return q, true
+}
+
+func writeFollowError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrUserNotFound):
+		response.Error(ctx, http.StatusNotFound, "User not found", err.Error())
+	case errors.Is(err, domain.ErrRequestNotFound):
+		response.Error(ctx, http.StatusNotFound, "Follow request not found", err.Error())
+	case errors.Is(err, domain.ErrCannotFollowSelf):
+		response.Error(ctx, http.StatusBadRequest, "Cannot follow yourself", err.Error())
+	case errors.Is(err, domain.ErrPrivateAccount):
+		response.Error(ctx, http.StatusForbidden, "This account is private", err.Error())
+	default:
+		response.Error(ctx, http.StatusInternalServerError, "Internal server Error", err.Error())
+	}
+}
@@ -0,0 +1,10 @@
+package domain
+
+import "errors"
+
+var (
+	ErrUserNotFound     = errors.New("user not found")
+	ErrCannotFollowSelf = errors.New("you cannot follow yourself")
+	ErrRequestNotFound  = errors.New("follow request not found")
+	ErrPrivateAccount   = errors.New("this account is private")
+)
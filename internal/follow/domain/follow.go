@@ -0,0 +1,61 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Status is where the viewer stands with another user.
+type Status string
+
+const (
+	StatusNone      Status = "none"
+	StatusPending   Status = "pending"
+	StatusFollowing Status = "following"
+)
+
+// FollowUser is one entry of a followers, following or requests list.
+// FollowsYou and FollowedByYou are relative to whoever asked for the list.
+type FollowUser struct {
+	ID             uuid.UUID `json:"id"`
+	FirstName      string    `json:"first_name"`
+	LastName       string    `json:"last_name"`
+	ProfilePicture *string   `json:"profile_picture"`
+	FollowsYou     bool      `json:"follows_you"`
+	FollowedByYou  bool      `json:"followed_by_you"`
+	Since          time.Time `json:"since"`
+}
+
+// Stats is the follow information shown on a profile.
+type Stats struct {
+	FollowerCount  int    `json:"follower_count"`
+	FollowingCount int    `json:"following_count"`
+	FollowsYou     bool   `json:"follows_you"`
+	FollowStatus   Status `json:"follow_status"`
+}
+
+// ListQuery pages through a list newest first; Before is the Since of the
+// last entry of the previous page.
+type ListQuery struct {
+	Before *time.Time
+	Limit  int
+}
+
+type FollowUseCase interface {
+	// Follow follows the target, or files a request when the account is
+	// private, and returns the resulting status.
+	Follow(ctx context.Context, followerID, targetID uuid.UUID) (Status, error)
+	// Unfollow also withdraws a pending request.
+	Unfollow(ctx context.Context, followerID, targetID uuid.UUID) error
+	RemoveFollower(ctx context.Context, userID, followerID uuid.UUID) error
+
+	Followers(ctx context.Context, viewerID, userID uuid.UUID, q ListQuery) ([]FollowUser, error)
+	Following(ctx context.Context, viewerID, userID uuid.UUID, q ListQuery) ([]FollowUser, error)
+	Stats(ctx context.Context, viewerID, userID uuid.UUID) (*Stats, error)
+
+	Requests(ctx context.Context, userID uuid.UUID, q ListQuery) ([]FollowUser, error)
+	ApproveRequest(ctx context.Context, userID, requesterID uuid.UUID) error
+	RejectRequest(ctx context.Context, userID, requesterID uuid.UUID) error
+}
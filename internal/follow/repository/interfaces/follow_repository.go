@@ -0,0 +1,26 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/Ramsi97/edu-social-backend/internal/follow/domain"
+	"github.com/google/uuid"
+)
+
+type FollowRepository interface {
+	// IsPrivate reports whether an active (not suspended) user has a
+	// private account, or ErrUserNotFound.
+	IsPrivate(ctx context.Context, userID uuid.UUID) (bool, error)
+	// Create stores the follow unless one already exists and returns the
+	// status of whichever row ends up there.
+	Create(ctx context.Context, followerID, followeeID uuid.UUID, pending bool) (domain.Status, error)
+	Delete(ctx context.Context, followerID, followeeID uuid.UUID) error
+	Status(ctx context.Context, followerID, followeeID uuid.UUID) (domain.Status, error)
+	Accept(ctx context.Context, followerID, followeeID uuid.UUID) error
+	DeletePending(ctx context.Context, followerID, followeeID uuid.UUID) error
+
+	Followers(ctx context.Context, viewerID, userID uuid.UUID, q domain.ListQuery) ([]domain.FollowUser, error)
+	Following(ctx context.Context, viewerID, userID uuid.UUID, q domain.ListQuery) ([]domain.FollowUser, error)
+	Pending(ctx context.Context, userID uuid.UUID, q domain.ListQuery) ([]domain.FollowUser, error)
+	Counts(ctx context.Context, userID uuid.UUID) (followers int, following int, err error)
+}
@@ -0,0 +1,214 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/Ramsi97/edu-social-backend/internal/follow/domain"
+	"github.com/Ramsi97/edu-social-backend/internal/follow/repository/interfaces"
+	"github.com/google/uuid"
+)
+
+const (
+	statusPending  = "pending"
+	statusAccepted = "accepted"
+)
+
+type followRepository struct {
+	db *sql.DB
+}
+
+func NewFollowRepository(db *sql.DB) interfaces.FollowRepository {
+	return &followRepository{
+		db: db,
+	}
+}
+
+func (f *followRepository) IsPrivate(ctx context.Context, userID uuid.UUID) (bool, error) {
+	query := `SELECT is_private FROM users WHERE id = $1 AND suspended_at IS NULL`
+
+	var private bool
+	err := f.db.QueryRowContext(ctx, query, userID).Scan(&private)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, domain.ErrUserNotFound
+		}
+		return false, err
+	}
+
+	return private, nil
+}
+
+func (f *followRepository) Create(ctx context.Context, followerID, followeeID uuid.UUID, pending bool) (domain.Status, error) {
+	status := statusAccepted
+	if pending {
+		status = statusPending
+	}
+
+	// Users on either side of a block can't follow each other; to the
+	// follower the blocked account looks like it doesn't exist.
+	query := `
+		INSERT INTO follows (follower_id, followee_id, status, created_at, accepted_at)
+		SELECT $1, $2, $3, NOW(), CASE WHEN $3 = 'accepted' THEN NOW() END
+		WHERE NOT EXISTS (
+			SELECT 1 FROM user_blocks b
+			WHERE (b.blocker_id = $1 AND b.blocked_id = $2)
+				OR (b.blocker_id = $2 AND b.blocked_id = $1)
+		)
+		ON CONFLICT DO NOTHING
+	`
+
+	if _, err := f.db.ExecContext(ctx, query, followerID, followeeID, status); err != nil {
+		return domain.StatusNone, err
+	}
+
+	current, err := f.Status(ctx, followerID, followeeID)
+	if err != nil {
+		return domain.StatusNone, err
+	}
+	if current == domain.StatusNone {
+		return domain.StatusNone, domain.ErrUserNotFound
+	}
+	return current, nil
+}
+
+func (f *followRepository) Delete(ctx context.Context, followerID, followeeID uuid.UUID) error {
+	query := `DELETE FROM follows WHERE follower_id = $1 AND followee_id = $2`
+
+	_, err := f.db.ExecContext(ctx, query, followerID, followeeID)
+	return err
+}
+
+func (f *followRepository) Status(ctx context.Context, followerID, followeeID uuid.UUID) (domain.Status, error) {
+	query := `SELECT status FROM follows WHERE follower_id = $1 AND followee_id = $2`
+
+	var status string
+	err := f.db.QueryRowContext(ctx, query, followerID, followeeID).Scan(&status)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.StatusNone, nil
+		}
+		return domain.StatusNone, err
+	}
+
+	if status == statusPending {
+		return domain.StatusPending, nil
+	}
+	return domain.StatusFollowing, nil
+}
+
+func (f *followRepository) Accept(ctx context.Context, followerID, followeeID uuid.UUID) error {
+	query := `
+		UPDATE follows
+		SET status = 'accepted', accepted_at = NOW()
+		WHERE follower_id = $1 AND followee_id = $2 AND status = 'pending'
+	`
+
+	return f.execPending(ctx, query, followerID, followeeID)
+}
+
+func (f *followRepository) DeletePending(ctx context.Context, followerID, followeeID uuid.UUID) error {
+	query := `
+		DELETE FROM follows
+		WHERE follower_id = $1 AND followee_id = $2 AND status = 'pending'
+	`
+
+	return f.execPending(ctx, query, followerID, followeeID)
+}
+
+func (f *followRepository) execPending(ctx context.Context, query string, followerID, followeeID uuid.UUID) error {
+	res, err := f.db.ExecContext(ctx, query, followerID, followeeID)
+	if err != nil {
+		return err
+	}
+
+	rows, _ := res.RowsAffected()
+	if rows == 0 {
+		return domain.ErrRequestNotFound
+	}
+	return nil
+}
+
+func (f *followRepository) Followers(ctx context.Context, viewerID, userID uuid.UUID, q domain.ListQuery) ([]domain.FollowUser, error) {
+	return f.list(ctx, "follower_id", "followee_id", statusAccepted, viewerID, userID, q)
+}
+
+func (f *followRepository) Following(ctx context.Context, viewerID, userID uuid.UUID, q domain.ListQuery) ([]domain.FollowUser, error) {
+	return f.list(ctx, "followee_id", "follower_id", statusAccepted, viewerID, userID, q)
+}
+
+func (f *followRepository) Pending(ctx context.Context, userID uuid.UUID, q domain.ListQuery) ([]domain.FollowUser, error) {
+	return f.list(ctx, "follower_id", "followee_id", statusPending, userID, userID, q)
+}
+
+// list returns the users on the listed side of userID's follow rows: the
+// followers when listed is follower_id, the followees otherwise.
+func (f *followRepository) list(
+	ctx context.Context,
+	listed, owner, status string,
+	viewerID, userID uuid.UUID,
+	q domain.ListQuery,
+) ([]domain.FollowUser, error) {
+	query := fmt.Sprintf(`
+		SELECT
+			u.id, u.first_name, u.last_name, u.profile_picture,
+			EXISTS(
+				SELECT 1 FROM follows x
+				WHERE x.follower_id = u.id AND x.followee_id = $1 AND x.status = 'accepted'
+			) AS follows_you,
+			EXISTS(
+				SELECT 1 FROM follows y
+				WHERE y.follower_id = $1 AND y.followee_id = u.id AND y.status = 'accepted'
+			) AS followed_by_you,
+			f.created_at
+		FROM follows f
+		JOIN users u ON u.id = f.%s
+		WHERE f.%s = $2 AND f.status = $3 AND u.suspended_at IS NULL
+			AND ($5::timestamptz IS NULL OR f.created_at < $5)
+		ORDER BY f.created_at DESC
+		LIMIT $4
+	`, listed, owner)
+
+	rows, err := f.db.QueryContext(ctx, query, viewerID, userID, status, q.Limit, q.Before)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := []domain.FollowUser{}
+	for rows.Next() {
+		var u domain.FollowUser
+		if err := rows.Scan(
+			&u.ID,
+			&u.FirstName,
+			&u.LastName,
+			&u.ProfilePicture,
+			&u.FollowsYou,
+			&u.FollowedByYou,
+			&u.Since,
+		); err != nil {
+			return nil, err
+		}
+		users = append(users, u)
+	}
+
+	return users, rows.Err()
+}
+
+func (f *followRepository) Counts(ctx context.Context, userID uuid.UUID) (int, int, error) {
+	query := `
+		SELECT
+			COUNT(*) FILTER (WHERE f.followee_id = $1),
+			COUNT(*) FILTER (WHERE f.follower_id = $1)
+		FROM follows f
+		JOIN users u ON u.id = CASE WHEN f.followee_id = $1 THEN f.follower_id ELSE f.followee_id END
+		WHERE (f.followee_id = $1 OR f.follower_id = $1)
+			AND f.status = 'accepted' AND u.suspended_at IS NULL
+	`
+
+	var followers, following int
+	err := f.db.QueryRowContext(ctx, query, userID).Scan(&followers, &following)
+	return followers, following, err
+}
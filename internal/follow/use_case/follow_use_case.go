@@ -0,0 +1,135 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/Ramsi97/edu-social-backend/internal/follow/domain"
+	"github.com/Ramsi97/edu-social-backend/internal/follow/repository/interfaces"
+	"github.com/google/uuid"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+type followUseCase struct {
+	repo interfaces.FollowRepository
+}
+
+func NewFollowUseCase(repo interfaces.FollowRepository) domain.FollowUseCase {
+	return &followUseCase{
+		repo: repo,
+	}
+}
+
+func (u *followUseCase) Follow(ctx context.Context, followerID, targetID uuid.UUID) (domain.Status, error) {
+	if followerID == targetID {
+		return domain.StatusNone, domain.ErrCannotFollowSelf
+	}
+
+	private, err := u.repo.IsPrivate(ctx, targetID)
+	if err != nil {
+		return domain.StatusNone, err
+	}
+
+	return u.repo.Create(ctx, followerID, targetID, private)
+}
+
+func (u *followUseCase) Unfollow(ctx context.Context, followerID, targetID uuid.UUID) error {
+	return u.repo.Delete(ctx, followerID, targetID)
+}
+
+func (u *followUseCase) RemoveFollower(ctx context.Context, userID, followerID uuid.UUID) error {
+	return u.repo.Delete(ctx, followerID, userID)
+}
+
+func (u *followUseCase) Followers(ctx context.Context, viewerID, userID uuid.UUID, q domain.ListQuery) ([]domain.FollowUser, error) {
+	if err := u.checkVisible(ctx, viewerID, userID); err != nil {
+		return nil, err
+	}
+
+	return u.repo.Followers(ctx, viewerID, userID, pageQuery(q))
+}
+
+func (u *followUseCase) Following(ctx context.Context, viewerID, userID uuid.UUID, q domain.ListQuery) ([]domain.FollowUser, error) {
+	if err := u.checkVisible(ctx, viewerID, userID); err != nil {
+		return nil, err
+	}
+
+	return u.repo.Following(ctx, viewerID, userID, pageQuery(q))
+}
+
+func (u *followUseCase) Stats(ctx context.Context, viewerID, userID uuid.UUID) (*domain.Stats, error) {
+	followers, following, err := u.repo.Counts(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &domain.Stats{
+		FollowerCount:  followers,
+		FollowingCount: following,
+		FollowStatus:   domain.StatusNone,
+	}
+
+	if viewerID == userID {
+		return stats, nil
+	}
+
+	if stats.FollowStatus, err = u.repo.Status(ctx, viewerID, userID); err != nil {
+		return nil, err
+	}
+
+	back, err := u.repo.Status(ctx, userID, viewerID)
+	if err != nil {
+		return nil, err
+	}
+	stats.FollowsYou = back == domain.StatusFollowing
+
+	return stats, nil
+}
+
+func (u *followUseCase) Requests(ctx context.Context, userID uuid.UUID, q domain.ListQuery) ([]domain.FollowUser, error) {
+	return u.repo.Pending(ctx, userID, pageQuery(q))
+}
+
+func (u *followUseCase) ApproveRequest(ctx context.Context, userID, requesterID uuid.UUID) error {
+	return u.repo.Accept(ctx, requesterID, userID)
+}
+
+func (u *followUseCase) RejectRequest(ctx context.Context, userID, requesterID uuid.UUID) error {
+	return u.repo.DeletePending(ctx, requesterID, userID)
+}
+
+// checkVisible hides the lists of a private account from everyone but the
+// owner and their approved followers.
+func (u *followUseCase) checkVisible(ctx context.Context, viewerID, userID uuid.UUID) error {
+	private, err := u.repo.IsPrivate(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if !private || viewerID == userID {
+		return nil
+	}
+
+	status, err := u.repo.Status(ctx, viewerID, userID)
+	if err != nil {
+		return err
+	}
+	if status != domain.StatusFollowing {
+		return domain.ErrPrivateAccount
+	}
+
+	return nil
+}
+
+func pageQuery(q domain.ListQuery) domain.ListQuery {
+	if q.Limit <= 0 {
+		q.Limit = defaultPageSize
+	}
+	if q.Limit > maxPageSize {
+		q.Limit = maxPageSize
+	}
+	return q
+}
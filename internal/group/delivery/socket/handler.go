@@ -2,12 +2,14 @@ package socket
 
 import (
 	"context"
+	"errors"
 	"log"
 
+	authDomain "github.com/Ramsi97/edu-social-backend/internal/auth/domain"
 	"github.com/Ramsi97/edu-social-backend/internal/group/domain"
+	"github.com/Ramsi97/edu-social-backend/pkg/websocket"
 	"github.com/google/uuid"
 	"github.com/zishang520/socket.io/v2/socket"
-	"github.com/Ramsi97/edu-social-backend/pkg/auth"
 )
 
 
@@ -15,12 +17,14 @@ import (
 type socketHandler struct {
 	chatUsecase domain.GroupChatUseCase
 	io *socket.Server
+	authenticator authDomain.Authenticator
 }
 
-func NewSocketHandler(io *socket.Server, uc domain.GroupChatUseCase) *socketHandler {
+func NewSocketHandler(io *socket.Server, uc domain.GroupChatUseCase, authenticator authDomain.Authenticator) *socketHandler {
 	return &socketHandler{
 		chatUsecase: uc,
 		io: io,
+		authenticator: authenticator,
 	}
 }
 
@@ -115,19 +119,25 @@ func (h *socketHandler) RegisterMiddleWare() {
 			return
 		}
 
-		userIDStr, err := auth.ValidateToken(token)
+		claims, err := h.authenticator.Authenticate(context.Background(), token)
+
+		if errors.Is(err, authDomain.ErrAccountSuspended) {
+			next(socket.NewExtendedError("Account suspended", nil))
+			return
+		}
 
 		if err != nil {
 			next(socket.NewExtendedError("Invalid token", nil))
 			return
 		}
 
-		userUUID, err := uuid.Parse(userIDStr)
+		userUUID, err := uuid.Parse(claims.UserID)
 		if err != nil {
 			next(socket.NewExtendedError("Invalid user ID", nil))
 			return
 		}
 
+		s.Join(socket.Room(websocket.SessionRoom(claims.SessionID)))
 		s.SetData(userUUID)
 
 		next(nil)
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"time"
 
+	mentionDomain "github.com/Ramsi97/edu-social-backend/internal/mention/domain"
 	"github.com/google/uuid"
 )
 
@@ -15,6 +16,8 @@ type Message struct {
 	Content string `json:"content"`
 	MediaURL string `json:"media_url"`
 	CreatedAt time.Time `json:"created_at"`
+	// Mentions are the users @mentioned in Content.
+	Mentions mentionDomain.Mentions `json:"mentions"`
 }
 
 type Group struct {
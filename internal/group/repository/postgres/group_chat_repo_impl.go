@@ -8,6 +8,8 @@ import (
 
 	"github.com/Ramsi97/edu-social-backend/internal/group/domain"
 	"github.com/Ramsi97/edu-social-backend/internal/group/repository/interfaces"
+	notificationDomain "github.com/Ramsi97/edu-social-backend/internal/notification/domain"
+	sharedPostgres "github.com/Ramsi97/edu-social-backend/internal/shared/infrastructure/postgres"
 	"github.com/google/uuid"
 )
 
@@ -126,13 +128,14 @@ func (r *groupChatRepo) SaveMessage(ctx context.Context, msg *domain.Message) er
 
 	_, err := r.db.ExecContext(ctx, `
 		INSERT INTO group_posts (id, group_id, author_id, content, media_url, created_at)
-		VALUES ($1, $2, $3, $4, $5, NOW())
+		VALUES ($1, $2, $3, $4, $5, $6)
 	`,
 		msg.ID,
 		msg.GroupID,
 		msg.AuthorID,
 		msg.Content,
 		msg.MediaURL,
+		msg.CreatedAt,
 	)
 	return err
 }
@@ -150,7 +153,8 @@ func (r *groupChatRepo) IsMember(ctx context.Context, userID, groupID uuid.UUID)
 
 func (r *groupChatRepo) GetMessages(ctx context.Context, groupID uuid.UUID, limit int) ([]*domain.Message, error) {
 	rows, err := r.db.QueryContext(ctx, `
-		SELECT id, group_id, author_id, content, media_url, created_at
+		SELECT id, group_id, author_id, content, media_url, created_at,
+			`+sharedPostgres.MentionsColumn(string(notificationDomain.SourceGroupMessage), "group_posts.id")+`
 		FROM group_posts
 		WHERE group_id=$1
 		ORDER BY created_at DESC
@@ -164,7 +168,7 @@ func (r *groupChatRepo) GetMessages(ctx context.Context, groupID uuid.UUID, limi
 	posts := []*domain.Message{}
 	for rows.Next() {
 		var p domain.Message
-		if err := rows.Scan(&p.ID, &p.GroupID, &p.AuthorID, &p.Content, &p.MediaURL, &p.CreatedAt); err != nil {
+		if err := rows.Scan(&p.ID, &p.GroupID, &p.AuthorID, &p.Content, &p.MediaURL, &p.CreatedAt, &p.Mentions); err != nil {
 			return nil, err
 		}
 		posts = append(posts, &p)
@@ -3,19 +3,25 @@ package usecase
 import (
 	"context"
 	"errors"
+	"log"
+	"time"
 
 	"github.com/Ramsi97/edu-social-backend/internal/group/domain"
 	"github.com/Ramsi97/edu-social-backend/internal/group/repository/interfaces"
+	mentionDomain "github.com/Ramsi97/edu-social-backend/internal/mention/domain"
+	notificationDomain "github.com/Ramsi97/edu-social-backend/internal/notification/domain"
 	"github.com/google/uuid"
 )
 
 type groupChatUseCase struct {
 	repo      interfaces.GroupChatRepo
+	mentions  mentionDomain.MentionUseCase
 }
 
-func NewGroupChatUseCase(repo interfaces.GroupChatRepo) domain.GroupChatUseCase {
+func NewGroupChatUseCase(repo interfaces.GroupChatRepo, mentions mentionDomain.MentionUseCase) domain.GroupChatUseCase {
 	return &groupChatUseCase{
 		repo:      repo,
+		mentions:  mentions,
 	}
 }
 
@@ -73,10 +79,31 @@ func (g *groupChatUseCase) SendMessage(ctx context.Context, msg *domain.Message)
 		return errors.New("not allowed to post to this group")
 	}
 
+	msg.ID = uuid.New()
+	msg.CreatedAt = time.Now()
 	if err := g.repo.SaveMessage(ctx, msg); err != nil {
         return err
     }
 
+	// Only members of the group are notified. A failure is only logged.
+	mentions, err := g.mentions.Record(ctx, mentionDomain.Source{
+		Source: notificationDomain.Source{
+			Type:     notificationDomain.SourceGroupMessage,
+			ID:       msg.ID,
+			ParentID: msg.GroupID,
+		},
+		AuthorID: msg.AuthorID,
+		Text:     msg.Content,
+		CanRead: func(ctx context.Context, userID uuid.UUID) (bool, error) {
+			return g.repo.IsMember(ctx, userID, msg.GroupID)
+		},
+	})
+	if err != nil {
+		log.Printf("failed to record mentions in group message %s: %v", msg.ID, err)
+		mentions = mentionDomain.Mentions{}
+	}
+	msg.Mentions = mentions
+
 	return nil
 }
 
@@ -1,6 +1,7 @@
 package http
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/Ramsi97/edu-social-backend/internal/like/domain"
@@ -46,6 +47,11 @@ func (l *likeHandler) Togglelike(ctx *gin.Context) {
 	}
 	liked, err := l.useCase.ToggleUseCase(ctx, userID, postID)
 
+	if errors.Is(err, domain.ErrPostNotFound) {
+		response.Error(ctx, http.StatusNotFound, "Post not found", err.Error())
+		return
+	}
+
 	if err != nil {
 		response.Error(ctx, http.StatusInternalServerError, "Internal server Error", err.Error())
 		return
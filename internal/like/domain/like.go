@@ -2,10 +2,13 @@ package domain
 
 import (
 	"context"
+	"errors"
 
 	"github.com/google/uuid"
 )
 
+var ErrPostNotFound = errors.New("post not found")
+
 type Like struct {
 	UserID uuid.UUID `json:"user_id"`
 	PostID uuid.UUID `json:"post_id"`
@@ -4,7 +4,9 @@ import (
 	"context"
 	"database/sql"
 
+	"github.com/Ramsi97/edu-social-backend/internal/like/domain"
 	"github.com/Ramsi97/edu-social-backend/internal/like/repository/interfaces"
+	sharedPostgres "github.com/Ramsi97/edu-social-backend/internal/shared/infrastructure/postgres"
 	"github.com/google/uuid"
 )
 
@@ -18,15 +20,41 @@ func NewLikeRepository(db *sql.DB) interfaces.LikeRepository {
 	}
 }
 
+// Create likes the post unless it doesn't exist or the user can't see it,
+// which includes the user and the post's author having blocked one another.
 func (l *likeRepository) Create(ctx context.Context, userID, postID uuid.UUID) error {
 
 	query := `
 		INSERT INTO posts_likes (user_id, post_id)
-		VALUES ($1, $2)
+		SELECT $1, p.id
+		FROM posts p
+		JOIN users u ON u.id = p.author_id
+		WHERE p.id = $2
+			AND p.deleted_at IS NULL
+			AND` + sharedPostgres.VisibleTo("u", "$1") + `
 		ON CONFLICT DO NOTHING
 	`
-	_, err := l.db.ExecContext(ctx, query, userID, postID)
-	return err
+	res, err := l.db.ExecContext(ctx, query, userID, postID)
+	if err != nil {
+		return err
+	}
+
+	rows, _ := res.RowsAffected()
+	if rows > 0 {
+		return nil
+	}
+
+	// Nothing inserted: either a concurrent request already liked the post
+	// or the post is out of reach.
+	exists, err := l.Exists(ctx, userID, postID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return domain.ErrPostNotFound
+	}
+
+	return nil
 }
 
 func (l *likeRepository) Delete(ctx context.Context, userID, postID uuid.UUID) error {
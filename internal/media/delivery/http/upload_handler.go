@@ -0,0 +1,170 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/Ramsi97/edu-social-backend/internal/media/domain"
+	"github.com/Ramsi97/edu-social-backend/pkg/media"
+	"github.com/Ramsi97/edu-social-backend/pkg/response"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type uploadHandler struct {
+	usecase domain.MediaUseCase
+}
+
+// NewUploadHandler mounts the upload sessions. A client creates a session,
+// PUTs the file's bytes in order (?offset= being where each chunk starts),
+// and finalizes it into a media ID to attach to a post, comment or message.
+func NewUploadHandler(rg *gin.RouterGroup, uc domain.MediaUseCase) {
+	handler := &uploadHandler{
+		usecase: uc,
+	}
+
+	rg.POST("", handler.CreateSession)
+	rg.GET("/:session_id", handler.GetSession)
+	rg.PUT("/:session_id/chunks", handler.WriteChunk)
+	rg.POST("/:session_id/finalize", handler.Finalize)
+	rg.DELETE("/:session_id", handler.Abort)
+}
+
+func (h *uploadHandler) CreateSession(ctx *gin.Context) {
+	userID, ok := currentUser(ctx)
+	if !ok {
+		return
+	}
+
+	var req domain.CreateSessionRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.Error(ctx, http.StatusBadRequest, "Invalid request", err.Error())
+		return
+	}
+
+	session, err := h.usecase.CreateSession(ctx.Request.Context(), userID, req)
+	if err != nil {
+		writeUploadError(ctx, err)
+		return
+	}
+
+	response.Success(ctx, http.StatusCreated, "Upload session created", session)
+}
+
+func (h *uploadHandler) GetSession(ctx *gin.Context) {
+	userID, sessionID, ok := parseSession(ctx)
+	if !ok {
+		return
+	}
+
+	session, err := h.usecase.GetSession(ctx.Request.Context(), userID, sessionID)
+	if err != nil {
+		writeUploadError(ctx, err)
+		return
+	}
+
+	response.Success(ctx, http.StatusOK, "Upload session fetched", session)
+}
+
+func (h *uploadHandler) WriteChunk(ctx *gin.Context) {
+	userID, sessionID, ok := parseSession(ctx)
+	if !ok {
+		return
+	}
+
+	offset, err := strconv.ParseInt(ctx.Query("offset"), 10, 64)
+	if err != nil || offset < 0 {
+		response.Error(ctx, http.StatusBadRequest, "Invalid offset", "offset must be a non-negative byte count")
+		return
+	}
+
+	session, err := h.usecase.WriteChunk(ctx.Request.Context(), userID, sessionID, offset, ctx.Request.Body)
+	if errors.Is(err, domain.ErrOffsetMismatch) {
+		// Tell the client where to resume from.
+		ctx.JSON(http.StatusConflict, response.Response{
+			Success: false,
+			Message: "Resume from the received offset",
+			Data:    session,
+			Error:   err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		writeUploadError(ctx, err)
+		return
+	}
+
+	response.Success(ctx, http.StatusOK, "Chunk received", session)
+}
+
+func (h *uploadHandler) Finalize(ctx *gin.Context) {
+	userID, sessionID, ok := parseSession(ctx)
+	if !ok {
+		return
+	}
+
+	m, err := h.usecase.Finalize(ctx.Request.Context(), userID, sessionID)
+	if err != nil {
+		writeUploadError(ctx, err)
+		return
+	}
+
+	response.Success(ctx, http.StatusCreated, "Upload complete", m)
+}
+
+func (h *uploadHandler) Abort(ctx *gin.Context) {
+	userID, sessionID, ok := parseSession(ctx)
+	if !ok {
+		return
+	}
+
+	if err := h.usecase.Abort(ctx.Request.Context(), userID, sessionID); err != nil {
+		writeUploadError(ctx, err)
+		return
+	}
+
+	response.Success(ctx, http.StatusOK, "Upload cancelled", nil)
+}
+
+func currentUser(ctx *gin.Context) (uuid.UUID, bool) {
+	userID, err := uuid.Parse(ctx.GetString("user_id"))
+	if err != nil {
+		response.Error(ctx, http.StatusUnauthorized, "Invalid session", err.Error())
+		return uuid.Nil, false
+	}
+	return userID, true
+}
+
+func parseSession(ctx *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	userID, ok := currentUser(ctx)
+	if !ok {
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	sessionID, err := uuid.Parse(ctx.Param("session_id"))
+	if err != nil {
+		response.Error(ctx, http.StatusBadRequest, "Invalid session ID", err.Error())
+		return uuid.Nil, uuid.Nil, false
+	}
+	return userID, sessionID, true
+}
+
+func writeUploadError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrSessionNotFound):
+		response.Error(ctx, http.StatusNotFound, "Upload session not found", err.Error())
+	case errors.Is(err, domain.ErrTooManySessions):
+		response.Error(ctx, http.StatusTooManyRequests, "Too many uploads", err.Error())
+	case errors.Is(err, domain.ErrIncomplete):
+		response.Error(ctx, http.StatusConflict, "Upload not complete", err.Error())
+	case errors.Is(err, domain.ErrInvalidUpload), errors.Is(err, media.ErrUnsupportedType),
+		errors.Is(err, media.ErrCorruptImage), errors.Is(err, media.ErrImageTooLarge),
+		errors.Is(err, media.ErrPolyglot):
+		response.Error(ctx, http.StatusBadRequest, "Invalid upload", err.Error())
+	case errors.Is(err, domain.ErrChunkTooLarge), errors.Is(err, media.ErrFileTooLarge):
+		response.Error(ctx, http.StatusRequestEntityTooLarge, "Upload too large", err.Error())
+	default:
+		response.Error(ctx, http.StatusInternalServerError, "Server Error", err.Error())
+	}
+}
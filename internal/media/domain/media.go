@@ -0,0 +1,153 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	sharedInterfaces "github.com/Ramsi97/edu-social-backend/internal/shared/interfaces"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrSessionNotFound = errors.New("upload session not found")
+	ErrInvalidUpload   = errors.New("invalid upload")
+	// ErrOffsetMismatch means the chunk doesn't start where the session
+	// left off; the client should resume from the session's received count.
+	ErrOffsetMismatch = errors.New("chunk does not start at the received offset")
+	ErrChunkTooLarge  = errors.New("chunk too large")
+	ErrIncomplete     = errors.New("upload is not complete")
+	// ErrTooManySessions means the user already has as many uploads in
+	// progress, or as many bytes staged, as UploadConfig allows.
+	ErrTooManySessions = errors.New("too many uploads in progress")
+	// ErrMediaNotFound also covers media that belongs to someone else, was
+	// uploaded for another purpose or is already in use.
+	ErrMediaNotFound = errors.New("media not found")
+)
+
+// Kind is what sort of file a media record holds.
+type Kind string
+
+const (
+	KindImage    Kind = "image"
+	KindVideo    Kind = "video"
+	KindDocument Kind = "document"
+)
+
+// Media is a finalized upload. It can be attached once, to whatever its
+// purpose allows; until then it belongs to nobody but its uploader.
+type Media struct {
+	ID              uuid.UUID                     `json:"id"`
+	OwnerID         uuid.UUID                     `json:"-"`
+	Purpose         sharedInterfaces.MediaPurpose `json:"-"`
+	Kind            Kind                          `json:"type"`
+	URL             string                        `json:"url"`
+	ContentType     string                        `json:"content_type"`
+	SizeBytes       int64                         `json:"size_bytes"`
+	Width           *int                          `json:"width,omitempty"`
+	Height          *int                          `json:"height,omitempty"`
+	DurationSeconds *float64                      `json:"duration_seconds,omitempty"`
+	FileName        string                        `json:"file_name"`
+	Variants        *Variants                     `json:"variants,omitempty"`
+	CreatedAt       time.Time                     `json:"created_at"`
+}
+
+// Scan reads media selected as a JSON object.
+func (m *Media) Scan(src any) error {
+	switch src := src.(type) {
+	case []byte:
+		return json.Unmarshal(src, m)
+	case string:
+		return json.Unmarshal([]byte(src), m)
+	default:
+		return fmt.Errorf("cannot scan %T into Media", src)
+	}
+}
+
+// UploadSession assembles one file from chunks sent in order. Received is
+// where the next chunk has to start, so a client that lost its connection
+// asks for the session and carries on from there.
+type UploadSession struct {
+	ID          uuid.UUID                     `json:"id"`
+	OwnerID     uuid.UUID                     `json:"-"`
+	Purpose     sharedInterfaces.MediaPurpose `json:"purpose"`
+	FileName    string                        `json:"file_name"`
+	ContentType string                        `json:"content_type"`
+	Size        int64                         `json:"size"`
+	Received    int64                         `json:"received"`
+	// ChunkSize is the largest chunk the server takes.
+	ChunkSize int64     `json:"chunk_size"`
+	ExpiresAt time.Time `json:"expires_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type CreateSessionRequest struct {
+	FileName    string                        `json:"file_name"`
+	ContentType string                        `json:"content_type"`
+	Size        int64                         `json:"size"`
+	Purpose     sharedInterfaces.MediaPurpose `json:"purpose"`
+}
+
+type MediaUseCase interface {
+	CreateSession(ctx context.Context, ownerID uuid.UUID, req CreateSessionRequest) (*UploadSession, error)
+	GetSession(ctx context.Context, ownerID, sessionID uuid.UUID) (*UploadSession, error)
+	// WriteChunk appends body at offset. Whatever arrived before a dropped
+	// connection is kept.
+	WriteChunk(ctx context.Context, ownerID, sessionID uuid.UUID, offset int64, body io.Reader) (*UploadSession, error)
+	Finalize(ctx context.Context, ownerID, sessionID uuid.UUID) (*Media, error)
+	Abort(ctx context.Context, ownerID, sessionID uuid.UUID) error
+
+	// Claim attaches the owner's unused media, returning it in the order of
+	// ids. Either all of it is claimed or none.
+	Claim(ctx context.Context, ownerID uuid.UUID, purpose sharedInterfaces.MediaPurpose, ids []uuid.UUID) ([]Media, error)
+	// Release undoes a Claim whose attachment could not be saved. It only
+	// takes what that Claim returned.
+	Release(ctx context.Context, ownerID uuid.UUID, claimed []Media)
+	// Discard deletes media along with its file, once what it was attached
+	// to is gone.
+	Discard(ctx context.Context, id uuid.UUID) error
+	// Sign swaps the URLs of media and its variants for links that stop
+	// working after SignedURLTTL, which private media is only served by.
+	Sign(ctx context.Context, m *Media) error
+
+	// CollectGarbage removes expired sessions and media that was never
+	// attached.
+	CollectGarbage(ctx context.Context) error
+}
+
+// UploadConfig sets how upload sessions behave. Chunks are staged on the
+// server's disk, so every request of a session has to reach the same
+// instance.
+type UploadConfig struct {
+	StagingDir string
+	ChunkSize  int64
+	// SessionTTL is how long an unfinished session is kept.
+	SessionTTL time.Duration
+	// UnclaimedTTL is how long finalized media waits to be attached.
+	UnclaimedTTL time.Duration
+	// MaxOpenSessions and MaxStagedBytes cap one user's unfinished
+	// sessions, counted by the sizes they declared, so nobody can fill the
+	// staging disk.
+	MaxOpenSessions int
+	MaxStagedBytes  int64
+	// SignedURLTTL is how long a signed link to private media works.
+	SignedURLTTL time.Duration
+}
+
+func DefaultUploadConfig() UploadConfig {
+	return UploadConfig{
+		StagingDir:   filepath.Join(os.TempDir(), "edu-social-uploads"),
+		ChunkSize:    8 << 20,
+		SessionTTL:   24 * time.Hour,
+		UnclaimedTTL: 24 * time.Hour,
+
+		MaxOpenSessions: 5,
+		MaxStagedBytes:  2 << 30,
+		SignedURLTTL:    time.Hour,
+	}
+}
@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Variants are the smaller renditions of an uploaded image, made in the
+// background after it is stored. Until then payloads carry none and
+// clients fall back to the original.
+type Variants struct {
+	ThumbnailURL string `json:"thumbnail_url"`
+	MediumURL    string `json:"medium_url"`
+	// Blurhash and DominantColor (#rrggbb) are placeholders to show while
+	// an image loads.
+	Blurhash      string `json:"blurhash"`
+	DominantColor string `json:"dominant_color"`
+}
+
+// Scan reads variants selected as a JSON object.
+func (v *Variants) Scan(src any) error {
+	switch src := src.(type) {
+	case []byte:
+		return json.Unmarshal(src, v)
+	case string:
+		return json.Unmarshal([]byte(src), v)
+	default:
+		return fmt.Errorf("cannot scan %T into Variants", src)
+	}
+}
+
+// VariantConfig sets the variant sizes (longest side, in pixels) and how
+// much background work may pile up.
+type VariantConfig struct {
+	ThumbnailSize int
+	MediumSize    int
+	Workers       int
+	// QueueSize is how many images may wait; past that new ones get no
+	// variants rather than holding up uploads.
+	QueueSize int
+}
+
+func DefaultVariantConfig() VariantConfig {
+	return VariantConfig{
+		ThumbnailSize: 320,
+		MediumSize:    1080,
+		Workers:       2,
+		QueueSize:     32,
+	}
+}
@@ -0,0 +1,38 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"github.com/Ramsi97/edu-social-backend/internal/media/domain"
+	sharedInterfaces "github.com/Ramsi97/edu-social-backend/internal/shared/interfaces"
+	"github.com/google/uuid"
+)
+
+type UploadRepository interface {
+	CreateSession(ctx context.Context, s *domain.UploadSession) error
+	// GetSession returns the owner's session unless it has expired, or
+	// ErrSessionNotFound.
+	GetSession(ctx context.Context, ownerID, sessionID uuid.UUID) (*domain.UploadSession, error)
+	// OpenSessions counts the owner's sessions that haven't expired and the
+	// bytes they declared.
+	OpenSessions(ctx context.Context, ownerID uuid.UUID, now time.Time) (int, int64, error)
+	SetReceived(ctx context.Context, sessionID uuid.UUID, received int64) error
+	DeleteSession(ctx context.Context, sessionID uuid.UUID) error
+	// DeleteExpiredSessions removes sessions that expired before now and
+	// returns their IDs.
+	DeleteExpiredSessions(ctx context.Context, now time.Time) ([]uuid.UUID, error)
+
+	CreateMedia(ctx context.Context, m *domain.Media) error
+	// Claim marks the owner's unattached media of the given purpose as
+	// attached and returns it. Unless every ID can be claimed, nothing is
+	// and ErrMediaNotFound is returned.
+	Claim(ctx context.Context, ownerID uuid.UUID, purpose sharedInterfaces.MediaPurpose, ids []uuid.UUID) ([]domain.Media, error)
+	// Release detaches the owner's media again.
+	Release(ctx context.Context, ownerID uuid.UUID, ids []uuid.UUID) error
+	// DeleteMedia removes the record and returns its URL.
+	DeleteMedia(ctx context.Context, id uuid.UUID) (string, error)
+	// DeleteUnclaimed removes media never attached since before and
+	// returns the URLs.
+	DeleteUnclaimed(ctx context.Context, before time.Time) ([]string, error)
+}
@@ -0,0 +1,14 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/Ramsi97/edu-social-backend/internal/media/domain"
+)
+
+type VariantRepository interface {
+	Save(ctx context.Context, url string, v domain.Variants) error
+	// Delete forgets the variants of url and returns them, or nil when it
+	// had none.
+	Delete(ctx context.Context, url string) (*domain.Variants, error)
+}
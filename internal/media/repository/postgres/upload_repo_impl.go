@@ -0,0 +1,231 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Ramsi97/edu-social-backend/internal/media/domain"
+	"github.com/Ramsi97/edu-social-backend/internal/media/repository/interfaces"
+	sharedInterfaces "github.com/Ramsi97/edu-social-backend/internal/shared/interfaces"
+	"github.com/google/uuid"
+)
+
+const mediaColumns = `
+	id, owner_id, purpose, type, url, content_type, size_bytes,
+	width, height, duration_seconds, file_name, created_at
+`
+
+type uploadRepository struct {
+	db *sql.DB
+}
+
+func NewUploadRepository(db *sql.DB) interfaces.UploadRepository {
+	return &uploadRepository{
+		db: db,
+	}
+}
+
+func (r *uploadRepository) CreateSession(ctx context.Context, s *domain.UploadSession) error {
+	query := `
+		INSERT INTO upload_sessions
+			(id, owner_id, purpose, file_name, content_type, size_bytes, received_bytes, created_at, expires_at)
+		VALUES ($1, $2, $3, $4, $5, $6, 0, $7, $8)
+	`
+	_, err := r.db.ExecContext(ctx, query,
+		s.ID, s.OwnerID, s.Purpose, s.FileName, s.ContentType, s.Size, s.CreatedAt, s.ExpiresAt,
+	)
+	return err
+}
+
+func (r *uploadRepository) GetSession(ctx context.Context, ownerID, sessionID uuid.UUID) (*domain.UploadSession, error) {
+	query := `
+		SELECT id, owner_id, purpose, file_name, content_type, size_bytes, received_bytes, created_at, expires_at
+		FROM upload_sessions
+		WHERE id = $1 AND owner_id = $2 AND expires_at > NOW()
+	`
+
+	var s domain.UploadSession
+	err := r.db.QueryRowContext(ctx, query, sessionID, ownerID).Scan(
+		&s.ID,
+		&s.OwnerID,
+		&s.Purpose,
+		&s.FileName,
+		&s.ContentType,
+		&s.Size,
+		&s.Received,
+		&s.CreatedAt,
+		&s.ExpiresAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrSessionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (r *uploadRepository) OpenSessions(ctx context.Context, ownerID uuid.UUID, now time.Time) (int, int64, error) {
+	var count int
+	var size int64
+	err := r.db.QueryRowContext(ctx, `
+		SELECT COUNT(*), COALESCE(SUM(size_bytes), 0)
+		FROM upload_sessions
+		WHERE owner_id = $1 AND expires_at > $2
+	`, ownerID, now).Scan(&count, &size)
+	return count, size, err
+}
+
+func (r *uploadRepository) SetReceived(ctx context.Context, sessionID uuid.UUID, received int64) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE upload_sessions SET received_bytes = $2 WHERE id = $1`,
+		sessionID, received,
+	)
+	return err
+}
+
+func (r *uploadRepository) DeleteSession(ctx context.Context, sessionID uuid.UUID) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM upload_sessions WHERE id = $1`, sessionID)
+	return err
+}
+
+func (r *uploadRepository) DeleteExpiredSessions(ctx context.Context, now time.Time) ([]uuid.UUID, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`DELETE FROM upload_sessions WHERE expires_at <= $1 RETURNING id`, now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := []uuid.UUID{}
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+func (r *uploadRepository) CreateMedia(ctx context.Context, m *domain.Media) error {
+	query := `
+		INSERT INTO media (` + mediaColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+	`
+	_, err := r.db.ExecContext(ctx, query,
+		m.ID, m.OwnerID, m.Purpose, m.Kind, m.URL, m.ContentType, m.SizeBytes,
+		m.Width, m.Height, m.DurationSeconds, m.FileName, m.CreatedAt,
+	)
+	return err
+}
+
+func (r *uploadRepository) Claim(
+	ctx context.Context,
+	ownerID uuid.UUID,
+	purpose sharedInterfaces.MediaPurpose,
+	ids []uuid.UUID,
+) ([]domain.Media, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE media
+		SET attached_at = NOW()
+		WHERE id = ANY($1::uuid[])
+			AND owner_id = $2
+			AND purpose = $3
+			AND attached_at IS NULL
+		RETURNING ` + mediaColumns
+
+	rows, err := tx.QueryContext(ctx, query, uuidStrings(ids), ownerID, purpose)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	claimed := []domain.Media{}
+	found := make(map[uuid.UUID]bool, len(ids))
+	for rows.Next() {
+		var m domain.Media
+		if err := rows.Scan(
+			&m.ID,
+			&m.OwnerID,
+			&m.Purpose,
+			&m.Kind,
+			&m.URL,
+			&m.ContentType,
+			&m.SizeBytes,
+			&m.Width,
+			&m.Height,
+			&m.DurationSeconds,
+			&m.FileName,
+			&m.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		found[m.ID] = true
+		claimed = append(claimed, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// Anything missing leaves the whole claim undone.
+	for _, id := range ids {
+		if !found[id] {
+			return nil, fmt.Errorf("%w: %s", domain.ErrMediaNotFound, id)
+		}
+	}
+
+	return claimed, tx.Commit()
+}
+
+func (r *uploadRepository) Release(ctx context.Context, ownerID uuid.UUID, ids []uuid.UUID) error {
+	_, err := r.db.ExecContext(ctx,
+		`UPDATE media SET attached_at = NULL WHERE id = ANY($1::uuid[]) AND owner_id = $2`,
+		uuidStrings(ids), ownerID)
+	return err
+}
+
+func (r *uploadRepository) DeleteMedia(ctx context.Context, id uuid.UUID) (string, error) {
+	var url string
+	err := r.db.QueryRowContext(ctx, `DELETE FROM media WHERE id = $1 RETURNING url`, id).Scan(&url)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", domain.ErrMediaNotFound
+	}
+	return url, err
+}
+
+func (r *uploadRepository) DeleteUnclaimed(ctx context.Context, before time.Time) ([]string, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`DELETE FROM media WHERE attached_at IS NULL AND created_at < $1 RETURNING url`, before)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	urls := []string{}
+	for rows.Next() {
+		var url string
+		if err := rows.Scan(&url); err != nil {
+			return nil, err
+		}
+		urls = append(urls, url)
+	}
+	return urls, rows.Err()
+}
+
+func uuidStrings(ids []uuid.UUID) []string {
+	out := make([]string, len(ids))
+	for i, id := range ids {
+		out[i] = id.String()
+	}
+	return out
+}
@@ -0,0 +1,52 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/Ramsi97/edu-social-backend/internal/media/domain"
+	"github.com/Ramsi97/edu-social-backend/internal/media/repository/interfaces"
+)
+
+type variantRepository struct {
+	db *sql.DB
+}
+
+func NewVariantRepository(db *sql.DB) interfaces.VariantRepository {
+	return &variantRepository{
+		db: db,
+	}
+}
+
+func (r *variantRepository) Save(ctx context.Context, url string, v domain.Variants) error {
+	query := `
+		INSERT INTO media_variants (url, thumbnail_url, medium_url, blurhash, dominant_color)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (url) DO UPDATE SET
+			thumbnail_url = EXCLUDED.thumbnail_url,
+			medium_url = EXCLUDED.medium_url,
+			blurhash = EXCLUDED.blurhash,
+			dominant_color = EXCLUDED.dominant_color
+	`
+	_, err := r.db.ExecContext(ctx, query, url, v.ThumbnailURL, v.MediumURL, v.Blurhash, v.DominantColor)
+	return err
+}
+
+func (r *variantRepository) Delete(ctx context.Context, url string) (*domain.Variants, error) {
+	query := `
+		DELETE FROM media_variants
+		WHERE url = $1
+		RETURNING thumbnail_url, medium_url, blurhash, dominant_color
+	`
+
+	var v domain.Variants
+	err := r.db.QueryRowContext(ctx, query, url).Scan(&v.ThumbnailURL, &v.MediumURL, &v.Blurhash, &v.DominantColor)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
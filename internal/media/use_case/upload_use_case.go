@@ -0,0 +1,399 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Ramsi97/edu-social-backend/internal/media/domain"
+	"github.com/Ramsi97/edu-social-backend/internal/media/repository/interfaces"
+	sharedInterfaces "github.com/Ramsi97/edu-social-backend/internal/shared/interfaces"
+	"github.com/Ramsi97/edu-social-backend/pkg/media"
+	"github.com/google/uuid"
+)
+
+// sessionPurposes are the uploads that can go through a session; avatars
+// are small enough to send in one request.
+var sessionPurposes = map[sharedInterfaces.MediaPurpose]bool{
+	sharedInterfaces.PurposePost:    true,
+	sharedInterfaces.PurposeComment: true,
+	sharedInterfaces.PurposeMessage: true,
+}
+
+type mediaUseCase struct {
+	repo     interfaces.UploadRepository
+	storage  sharedInterfaces.MediaStorage
+	policies map[sharedInterfaces.MediaPurpose]media.Policy
+	cfg      domain.UploadConfig
+
+	// locks keeps two requests from writing the same session at once.
+	locks sync.Map
+}
+
+func NewMediaUseCase(
+	repo interfaces.UploadRepository,
+	storage sharedInterfaces.MediaStorage,
+	policies map[sharedInterfaces.MediaPurpose]media.Policy,
+	cfg domain.UploadConfig,
+) domain.MediaUseCase {
+	return &mediaUseCase{
+		repo:     repo,
+		storage:  storage,
+		policies: policies,
+		cfg:      cfg,
+	}
+}
+
+func (u *mediaUseCase) CreateSession(ctx context.Context, ownerID uuid.UUID, req domain.CreateSessionRequest) (*domain.UploadSession, error) {
+	if !sessionPurposes[req.Purpose] {
+		return nil, fmt.Errorf("%w: unknown purpose %q", domain.ErrInvalidUpload, req.Purpose)
+	}
+	if req.Size <= 0 {
+		return nil, fmt.Errorf("%w: size must be positive", domain.ErrInvalidUpload)
+	}
+
+	// The declared type only gets an early answer; finalizing checks the
+	// file's actual content.
+	contentType, _, err := mime.ParseMediaType(req.ContentType)
+	if err != nil {
+		return nil, fmt.Errorf("%w: bad content type", domain.ErrInvalidUpload)
+	}
+	limit, ok := u.policies[req.Purpose].MaxBytes[contentType]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", media.ErrUnsupportedType, contentType)
+	}
+	if req.Size > limit {
+		return nil, fmt.Errorf("%w: exceeds %d MB", media.ErrFileTooLarge, limit>>20)
+	}
+
+	fileName := filepath.Base(strings.TrimSpace(req.FileName))
+	if fileName == "." || fileName == string(filepath.Separator) {
+		fileName = ""
+	}
+
+	now := time.Now()
+	open, staged, err := u.repo.OpenSessions(ctx, ownerID, now)
+	if err != nil {
+		return nil, err
+	}
+	if open >= u.cfg.MaxOpenSessions || staged+req.Size > u.cfg.MaxStagedBytes {
+		return nil, fmt.Errorf("%w: at most %d sessions and %d MB at once",
+			domain.ErrTooManySessions, u.cfg.MaxOpenSessions, u.cfg.MaxStagedBytes>>20)
+	}
+
+	session := &domain.UploadSession{
+		ID:          uuid.New(),
+		OwnerID:     ownerID,
+		Purpose:     req.Purpose,
+		FileName:    fileName,
+		ContentType: contentType,
+		Size:        req.Size,
+		ChunkSize:   u.cfg.ChunkSize,
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(u.cfg.SessionTTL),
+	}
+
+	if err := os.MkdirAll(u.cfg.StagingDir, 0o700); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(u.stagingPath(session.ID), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	f.Close()
+
+	if err := u.repo.CreateSession(ctx, session); err != nil {
+		os.Remove(u.stagingPath(session.ID))
+		return nil, err
+	}
+	return session, nil
+}
+
+func (u *mediaUseCase) GetSession(ctx context.Context, ownerID, sessionID uuid.UUID) (*domain.UploadSession, error) {
+	session, err := u.repo.GetSession(ctx, ownerID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	session.ChunkSize = u.cfg.ChunkSize
+	return session, nil
+}
+
+func (u *mediaUseCase) WriteChunk(ctx context.Context, ownerID, sessionID uuid.UUID, offset int64, body io.Reader) (*domain.UploadSession, error) {
+	unlock := u.lock(sessionID)
+	defer unlock()
+
+	session, err := u.GetSession(ctx, ownerID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if offset != session.Received {
+		return session, domain.ErrOffsetMismatch
+	}
+
+	f, err := os.OpenFile(u.stagingPath(sessionID), os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	// Drop anything past the recorded offset, such as the tail of a write
+	// whose bookkeeping never happened.
+	if err := f.Truncate(session.Received); err != nil {
+		return nil, err
+	}
+	if _, err := f.Seek(session.Received, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	allowed := min(u.cfg.ChunkSize, session.Size-session.Received)
+	n, copyErr := io.Copy(f, io.LimitReader(body, allowed+1))
+	if n > allowed {
+		return session, fmt.Errorf("%w: at most %d bytes fit here", domain.ErrChunkTooLarge, allowed)
+	}
+
+	// Keep whatever arrived, even from a connection that dropped midway,
+	// so the client only resends the rest.
+	if n > 0 {
+		// The request context is already cancelled when the client is gone.
+		if err := u.repo.SetReceived(context.WithoutCancel(ctx), sessionID, session.Received+n); err != nil {
+			return nil, err
+		}
+		session.Received += n
+	}
+	if copyErr != nil {
+		return session, copyErr
+	}
+	return session, nil
+}
+
+func (u *mediaUseCase) Finalize(ctx context.Context, ownerID, sessionID uuid.UUID) (*domain.Media, error) {
+	unlock := u.lock(sessionID)
+	defer unlock()
+
+	session, err := u.GetSession(ctx, ownerID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if session.Received != session.Size {
+		return nil, fmt.Errorf("%w: %d of %d bytes received", domain.ErrIncomplete, session.Received, session.Size)
+	}
+
+	f, err := os.Open(u.stagingPath(sessionID))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	m, err := u.describe(f, session)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	m.URL, err = u.storage.UploadReader(ctx, f, session.FileName, session.Size, session.Purpose)
+	if err != nil {
+		return nil, err
+	}
+
+	// Sanitizing can change the file, so record the size that was stored.
+	if stored, err := u.storage.Metadata(ctx, m.URL); err != nil {
+		log.Printf("could not read stored metadata of %s: %v", m.URL, err)
+	} else if stored.Size > 0 {
+		m.SizeBytes = stored.Size
+	}
+
+	if err := u.repo.CreateMedia(ctx, m); err != nil {
+		u.deleteMedia(ctx, m.URL)
+		return nil, err
+	}
+
+	u.removeSession(ctx, sessionID)
+	return m, nil
+}
+
+func (u *mediaUseCase) Abort(ctx context.Context, ownerID, sessionID uuid.UUID) error {
+	unlock := u.lock(sessionID)
+	defer unlock()
+
+	if _, err := u.repo.GetSession(ctx, ownerID, sessionID); err != nil {
+		return err
+	}
+	u.removeSession(ctx, sessionID)
+	return nil
+}
+
+func (u *mediaUseCase) Claim(
+	ctx context.Context,
+	ownerID uuid.UUID,
+	purpose sharedInterfaces.MediaPurpose,
+	ids []uuid.UUID,
+) ([]domain.Media, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	// Listing one ID twice must not attach it twice.
+	seen := make(map[uuid.UUID]bool, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			return nil, fmt.Errorf("%w: %s is listed twice", domain.ErrMediaNotFound, id)
+		}
+		seen[id] = true
+	}
+
+	claimed, err := u.repo.Claim(ctx, ownerID, purpose, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[uuid.UUID]domain.Media, len(claimed))
+	for _, m := range claimed {
+		byID[m.ID] = m
+	}
+
+	ordered := make([]domain.Media, len(ids))
+	for i, id := range ids {
+		ordered[i] = byID[id]
+	}
+	return ordered, nil
+}
+
+func (u *mediaUseCase) Release(ctx context.Context, ownerID uuid.UUID, claimed []domain.Media) {
+	if len(claimed) == 0 {
+		return
+	}
+
+	ids := make([]uuid.UUID, len(claimed))
+	for i, m := range claimed {
+		ids[i] = m.ID
+	}
+	if err := u.repo.Release(ctx, ownerID, ids); err != nil {
+		log.Printf("failed to release media %v: %v", ids, err)
+	}
+}
+
+func (u *mediaUseCase) Discard(ctx context.Context, id uuid.UUID) error {
+	url, err := u.repo.DeleteMedia(ctx, id)
+	if err != nil {
+		return err
+	}
+	u.deleteMedia(ctx, url)
+	return nil
+}
+
+func (u *mediaUseCase) Sign(ctx context.Context, m *domain.Media) error {
+	urls := []*string{&m.URL}
+	if m.Variants != nil {
+		urls = append(urls, &m.Variants.ThumbnailURL, &m.Variants.MediumURL)
+	}
+
+	for _, url := range urls {
+		if *url == "" {
+			continue
+		}
+		signed, err := u.storage.SignedURL(ctx, *url, u.cfg.SignedURLTTL)
+		if err != nil {
+			return err
+		}
+		*url = signed
+	}
+	return nil
+}
+
+func (u *mediaUseCase) CollectGarbage(ctx context.Context) error {
+	expired, err := u.repo.DeleteExpiredSessions(ctx, time.Now())
+	if err != nil {
+		return err
+	}
+	for _, id := range expired {
+		u.locks.Delete(id)
+		if err := os.Remove(u.stagingPath(id)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			log.Printf("failed to remove staged upload %s: %v", id, err)
+		}
+	}
+
+	unclaimed, err := u.repo.DeleteUnclaimed(ctx, time.Now().Add(-u.cfg.UnclaimedTTL))
+	if err != nil {
+		return err
+	}
+	for _, url := range unclaimed {
+		u.deleteMedia(ctx, url)
+	}
+	return nil
+}
+
+// describe fills in what the media record says about the file, going by
+// its content rather than the declared type.
+func (u *mediaUseCase) describe(f io.ReadSeeker, session *domain.UploadSession) (*domain.Media, error) {
+	contentType, err := media.Sniff(f)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	m := &domain.Media{
+		ID:          uuid.New(),
+		OwnerID:     session.OwnerID,
+		Purpose:     session.Purpose,
+		Kind:        domain.KindDocument,
+		ContentType: contentType,
+		SizeBytes:   session.Size,
+		FileName:    session.FileName,
+		CreatedAt:   time.Now(),
+	}
+
+	// Metadata is best effort, as it is for post attachments.
+	var info media.Info
+	switch {
+	case strings.HasPrefix(contentType, "image/"):
+		m.Kind = domain.KindImage
+		info, err = media.ProbeImage(f)
+	case strings.HasPrefix(contentType, "video/"):
+		m.Kind = domain.KindVideo
+		info, err = media.ProbeVideo(f)
+	}
+	if err != nil {
+		log.Printf("could not read metadata of %s: %v", session.FileName, err)
+	}
+	m.Width, m.Height, m.DurationSeconds = info.Width, info.Height, info.Duration
+
+	return m, nil
+}
+
+func (u *mediaUseCase) removeSession(ctx context.Context, sessionID uuid.UUID) {
+	u.locks.Delete(sessionID)
+	if err := u.repo.DeleteSession(ctx, sessionID); err != nil {
+		log.Printf("failed to delete upload session %s: %v", sessionID, err)
+	}
+	if err := os.Remove(u.stagingPath(sessionID)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		log.Printf("failed to remove staged upload %s: %v", sessionID, err)
+	}
+}
+
+func (u *mediaUseCase) deleteMedia(ctx context.Context, url string) {
+	if err := u.storage.Delete(ctx, url); err != nil {
+		log.Printf("failed to delete media %s: %v", url, err)
+	}
+}
+
+func (u *mediaUseCase) stagingPath(sessionID uuid.UUID) string {
+	return filepath.Join(u.cfg.StagingDir, sessionID.String()+".part")
+}
+
+func (u *mediaUseCase) lock(sessionID uuid.UUID) func() {
+	mu, _ := u.locks.LoadOrStore(sessionID, &sync.Mutex{})
+	mu.(*sync.Mutex).Lock()
+	return mu.(*sync.Mutex).Unlock
+}
@@ -0,0 +1,152 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"log"
+	"time"
+
+	"github.com/Ramsi97/edu-social-backend/internal/media/domain"
+	"github.com/Ramsi97/edu-social-backend/internal/media/repository/interfaces"
+	sharedInterfaces "github.com/Ramsi97/edu-social-backend/internal/shared/interfaces"
+	"github.com/Ramsi97/edu-social-backend/pkg/media"
+)
+
+// variantTimeout bounds the work on a single image, uploads included.
+const variantTimeout = 2 * time.Minute
+
+type variantJob struct {
+	url     string
+	purpose sharedInterfaces.MediaPurpose
+	data    []byte
+}
+
+type variantProcessor struct {
+	repo    interfaces.VariantRepository
+	backend sharedInterfaces.MediaBackend
+	cfg     domain.VariantConfig
+	jobs    chan variantJob
+}
+
+// NewVariantProcessor starts cfg.Workers goroutines that make the variants
+// of queued images and store them next to the original.
+func NewVariantProcessor(
+	repo interfaces.VariantRepository,
+	backend sharedInterfaces.MediaBackend,
+	cfg domain.VariantConfig,
+) sharedInterfaces.ImageProcessor {
+	p := &variantProcessor{
+		repo:    repo,
+		backend: backend,
+		cfg:     cfg,
+		jobs:    make(chan variantJob, cfg.QueueSize),
+	}
+	for range cfg.Workers {
+		go p.work()
+	}
+	return p
+}
+
+func (p *variantProcessor) Process(url string, purpose sharedInterfaces.MediaPurpose, data []byte) {
+	select {
+	case p.jobs <- variantJob{url: url, purpose: purpose, data: data}:
+	default:
+		log.Printf("variant queue full, serving %s without variants", url)
+	}
+}
+
+func (p *variantProcessor) Discard(ctx context.Context, url string) error {
+	v, err := p.repo.Delete(ctx, url)
+	if err != nil || v == nil {
+		return err
+	}
+	return p.deleteFiles(ctx, url, v.ThumbnailURL, v.MediumURL)
+}
+
+func (p *variantProcessor) work() {
+	for job := range p.jobs {
+		ctx, cancel := context.WithTimeout(context.Background(), variantTimeout)
+		if err := p.makeVariants(ctx, job); err != nil {
+			log.Printf("failed to make variants of %s: %v", job.url, err)
+		}
+		cancel()
+	}
+}
+
+func (p *variantProcessor) makeVariants(ctx context.Context, job variantJob) error {
+	img, format, err := image.Decode(bytes.NewReader(job.data))
+	if err != nil {
+		return err
+	}
+
+	medium := media.Resize(img, p.cfg.MediumSize)
+	thumbnail := media.Resize(medium, p.cfg.ThumbnailSize)
+	v := domain.Variants{
+		// Reuse the original where it is already small enough.
+		ThumbnailURL:  job.url,
+		MediumURL:     job.url,
+		Blurhash:      media.Blurhash(media.Resize(thumbnail, 32), 4, 3),
+		DominantColor: media.DominantColor(thumbnail),
+	}
+
+	if medium != img {
+		if v.MediumURL, err = p.store(ctx, job.purpose, medium, format); err != nil {
+			return err
+		}
+	}
+	if thumbnail != img {
+		if v.ThumbnailURL, err = p.store(ctx, job.purpose, thumbnail, format); err != nil {
+			p.deleteFiles(ctx, job.url, v.MediumURL)
+			return err
+		}
+	}
+
+	if err := p.repo.Save(ctx, job.url, v); err != nil {
+		p.deleteFiles(ctx, job.url, v.ThumbnailURL, v.MediumURL)
+		return err
+	}
+	return nil
+}
+
+// store encodes a variant as JPEG, or as PNG when the original may have
+// transparency.
+func (p *variantProcessor) store(ctx context.Context, purpose sharedInterfaces.MediaPurpose, img image.Image, format string) (string, error) {
+	var buf bytes.Buffer
+	contentType := "image/jpeg"
+	var err error
+	if format == "png" || format == "gif" {
+		contentType = "image/png"
+		err = png.Encode(&buf, img)
+	} else {
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: 80})
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return p.backend.Put(ctx, sharedInterfaces.MediaFile{
+		Purpose:     purpose,
+		ContentType: contentType,
+		Size:        int64(buf.Len()),
+		Body:        &buf,
+	})
+}
+
+// deleteFiles removes variant files, skipping any that are the original
+// itself.
+func (p *variantProcessor) deleteFiles(ctx context.Context, original string, urls ...string) error {
+	var errs []error
+	for _, url := range urls {
+		if url == original {
+			continue
+		}
+		if err := p.backend.Delete(ctx, url); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	notificationDomain "github.com/Ramsi97/edu-social-backend/internal/notification/domain"
+	"github.com/google/uuid"
+)
+
+// Mention links an @handle in a post, comment or message to its user.
+// Offset and Length locate the "@handle" in the text, counted in Unicode
+// code points. Username is the user's current handle, which may differ
+// from the text if they have changed it since.
+type Mention struct {
+	UserID   uuid.UUID `json:"user_id"`
+	Username string    `json:"username"`
+	Offset   int       `json:"offset"`
+	Length   int       `json:"length"`
+}
+
+// Mentions are the mentions of one source in the order they appear.
+type Mentions []Mention
+
+// Scan reads mentions selected as a JSON array.
+func (m *Mentions) Scan(src any) error {
+	switch src := src.(type) {
+	case []byte:
+		return json.Unmarshal(src, m)
+	case string:
+		return json.Unmarshal([]byte(src), m)
+	default:
+		return fmt.Errorf("cannot scan %T into Mentions", src)
+	}
+}
+
+// Source is a piece of text whose mentions are recorded.
+type Source struct {
+	notificationDomain.Source
+	AuthorID uuid.UUID
+	Text     string
+	// CanRead reports whether a mentioned user can read the source. Only
+	// those who can are notified; nil means everyone can.
+	CanRead func(ctx context.Context, userID uuid.UUID) (bool, error)
+}
+
+type MentionUseCase interface {
+	// Record links the @handles in the text to the users who let the
+	// author mention them, replacing what the source had before. Users
+	// newly mentioned are notified; those no longer mentioned have their
+	// notification withdrawn.
+	Record(ctx context.Context, src Source) (Mentions, error)
+	// Remove drops the mentions of a deleted source and their
+	// notifications.
+	Remove(ctx context.Context, sourceType notificationDomain.SourceType, sourceID uuid.UUID) error
+}
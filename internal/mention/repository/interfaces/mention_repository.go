@@ -0,0 +1,19 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/Ramsi97/edu-social-backend/internal/mention/domain"
+	notificationDomain "github.com/Ramsi97/edu-social-backend/internal/notification/domain"
+	"github.com/google/uuid"
+)
+
+type MentionRepository interface {
+	// FindUsers maps the handles to users, leaving out suspended users and
+	// anyone on either side of a block with the author.
+	FindUsers(ctx context.Context, authorID uuid.UUID, handles []string) (map[string]uuid.UUID, error)
+	// Replace stores the source's mentions in place of the old ones and
+	// returns who the old ones were for.
+	Replace(ctx context.Context, sourceType notificationDomain.SourceType, sourceID uuid.UUID, mentions domain.Mentions) ([]uuid.UUID, error)
+	Delete(ctx context.Context, sourceType notificationDomain.SourceType, sourceID uuid.UUID) error
+}
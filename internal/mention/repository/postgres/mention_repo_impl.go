@@ -0,0 +1,107 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/Ramsi97/edu-social-backend/internal/mention/domain"
+	"github.com/Ramsi97/edu-social-backend/internal/mention/repository/interfaces"
+	notificationDomain "github.com/Ramsi97/edu-social-backend/internal/notification/domain"
+	"github.com/google/uuid"
+)
+
+type mentionRepository struct {
+	db *sql.DB
+}
+
+func NewMentionRepository(db *sql.DB) interfaces.MentionRepository {
+	return &mentionRepository{
+		db: db,
+	}
+}
+
+func (r *mentionRepository) FindUsers(ctx context.Context, authorID uuid.UUID, handles []string) (map[string]uuid.UUID, error) {
+	query := `
+		SELECT u.username, u.id
+		FROM users u
+		WHERE u.username = ANY($1::text[])
+			AND u.suspended_at IS NULL
+			AND NOT EXISTS (
+				SELECT 1 FROM user_blocks b
+				WHERE (b.blocker_id = $2 AND b.blocked_id = u.id)
+					OR (b.blocker_id = u.id AND b.blocked_id = $2)
+			)
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, handles, authorID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := map[string]uuid.UUID{}
+	for rows.Next() {
+		var handle string
+		var id uuid.UUID
+		if err := rows.Scan(&handle, &id); err != nil {
+			return nil, err
+		}
+		users[handle] = id
+	}
+
+	return users, rows.Err()
+}
+
+func (r *mentionRepository) Replace(
+	ctx context.Context,
+	sourceType notificationDomain.SourceType,
+	sourceID uuid.UUID,
+	mentions domain.Mentions,
+) ([]uuid.UUID, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, `
+		DELETE FROM mentions
+		WHERE source_type = $1 AND source_id = $2
+		RETURNING user_id
+	`, sourceType, sourceID)
+	if err != nil {
+		return nil, err
+	}
+
+	previous := []uuid.UUID{}
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		previous = append(previous, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, m := range mentions {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO mentions (id, source_type, source_id, user_id, start_index, length)
+			VALUES ($1, $2, $3, $4, $5, $6)
+		`, uuid.New(), sourceType, sourceID, m.UserID, m.Offset, m.Length)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return previous, tx.Commit()
+}
+
+func (r *mentionRepository) Delete(ctx context.Context, sourceType notificationDomain.SourceType, sourceID uuid.UUID) error {
+	_, err := r.db.ExecContext(ctx,
+		`DELETE FROM mentions WHERE source_type = $1 AND source_id = $2`, sourceType, sourceID)
+	return err
+}
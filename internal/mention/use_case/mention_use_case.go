@@ -0,0 +1,139 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/Ramsi97/edu-social-backend/internal/mention/domain"
+	"github.com/Ramsi97/edu-social-backend/internal/mention/repository/interfaces"
+	notificationDomain "github.com/Ramsi97/edu-social-backend/internal/notification/domain"
+	userDomain "github.com/Ramsi97/edu-social-backend/internal/user/domain"
+	"github.com/Ramsi97/edu-social-backend/pkg/mention"
+	"github.com/google/uuid"
+)
+
+// maxMentionedUsers caps how many people one text can mention, so a post
+// can't be used to notify a crowd.
+const maxMentionedUsers = 20
+
+type mentionUseCase struct {
+	repo          interfaces.MentionRepository
+	privacy       userDomain.PrivacyPolicy
+	notifications notificationDomain.NotificationUseCase
+}
+
+func NewMentionUseCase(
+	repo interfaces.MentionRepository,
+	privacy userDomain.PrivacyPolicy,
+	notifications notificationDomain.NotificationUseCase,
+) domain.MentionUseCase {
+	return &mentionUseCase{
+		repo:          repo,
+		privacy:       privacy,
+		notifications: notifications,
+	}
+}
+
+func (u *mentionUseCase) Record(ctx context.Context, src domain.Source) (domain.Mentions, error) {
+	matches := mention.Find(src.Text)
+
+	var handles []string
+	seen := map[string]bool{}
+	for _, m := range matches {
+		if !seen[m.Handle] && len(handles) < maxMentionedUsers {
+			seen[m.Handle] = true
+			handles = append(handles, m.Handle)
+		}
+	}
+
+	users := map[string]uuid.UUID{}
+	if len(handles) > 0 {
+		found, err := u.repo.FindUsers(ctx, src.AuthorID, handles)
+		if err != nil {
+			return nil, err
+		}
+
+		// Keep only those who let the author mention them.
+		for handle, userID := range found {
+			allowed, err := u.privacy.Allowed(ctx, src.AuthorID, userID, userDomain.ActionMention)
+			if err != nil {
+				return nil, err
+			}
+			if allowed {
+				users[handle] = userID
+			}
+		}
+	}
+
+	mentions := domain.Mentions{}
+	for _, m := range matches {
+		if userID, ok := users[m.Handle]; ok {
+			mentions = append(mentions, domain.Mention{
+				UserID:   userID,
+				Username: m.Handle,
+				Offset:   m.Offset,
+				Length:   m.Length,
+			})
+		}
+	}
+
+	previous, err := u.repo.Replace(ctx, src.Type, src.ID, mentions)
+	if err != nil {
+		return nil, err
+	}
+
+	wasMentioned := map[uuid.UUID]bool{}
+	for _, userID := range previous {
+		wasMentioned[userID] = true
+	}
+
+	var notifications []notificationDomain.Notification
+	isMentioned := map[uuid.UUID]bool{}
+	for _, userID := range users {
+		isMentioned[userID] = true
+		if wasMentioned[userID] || userID == src.AuthorID {
+			continue
+		}
+
+		if src.CanRead != nil {
+			ok, err := src.CanRead(ctx, userID)
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				continue
+			}
+		}
+
+		notifications = append(notifications, notificationDomain.Notification{
+			UserID: userID,
+			Type:   notificationDomain.TypeMention,
+			Actor:  notificationDomain.Actor{ID: src.AuthorID},
+			Source: src.Source,
+		})
+	}
+
+	var removed []uuid.UUID
+	for userID := range wasMentioned {
+		if !isMentioned[userID] {
+			removed = append(removed, userID)
+		}
+	}
+	if len(removed) > 0 {
+		if err := u.notifications.Withdraw(ctx, src.Type, src.ID, removed); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := u.notifications.Notify(ctx, notifications); err != nil {
+		return nil, err
+	}
+
+	return mentions, nil
+}
+
+func (u *mentionUseCase) Remove(ctx context.Context, sourceType notificationDomain.SourceType, sourceID uuid.UUID) error {
+	if err := u.repo.Delete(ctx, sourceType, sourceID); err != nil {
+		return err
+	}
+	return u.notifications.Withdraw(ctx, sourceType, sourceID, nil)
+}
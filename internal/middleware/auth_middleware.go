@@ -1,15 +1,16 @@
 package middleware
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"strings"
 
-	"github.com/Ramsi97/edu-social-backend/pkg/auth"
+	authDomain "github.com/Ramsi97/edu-social-backend/internal/auth/domain"
 	"github.com/gin-gonic/gin"
 )
 
-func AuthMiddleWare() gin.HandlerFunc {
+func AuthMiddleWare(authenticator authDomain.Authenticator) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		authHeader := ctx.GetHeader("Authorization")
 
@@ -28,7 +29,14 @@ func AuthMiddleWare() gin.HandlerFunc {
 
 		token := parts[1]
 
-		userID, err := auth.ValidateToken(token)
+		claims, err := authenticator.Authenticate(ctx.Request.Context(), token)
+
+		if errors.Is(err, authDomain.ErrAccountSuspended) {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
 
 		if err != nil {
 			ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
@@ -37,9 +45,30 @@ func AuthMiddleWare() gin.HandlerFunc {
 			return
 		}
 
-		fmt.Println("UserID: " + userID)
+		fmt.Println("UserID: " + claims.UserID)
 
-		ctx.Set("user_id", userID)
+		ctx.Set("user_id", claims.UserID)
+		ctx.Set("session_id", claims.SessionID)
+		ctx.Set("role", claims.Role)
 		ctx.Next()
 	}
 }
+
+// RequireRoles lets the request through only when the authenticated user
+// has one of the given roles. It must run after AuthMiddleWare.
+func RequireRoles(roles ...authDomain.Role) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		role := authDomain.Role(ctx.GetString("role"))
+
+		for _, allowed := range roles {
+			if role == allowed {
+				ctx.Next()
+				return
+			}
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+			"error": "insufficient permissions",
+		})
+	}
+}
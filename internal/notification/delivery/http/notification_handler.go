@@ -0,0 +1,106 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Ramsi97/edu-social-backend/internal/notification/domain"
+	"github.com/Ramsi97/edu-social-backend/pkg/response"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type notificationHandler struct {
+	usecase domain.NotificationUseCase
+}
+
+func NewNotificationHandler(rg *gin.RouterGroup, uc domain.NotificationUseCase) {
+	handler := &notificationHandler{
+		usecase: uc,
+	}
+
+	rg.GET("", handler.List)
+	rg.GET("/unread_count", handler.UnreadCount)
+	rg.POST("/read", handler.MarkRead)
+}
+
+func (h *notificationHandler) List(ctx *gin.Context) {
+	userID, ok := currentUser(ctx)
+	if !ok {
+		return
+	}
+
+	q := domain.ListQuery{UnreadOnly: ctx.Query("unread") == "true"}
+	if v := ctx.Query("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil {
+			response.Error(ctx, http.StatusBadRequest, "Invalid limit", err.Error())
+			return
+		}
+		q.Limit = limit
+	}
+	if v := ctx.Query("before"); v != "" {
+		before, err := time.Parse(time.RFC3339Nano, v)
+		if err != nil {
+			response.Error(ctx, http.StatusBadRequest, "Invalid before cursor", err.Error())
+			return
+		}
+		q.Before = &before
+	}
+
+	notifications, err := h.usecase.List(ctx.Request.Context(), userID, q)
+	if err != nil {
+		response.Error(ctx, http.StatusInternalServerError, "Server Error", err.Error())
+		return
+	}
+
+	response.Success(ctx, http.StatusOK, "Notifications fetched", notifications)
+}
+
+func (h *notificationHandler) UnreadCount(ctx *gin.Context) {
+	userID, ok := currentUser(ctx)
+	if !ok {
+		return
+	}
+
+	count, err := h.usecase.UnreadCount(ctx.Request.Context(), userID)
+	if err != nil {
+		response.Error(ctx, http.StatusInternalServerError, "Server Error", err.Error())
+		return
+	}
+
+	response.Success(ctx, http.StatusOK, "", gin.H{"unread": count})
+}
+
+func (h *notificationHandler) MarkRead(ctx *gin.Context) {
+	userID, ok := currentUser(ctx)
+	if !ok {
+		return
+	}
+
+	// An empty body marks everything read.
+	var req domain.MarkReadRequest
+	if ctx.Request.ContentLength != 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			response.Error(ctx, http.StatusBadRequest, "Invalid request", err.Error())
+			return
+		}
+	}
+
+	if err := h.usecase.MarkRead(ctx.Request.Context(), userID, req.IDs); err != nil {
+		response.Error(ctx, http.StatusInternalServerError, "Server Error", err.Error())
+		return
+	}
+
+	response.Success(ctx, http.StatusOK, "Notifications marked read", nil)
+}
+
+func currentUser(ctx *gin.Context) (uuid.UUID, bool) {
+	userID, err := uuid.Parse(ctx.GetString("user_id"))
+	if err != nil {
+		response.Error(ctx, http.StatusUnauthorized, "Invalid session", err.Error())
+		return uuid.Nil, false
+	}
+	return userID, true
+}
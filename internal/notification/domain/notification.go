@@ -0,0 +1,88 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	mediaDomain "github.com/Ramsi97/edu-social-backend/internal/media/domain"
+	"github.com/google/uuid"
+)
+
+// Type is what happened to the user.
+type Type string
+
+const (
+	// TypeMention means the actor @mentioned the user.
+	TypeMention Type = "mention"
+)
+
+// SourceType is the kind of content a notification is about.
+type SourceType string
+
+const (
+	SourcePost         SourceType = "post"
+	SourceComment      SourceType = "comment"
+	SourceMessage      SourceType = "message"
+	SourceGroupMessage SourceType = "group_message"
+)
+
+// Source is the content a notification is about. ParentID is what a client
+// opens to show it: the post itself or the post of a comment, the room of
+// a message, the group of a group message.
+type Source struct {
+	Type     SourceType `json:"type"`
+	ID       uuid.UUID  `json:"id"`
+	ParentID uuid.UUID  `json:"parent_id"`
+}
+
+// Actor is the user whose action caused a notification.
+type Actor struct {
+	ID                     uuid.UUID             `json:"id"`
+	FirstName              string                `json:"first_name"`
+	LastName               string                `json:"last_name"`
+	Username               *string               `json:"username"`
+	ProfilePicture         string                `json:"profile_picture"`
+	ProfilePictureVariants *mediaDomain.Variants `json:"profile_picture_variants,omitempty"`
+}
+
+type Notification struct {
+	ID        uuid.UUID  `json:"id"`
+	UserID    uuid.UUID  `json:"-"`
+	Type      Type       `json:"type"`
+	Actor     Actor      `json:"actor"`
+	Source    Source     `json:"source"`
+	CreatedAt time.Time  `json:"created_at"`
+	ReadAt    *time.Time `json:"read_at"`
+}
+
+// ListQuery pages through a user's notifications newest first; Before is
+// the created_at of the last notification of the previous page.
+type ListQuery struct {
+	Before     *time.Time
+	Limit      int
+	UnreadOnly bool
+}
+
+type MarkReadRequest struct {
+	// IDs are the notifications to mark read; none marks all of them.
+	IDs []uuid.UUID `json:"ids"`
+}
+
+type NotificationUseCase interface {
+	// Notify stores the notifications and hands them to the listeners.
+	// Only Type, UserID, Actor.ID and Source have to be set.
+	Notify(ctx context.Context, notifications []Notification) error
+	// Withdraw removes the notifications about a source, only those sent to
+	// userIDs when any are given.
+	Withdraw(ctx context.Context, sourceType SourceType, sourceID uuid.UUID, userIDs []uuid.UUID) error
+
+	// List leaves out notifications from suspended users and from anyone on
+	// either side of a block with the user.
+	List(ctx context.Context, userID uuid.UUID, q ListQuery) ([]Notification, error)
+	UnreadCount(ctx context.Context, userID uuid.UUID) (int, error)
+	MarkRead(ctx context.Context, userID uuid.UUID, ids []uuid.UUID) error
+
+	// OnNotify registers a callback fired with every notification stored,
+	// so it can be pushed to the user's open sockets.
+	OnNotify(listener func(Notification))
+}
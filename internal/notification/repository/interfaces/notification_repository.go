@@ -0,0 +1,22 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/Ramsi97/edu-social-backend/internal/notification/domain"
+	"github.com/google/uuid"
+)
+
+type NotificationRepository interface {
+	// Create fills in the ID and created_at of each notification.
+	Create(ctx context.Context, notifications []domain.Notification) error
+	// GetByIDs returns the notifications with their actors.
+	GetByIDs(ctx context.Context, ids []uuid.UUID) ([]domain.Notification, error)
+	Delete(ctx context.Context, sourceType domain.SourceType, sourceID uuid.UUID, userIDs []uuid.UUID) error
+
+	List(ctx context.Context, userID uuid.UUID, q domain.ListQuery) ([]domain.Notification, error)
+	UnreadCount(ctx context.Context, userID uuid.UUID) (int, error)
+	// MarkRead marks the user's notifications in ids read, or all of them
+	// when ids is empty.
+	MarkRead(ctx context.Context, userID uuid.UUID, ids []uuid.UUID) error
+}
@@ -0,0 +1,169 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/Ramsi97/edu-social-backend/internal/notification/domain"
+	"github.com/Ramsi97/edu-social-backend/internal/notification/repository/interfaces"
+	sharedPostgres "github.com/Ramsi97/edu-social-backend/internal/shared/infrastructure/postgres"
+	"github.com/google/uuid"
+)
+
+type notificationRepository struct {
+	db *sql.DB
+}
+
+func NewNotificationRepository(db *sql.DB) interfaces.NotificationRepository {
+	return &notificationRepository{
+		db: db,
+	}
+}
+
+func (r *notificationRepository) Create(ctx context.Context, notifications []domain.Notification) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	for i := range notifications {
+		n := &notifications[i]
+		n.ID = uuid.New()
+		n.CreatedAt = now
+
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO notifications (id, user_id, actor_id, type, source_type, source_id, parent_id, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		`, n.ID, n.UserID, n.Actor.ID, n.Type, n.Source.Type, n.Source.ID, n.Source.ParentID, n.CreatedAt)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// notificationColumns selects notifications n with their actor a.
+var notificationColumns = `
+	SELECT
+		n.id, n.user_id, n.type, n.source_type, n.source_id, n.parent_id,
+		n.created_at, n.read_at,
+		a.id, a.first_name, a.last_name, a.username,
+		COALESCE(a.profile_picture, ''),
+		` + sharedPostgres.VariantsColumn("a.profile_picture") + `
+	FROM notifications n
+	JOIN users a ON a.id = n.actor_id
+`
+
+func (r *notificationRepository) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]domain.Notification, error) {
+	query := notificationColumns + `
+		WHERE n.id = ANY($1::uuid[])
+		ORDER BY n.created_at DESC, n.id
+	`
+	return r.query(ctx, query, uuidStrings(ids))
+}
+
+func (r *notificationRepository) Delete(ctx context.Context, sourceType domain.SourceType, sourceID uuid.UUID, userIDs []uuid.UUID) error {
+	_, err := r.db.ExecContext(ctx, `
+		DELETE FROM notifications
+		WHERE source_type = $1
+			AND source_id = $2
+			AND (cardinality($3::uuid[]) = 0 OR user_id = ANY($3::uuid[]))
+	`, sourceType, sourceID, uuidStrings(userIDs))
+	return err
+}
+
+func (r *notificationRepository) List(ctx context.Context, userID uuid.UUID, q domain.ListQuery) ([]domain.Notification, error) {
+	query := notificationColumns + `
+		WHERE n.user_id = $1
+			AND a.suspended_at IS NULL
+			AND NOT EXISTS (
+				SELECT 1 FROM user_blocks b
+				WHERE (b.blocker_id = $1 AND b.blocked_id = a.id)
+					OR (b.blocker_id = a.id AND b.blocked_id = $1)
+			)
+			AND ($2::timestamptz IS NULL OR n.created_at < $2)
+			AND (NOT $3 OR n.read_at IS NULL)
+		ORDER BY n.created_at DESC, n.id
+		LIMIT $4
+	`
+	return r.query(ctx, query, userID, q.Before, q.UnreadOnly, q.Limit)
+}
+
+func (r *notificationRepository) UnreadCount(ctx context.Context, userID uuid.UUID) (int, error) {
+	query := `
+		SELECT COUNT(*)
+		FROM notifications n
+		JOIN users a ON a.id = n.actor_id
+		WHERE n.user_id = $1
+			AND n.read_at IS NULL
+			AND a.suspended_at IS NULL
+			AND NOT EXISTS (
+				SELECT 1 FROM user_blocks b
+				WHERE (b.blocker_id = $1 AND b.blocked_id = a.id)
+					OR (b.blocker_id = a.id AND b.blocked_id = $1)
+			)
+	`
+
+	var count int
+	err := r.db.QueryRowContext(ctx, query, userID).Scan(&count)
+	return count, err
+}
+
+func (r *notificationRepository) MarkRead(ctx context.Context, userID uuid.UUID, ids []uuid.UUID) error {
+	_, err := r.db.ExecContext(ctx, `
+		UPDATE notifications
+		SET read_at = NOW()
+		WHERE user_id = $1
+			AND read_at IS NULL
+			AND (cardinality($2::uuid[]) = 0 OR id = ANY($2::uuid[]))
+	`, userID, uuidStrings(ids))
+	return err
+}
+
+func (r *notificationRepository) query(ctx context.Context, query string, args ...any) ([]domain.Notification, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	notifications := []domain.Notification{}
+	for rows.Next() {
+		var n domain.Notification
+		err := rows.Scan(
+			&n.ID,
+			&n.UserID,
+			&n.Type,
+			&n.Source.Type,
+			&n.Source.ID,
+			&n.Source.ParentID,
+			&n.CreatedAt,
+			&n.ReadAt,
+			&n.Actor.ID,
+			&n.Actor.FirstName,
+			&n.Actor.LastName,
+			&n.Actor.Username,
+			&n.Actor.ProfilePicture,
+			&n.Actor.ProfilePictureVariants,
+		)
+		if err != nil {
+			return nil, err
+		}
+		notifications = append(notifications, n)
+	}
+
+	return notifications, rows.Err()
+}
+
+// uuidStrings lets a slice of IDs be passed as a uuid[] parameter.
+func uuidStrings(ids []uuid.UUID) []string {
+	s := make([]string, len(ids))
+	for i, id := range ids {
+		s[i] = id.String()
+	}
+	return s
+}
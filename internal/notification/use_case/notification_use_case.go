@@ -0,0 +1,94 @@
+package usecase
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"github.com/Ramsi97/edu-social-backend/internal/notification/domain"
+	"github.com/Ramsi97/edu-social-backend/internal/notification/repository/interfaces"
+	"github.com/google/uuid"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+type notificationUseCase struct {
+	repo interfaces.NotificationRepository
+
+	listenersMu sync.RWMutex
+	listeners   []func(domain.Notification)
+}
+
+func NewNotificationUseCase(repo interfaces.NotificationRepository) domain.NotificationUseCase {
+	return &notificationUseCase{
+		repo: repo,
+	}
+}
+
+func (u *notificationUseCase) Notify(ctx context.Context, notifications []domain.Notification) error {
+	if len(notifications) == 0 {
+		return nil
+	}
+
+	if err := u.repo.Create(ctx, notifications); err != nil {
+		return err
+	}
+
+	u.listenersMu.RLock()
+	listeners := slices.Clone(u.listeners)
+	u.listenersMu.RUnlock()
+	if len(listeners) == 0 {
+		return nil
+	}
+
+	// Reload them so listeners get the actor's name and picture.
+	ids := make([]uuid.UUID, len(notifications))
+	for i, n := range notifications {
+		ids[i] = n.ID
+	}
+	stored, err := u.repo.GetByIDs(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	for _, n := range stored {
+		for _, listener := range listeners {
+			listener(n)
+		}
+	}
+
+	return nil
+}
+
+func (u *notificationUseCase) Withdraw(ctx context.Context, sourceType domain.SourceType, sourceID uuid.UUID, userIDs []uuid.UUID) error {
+	return u.repo.Delete(ctx, sourceType, sourceID, userIDs)
+}
+
+func (u *notificationUseCase) List(ctx context.Context, userID uuid.UUID, q domain.ListQuery) ([]domain.Notification, error) {
+	if q.Limit <= 0 {
+		q.Limit = defaultPageSize
+	}
+	if q.Limit > maxPageSize {
+		q.Limit = maxPageSize
+	}
+
+	return u.repo.List(ctx, userID, q)
+}
+
+func (u *notificationUseCase) UnreadCount(ctx context.Context, userID uuid.UUID) (int, error) {
+	return u.repo.UnreadCount(ctx, userID)
+}
+
+func (u *notificationUseCase) MarkRead(ctx context.Context, userID uuid.UUID, ids []uuid.UUID) error {
+	return u.repo.MarkRead(ctx, userID, ids)
+}
+
+func (u *notificationUseCase) OnNotify(listener func(domain.Notification)) {
+	u.listenersMu.Lock()
+	defer u.listenersMu.Unlock()
+
+	u.listeners = append(u.listeners, listener)
+}
@@ -1,48 +1,66 @@
 package http
 
 import (
-	"fmt"
+	"errors"
+	"mime/multipart"
 	"net/http"
 	"strconv"
 	"time"
 
+	authDomain "github.com/Ramsi97/edu-social-backend/internal/auth/domain"
 	"github.com/Ramsi97/edu-social-backend/internal/post/domain"
-	sharedInterfaces "github.com/Ramsi97/edu-social-backend/internal/shared/interfaces"
+	mediaDomain "github.com/Ramsi97/edu-social-backend/internal/media/domain"
+	"github.com/Ramsi97/edu-social-backend/pkg/media"
 	"github.com/Ramsi97/edu-social-backend/pkg/response"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
 
 type PostHandler struct {
-	usecase      domain.PostUseCase
-	mediaStorage sharedInterfaces.MediaStorage
+	usecase domain.PostUseCase
 }
 
 func NewPostHandler(
 	rg *gin.RouterGroup,
 	uc domain.PostUseCase,
-	ms sharedInterfaces.MediaStorage,
 ) {
 	handler := PostHandler{
-		usecase:      uc,
-		mediaStorage: ms,
+		usecase: uc,
 	}
 
 	rg.POST("", handler.CreatePost)
 	rg.GET("/feed", handler.GetFeed)
+	rg.GET("/user/:id", handler.GetUserPosts)
+	rg.GET("/:id", handler.GetPost)
+	rg.PATCH("/:id", handler.UpdatePost)
+	rg.DELETE("/:id", handler.DeletePost)
+	rg.GET("/:id/revisions", handler.GetRevisions)
 }
 
 func (p *PostHandler) CreatePost(ctx *gin.Context) {
 
 	content := ctx.PostForm("content")
 
-	file, err := ctx.FormFile("file")
-	if err != nil && err != http.ErrMissingFile {
+	// Attachments come as repeated "files" fields; a single "file" is
+	// still accepted from older clients.
+	var files []*multipart.FileHeader
+	if form, err := ctx.MultipartForm(); err == nil {
+		files = append(form.File["files"], form.File["file"]...)
+	} else if !errors.Is(err, http.ErrNotMultipart) {
 		response.Error(ctx, http.StatusBadRequest, "Invalid file", err.Error())
 		return
 	}
 
-	fmt.Println(content)
+	// Files sent ahead through upload sessions come as repeated "media_ids".
+	var mediaIDs []uuid.UUID
+	for _, v := range ctx.PostFormArray("media_ids") {
+		id, err := uuid.Parse(v)
+		if err != nil {
+			response.Error(ctx, http.StatusBadRequest, "Invalid media ID", err.Error())
+			return
+		}
+		mediaIDs = append(mediaIDs, id)
+	}
 
 	uuidString := ctx.GetString("user_id")
 	authorID, err := uuid.Parse(uuidString)
@@ -51,25 +69,14 @@ func (p *PostHandler) CreatePost(ctx *gin.Context) {
 		return
 	}
 
-	var mediaURL string
-
-	if file != nil {
-		mediaURL, err = p.mediaStorage.UploadToCloudinary(ctx, file)
-		if err != nil {
-			response.Error(ctx, http.StatusInternalServerError, "Failed to upload media", err.Error())
-			return
-		}
-	}
-
 	post := &domain.Post{
 		Author: domain.UserSummary{ID: authorID},
 		Content:  content,
-		MediaUrl: mediaURL,
 	}
 
-	err = p.usecase.CreatePost(ctx, post)
+	err = p.usecase.CreatePost(ctx, post, files, mediaIDs)
 	if err != nil {
-		response.Error(ctx, http.StatusBadRequest, "Failed to create post", err.Error())
+		writePostError(ctx, err)
 		return
 	}
 
@@ -77,36 +84,188 @@ func (p *PostHandler) CreatePost(ctx *gin.Context) {
 }
 
 func (p *PostHandler) GetFeed(ctx *gin.Context) {
-	limitStr := ctx.DefaultQuery("limit", "20")
-	lastSeenStr := ctx.Query("filter")
+	limit, lastSeenTime, ok := parsePage(ctx)
+	if !ok {
+		return
+	}
 
-	limit, err := strconv.Atoi(limitStr)
+	uuidString := ctx.GetString("user_id")
+	authorID, err := uuid.Parse(uuidString)
 	if err != nil {
-		response.Error(ctx, http.StatusBadRequest, "Invalid limit", err.Error())
+		response.Error(ctx, http.StatusBadRequest, "Invalid user ID", err.Error())
 		return
 	}
 
-	var lastSeenTime *time.Time
-	if lastSeenStr != "" {
-		parsedTime, err := time.Parse(time.RFC3339, lastSeenStr)
-		if err != nil {
-			response.Error(ctx, http.StatusBadRequest, "Invalid lastSeenTime", err.Error())
+	mode := domain.FeedMode(ctx.Query("mode"))
+
+	posts, err := p.usecase.GetFeed(ctx, mode, limit, lastSeenTime, authorID)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidFeed) {
+			response.Error(ctx, http.StatusBadRequest, "Invalid feed mode", err.Error())
 			return
 		}
-		lastSeenTime = &parsedTime
+		response.Error(ctx, http.StatusInternalServerError, "Failed to fetch feed", err.Error())
+		return
 	}
+	response.Success(ctx, http.StatusOK, "Feed fetched successfully", posts)
+}
 
-	uuidString := ctx.GetString("user_id")
-	authorID, err := uuid.Parse(uuidString)
+func (p *PostHandler) GetUserPosts(ctx *gin.Context) {
+	limit, lastSeenTime, ok := parsePage(ctx)
+	if !ok {
+		return
+	}
+
+	viewerID, err := uuid.Parse(ctx.GetString("user_id"))
 	if err != nil {
 		response.Error(ctx, http.StatusBadRequest, "Invalid user ID", err.Error())
 		return
 	}
 
-	posts, err := p.usecase.GetFeed(ctx, limit, lastSeenTime, authorID)
+	authorID, err := uuid.Parse(ctx.Param("id"))
 	if err != nil {
-		response.Error(ctx, http.StatusInternalServerError, "Failed to fetch feed", err.Error())
+		response.Error(ctx, http.StatusBadRequest, "Invalid user ID", err.Error())
 		return
 	}
-	response.Success(ctx, http.StatusOK, "Feed fetched successfully", posts)
+
+	posts, err := p.usecase.GetUserPosts(ctx, authorID, limit, lastSeenTime, viewerID)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrAuthorNotFound):
+			response.Error(ctx, http.StatusNotFound, "User not found", err.Error())
+		case errors.Is(err, domain.ErrPrivateAuthor):
+			response.Error(ctx, http.StatusForbidden, "This account is private", err.Error())
+		default:
+			response.Error(ctx, http.StatusInternalServerError, "Failed to fetch posts", err.Error())
+		}
+		return
+	}
+	response.Success(ctx, http.StatusOK, "Posts fetched successfully", posts)
+}
+
+func (p *PostHandler) GetPost(ctx *gin.Context) {
+	viewerID, postID, ok := parsePostTarget(ctx)
+	if !ok {
+		return
+	}
+
+	post, err := p.usecase.GetPost(ctx, postID, viewerID)
+	if err != nil {
+		writePostError(ctx, err)
+		return
+	}
+	response.Success(ctx, http.StatusOK, "Post fetched successfully", post)
+}
+
+func (p *PostHandler) UpdatePost(ctx *gin.Context) {
+	actorID, postID, ok := parsePostTarget(ctx)
+	if !ok {
+		return
+	}
+
+	var req domain.UpdatePostRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.Error(ctx, http.StatusBadRequest, "Invalid request", err.Error())
+		return
+	}
+
+	role := authDomain.Role(ctx.GetString("role"))
+
+	post, err := p.usecase.UpdatePost(ctx, postID, actorID, role, req.Content)
+	if err != nil {
+		writePostError(ctx, err)
+		return
+	}
+	response.Success(ctx, http.StatusOK, "Post updated successfully", post)
+}
+
+func (p *PostHandler) DeletePost(ctx *gin.Context) {
+	actorID, postID, ok := parsePostTarget(ctx)
+	if !ok {
+		return
+	}
+
+	role := authDomain.Role(ctx.GetString("role"))
+
+	if err := p.usecase.DeletePost(ctx, postID, actorID, role); err != nil {
+		writePostError(ctx, err)
+		return
+	}
+	response.Success(ctx, http.StatusOK, "Post deleted successfully", nil)
+}
+
+func (p *PostHandler) GetRevisions(ctx *gin.Context) {
+	viewerID, postID, ok := parsePostTarget(ctx)
+	if !ok {
+		return
+	}
+
+	revisions, err := p.usecase.GetRevisions(ctx, postID, viewerID)
+	if err != nil {
+		writePostError(ctx, err)
+		return
+	}
+	response.Success(ctx, http.StatusOK, "Revisions fetched successfully", revisions)
+}
+
+// parsePostTarget reads the authenticated user and the :id post parameter.
+func parsePostTarget(ctx *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	userID, err := uuid.Parse(ctx.GetString("user_id"))
+	if err != nil {
+		response.Error(ctx, http.StatusBadRequest, "Invalid user ID", err.Error())
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	postID, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		response.Error(ctx, http.StatusBadRequest, "Invalid post ID", err.Error())
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return userID, postID, true
+}
+
+func writePostError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrPostNotFound):
+		response.Error(ctx, http.StatusNotFound, "Post not found", err.Error())
+	case errors.Is(err, domain.ErrNotPostAuthor):
+		response.Error(ctx, http.StatusForbidden, "Not allowed", err.Error())
+	case errors.Is(err, domain.ErrEmptyPost):
+		response.Error(ctx, http.StatusBadRequest, "Post cannot be empty", err.Error())
+	case errors.Is(err, domain.ErrTooManyAttachments), errors.Is(err, domain.ErrUnsupportedMedia),
+		errors.Is(err, media.ErrUnsupportedType), errors.Is(err, media.ErrCorruptImage),
+		errors.Is(err, media.ErrImageTooLarge), errors.Is(err, media.ErrPolyglot),
+		errors.Is(err, mediaDomain.ErrMediaNotFound):
+		response.Error(ctx, http.StatusBadRequest, "Invalid attachments", err.Error())
+	case errors.Is(err, domain.ErrAttachmentTooLarge), errors.Is(err, media.ErrFileTooLarge):
+		response.Error(ctx, http.StatusRequestEntityTooLarge, "Attachment too large", err.Error())
+	default:
+		response.Error(ctx, http.StatusInternalServerError, "Server Error", err.Error())
+	}
+}
+
+// parsePage reads the limit and the "filter" cursor (created_at of the last
+// post already shown) shared by every post listing.
+func parsePage(ctx *gin.Context) (int, *time.Time, bool) {
+	limitStr := ctx.DefaultQuery("limit", "20")
+	lastSeenStr := ctx.Query("filter")
+
+	limit, err := strconv.Atoi(limitStr)
+	if err != nil {
+		response.Error(ctx, http.StatusBadRequest, "Invalid limit", err.Error())
+		return 0, nil, false
+	}
+
+	var lastSeenTime *time.Time
+	if lastSeenStr != "" {
+		parsedTime, err := time.Parse(time.RFC3339, lastSeenStr)
+		if err != nil {
+			response.Error(ctx, http.StatusBadRequest, "Invalid lastSeenTime", err.Error())
+			return 0, nil, false
+		}
+		lastSeenTime = &parsedTime
+	}
+
+	return limit, lastSeenTime, true
 }
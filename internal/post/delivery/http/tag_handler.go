@@ -0,0 +1,107 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/Ramsi97/edu-social-backend/internal/post/domain"
+	"github.com/Ramsi97/edu-social-backend/pkg/response"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type TagHandler struct {
+	usecase domain.PostUseCase
+}
+
+func NewTagHandler(
+	rg *gin.RouterGroup,
+	uc domain.PostUseCase,
+) {
+	handler := TagHandler{
+		usecase: uc,
+	}
+
+	rg.GET("/trending", handler.Trending)
+	rg.GET("/search", handler.Search)
+	rg.GET("/:tag/posts", handler.GetTagPosts)
+}
+
+func (t *TagHandler) GetTagPosts(ctx *gin.Context) {
+	limit, lastSeenTime, ok := parsePage(ctx)
+	if !ok {
+		return
+	}
+
+	viewerID, err := uuid.Parse(ctx.GetString("user_id"))
+	if err != nil {
+		response.Error(ctx, http.StatusBadRequest, "Invalid user ID", err.Error())
+		return
+	}
+
+	posts, err := t.usecase.GetTagPosts(ctx, ctx.Param("tag"), limit, lastSeenTime, viewerID)
+	if err != nil {
+		writeTagError(ctx, err)
+		return
+	}
+	response.Success(ctx, http.StatusOK, "Posts fetched successfully", posts)
+}
+
+func (t *TagHandler) Search(ctx *gin.Context) {
+	viewerID, err := uuid.Parse(ctx.GetString("user_id"))
+	if err != nil {
+		response.Error(ctx, http.StatusBadRequest, "Invalid user ID", err.Error())
+		return
+	}
+
+	limit, ok := parseLimit(ctx)
+	if !ok {
+		return
+	}
+
+	tags, err := t.usecase.SearchTags(ctx, ctx.Query("q"), limit, viewerID)
+	if err != nil {
+		writeTagError(ctx, err)
+		return
+	}
+	response.Success(ctx, http.StatusOK, "Tags fetched successfully", tags)
+}
+
+func (t *TagHandler) Trending(ctx *gin.Context) {
+	limit, ok := parseLimit(ctx)
+	if !ok {
+		return
+	}
+
+	tags, err := t.usecase.TrendingTags(ctx, limit)
+	if err != nil {
+		writeTagError(ctx, err)
+		return
+	}
+	response.Success(ctx, http.StatusOK, "Trending tags fetched successfully", tags)
+}
+
+// parseLimit reads an optional limit; zero leaves the default to the use
+// case.
+func parseLimit(ctx *gin.Context) (int, bool) {
+	v := ctx.Query("limit")
+	if v == "" {
+		return 0, true
+	}
+
+	limit, err := strconv.Atoi(v)
+	if err != nil {
+		response.Error(ctx, http.StatusBadRequest, "Invalid limit", err.Error())
+		return 0, false
+	}
+	return limit, true
+}
+
+func writeTagError(ctx *gin.Context, err error) {
+	if errors.Is(err, domain.ErrInvalidTag) {
+		response.Error(ctx, http.StatusBadRequest, "Invalid tag", err.Error())
+		return
+	}
+	response.Error(ctx, http.StatusInternalServerError, "Server Error", err.Error())
+}
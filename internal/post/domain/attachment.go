@@ -0,0 +1,56 @@
+package domain
+
+import (
+	"errors"
+
+	mediaDomain "github.com/Ramsi97/edu-social-backend/internal/media/domain"
+	"github.com/google/uuid"
+)
+
+// MediaType is the kind of file attached to a post.
+type MediaType string
+
+const (
+	MediaImage    MediaType = "image"
+	MediaVideo    MediaType = "video"
+	MediaDocument MediaType = "document"
+)
+
+// Attachment is one file of a post. Width and Height are set for images and
+// videos, Duration (in seconds) for videos, when they could be read.
+// Variants appear on images once they have been made.
+type Attachment struct {
+	ID              uuid.UUID             `json:"id"`
+	Type            MediaType             `json:"type"`
+	URL             string                `json:"url"`
+	SizeBytes       int64                 `json:"size_bytes"`
+	Width           *int                  `json:"width,omitempty"`
+	Height          *int                  `json:"height,omitempty"`
+	DurationSeconds *float64              `json:"duration_seconds,omitempty"`
+	FileName        string                `json:"file_name"`
+	Variants        *mediaDomain.Variants `json:"variants,omitempty"`
+}
+
+// AttachmentConfig limits what can be attached to a single post.
+type AttachmentConfig struct {
+	MaxAttachments int
+	// MaxBytes is the size limit per file for each media type.
+	MaxBytes map[MediaType]int64
+}
+
+func DefaultAttachmentConfig() AttachmentConfig {
+	return AttachmentConfig{
+		MaxAttachments: 10,
+		MaxBytes: map[MediaType]int64{
+			MediaImage:    10 << 20,
+			MediaVideo:    100 << 20,
+			MediaDocument: 25 << 20,
+		},
+	}
+}
+
+var (
+	ErrTooManyAttachments = errors.New("too many attachments")
+	ErrUnsupportedMedia   = errors.New("unsupported file type")
+	ErrAttachmentTooLarge = errors.New("attachment is too large")
+)
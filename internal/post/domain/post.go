@@ -2,8 +2,14 @@ package domain
 
 import (
 	"context"
+	"errors"
+	"mime/multipart"
 	"time"
 
+	authDomain "github.com/Ramsi97/edu-social-backend/internal/auth/domain"
+	commentDomain "github.com/Ramsi97/edu-social-backend/internal/comment/domain"
+	mediaDomain "github.com/Ramsi97/edu-social-backend/internal/media/domain"
+	mentionDomain "github.com/Ramsi97/edu-social-backend/internal/mention/domain"
 	"github.com/google/uuid"
 )
 
@@ -11,11 +17,47 @@ type Post struct {
 	ID        uuid.UUID `json:"id"`
 	Author  UserSummary `json:"author"`
 	Content   string    `json:"content"`
+	// MediaUrl is the first attachment's URL, kept for older clients.
 	MediaUrl  string    `json:"media_url"`
+	Attachments []Attachment `json:"attachments"`
+	// Tags are the hashtags in Content, normalized to lowercase.
+	Tags []string `json:"tags"`
+	// Mentions are the users @mentioned in Content.
+	Mentions mentionDomain.Mentions `json:"mentions"`
 	LikeCount int       `json:"like_count"`
 	CommentCount int `json:"comment_count"`
 	CreatedAt time.Time `json:"created_at"`
 	LikedByMe bool `json:"liked_by_me"`
+	// Edited is set once the content has been changed; the earlier versions
+	// are kept as revisions.
+	Edited   bool       `json:"edited"`
+	EditedAt *time.Time `json:"edited_at,omitempty"`
+}
+
+// PostRevision is an earlier version of a post's content: what it said
+// before EditedBy changed it at EditedAt.
+type PostRevision struct {
+	ID       uuid.UUID `json:"id"`
+	PostID   uuid.UUID `json:"post_id"`
+	Content  string    `json:"content"`
+	// EditedBy is nil once the editor's account has been deleted.
+	EditedBy *uuid.UUID `json:"edited_by"`
+	EditedAt time.Time `json:"edited_at"`
+}
+
+// PostDetail is a single post together with the first page of its
+// comments. NextCommentsCursor and NextCommentsCursorID are set when there
+// are more comments to load through the comment listing, and go in its
+// after and after_id parameters.
+type PostDetail struct {
+	Post
+	Comments             []commentDomain.Comment `json:"comments"`
+	NextCommentsCursor   *time.Time              `json:"next_comments_cursor,omitempty"`
+	NextCommentsCursorID *uuid.UUID              `json:"next_comments_cursor_id,omitempty"`
+}
+
+type UpdatePostRequest struct {
+	Content string `json:"content"`
 }
 
 type UserSummary struct {
@@ -23,10 +65,56 @@ type UserSummary struct {
     FirstName     string    `json:"first_name"`
     LastName      string    `json:"last_name"`
     ProfilePicture string   `json:"profile_picture"`
+    ProfilePictureVariants *mediaDomain.Variants `json:"profile_picture_variants,omitempty"`
     JoinedYear    time.Time       `json:"joined_year"`
 }
 
+// FeedMode selects which posts the home feed shows.
+type FeedMode string
+
+const (
+	// FeedGlobal is every post the viewer is allowed to see.
+	FeedGlobal FeedMode = "global"
+	// FeedFollowing is the viewer's own posts and those of accounts they follow.
+	FeedFollowing FeedMode = "following"
+)
+
+var (
+	ErrAuthorNotFound = errors.New("user not found")
+	ErrPrivateAuthor  = errors.New("this account is private")
+	ErrInvalidFeed    = errors.New("feed mode must be global or following")
+	ErrPostNotFound   = errors.New("post not found")
+	ErrEmptyPost      = errors.New("post cannot be empty")
+	ErrNotPostAuthor  = errors.New("only the author or a moderator can change this post")
+)
+
 type PostUseCase interface {
-	CreatePost(ctx context.Context, post *Post) error
-	GetFeed(ctx context.Context, limit int, lastSeenTime *time.Time, authorID uuid.UUID) ([]Post, error)
+	// CreatePost uploads the files and attaches them to the post in the
+	// order given, followed by the media finalized from upload sessions.
+	CreatePost(ctx context.Context, post *Post, files []*multipart.FileHeader, mediaIDs []uuid.UUID) error
+	GetFeed(ctx context.Context, mode FeedMode, limit int, lastSeenTime *time.Time, authorID uuid.UUID) ([]Post, error)
+	// GetUserPosts is a single user's timeline as seen by the viewer.
+	GetUserPosts(ctx context.Context, authorID uuid.UUID, limit int, lastSeenTime *time.Time, viewerID uuid.UUID) ([]Post, error)
+
+	// GetPost returns a post the viewer is allowed to see; hidden and
+	// deleted posts are reported as not found.
+	GetPost(ctx context.Context, postID, viewerID uuid.UUID) (*PostDetail, error)
+
+	// UpdatePost and DeletePost are open to the post's author and to
+	// moderators and admins.
+	UpdatePost(ctx context.Context, postID, actorID uuid.UUID, role authDomain.Role, content string) (*Post, error)
+	// DeletePost hides the post everywhere, drops its likes and removes its
+	// media from storage. Comments are kept but can no longer be reached.
+	DeletePost(ctx context.Context, postID, actorID uuid.UUID, role authDomain.Role) error
+	// GetRevisions lists the earlier versions of a post, newest first.
+	GetRevisions(ctx context.Context, postID, viewerID uuid.UUID) ([]PostRevision, error)
+
+	// GetTagPosts pages through the posts carrying a hashtag, newest first.
+	GetTagPosts(ctx context.Context, tag string, limit int, lastSeenTime *time.Time, viewerID uuid.UUID) ([]Post, error)
+	// SearchTags completes a partly typed hashtag, most used first.
+	SearchTags(ctx context.Context, prefix string, limit int, viewerID uuid.UUID) ([]Tag, error)
+	// TrendingTags ranks the hashtags of public posts by recent use.
+	TrendingTags(ctx context.Context, limit int) ([]TrendingTag, error)
+	// BackfillTags tags the posts written before hashtags were extracted.
+	BackfillTags(ctx context.Context) error
 }
@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// Tag is a hashtag with the number of posts the viewer can see under it.
+type Tag struct {
+	Name      string `json:"name"`
+	PostCount int    `json:"post_count"`
+}
+
+// TrendingTag is a hashtag scored by recent use. Authors is how many
+// different people used it within the window.
+type TrendingTag struct {
+	Name    string  `json:"name"`
+	Score   float64 `json:"score"`
+	Authors int     `json:"authors"`
+}
+
+// TagConfig sets how trending tags are scored. Every author who used a tag
+// within TrendingWindow adds to its score once, weighted by how recently
+// they last used it: a use TrendingHalfLife ago counts half as much as one
+// made now.
+type TagConfig struct {
+	TrendingWindow   time.Duration
+	TrendingHalfLife time.Duration
+	// TrendingMinAuthors keeps a tag used by only a handful of people off
+	// the list.
+	TrendingMinAuthors int
+}
+
+func DefaultTagConfig() TagConfig {
+	return TagConfig{
+		TrendingWindow:     24 * time.Hour,
+		TrendingHalfLife:   6 * time.Hour,
+		TrendingMinAuthors: 2,
+	}
+}
+
+var ErrInvalidTag = errors.New("tag must be letters, digits or underscores and at most 64 long")
@@ -10,5 +10,33 @@ import (
 
 type PostRepository interface{
 	CreatePost(ctx context.Context, post *domain.Post) error
-	GetFeed(ctx context.Context, limit int, lastSeenTime *time.Time, currentUserID uuid.UUID) ([]domain.Post, error)
+	GetFeed(ctx context.Context, mode domain.FeedMode, limit int, lastSeenTime *time.Time, currentUserID uuid.UUID) ([]domain.Post, error)
+	GetUserPosts(ctx context.Context, authorID uuid.UUID, limit int, lastSeenTime *time.Time, currentUserID uuid.UUID) ([]domain.Post, error)
+	// CanViewAuthor reports whether the viewer may see the author's posts.
+	CanViewAuthor(ctx context.Context, viewerID, authorID uuid.UUID) (bool, error)
+
+	// GetPost returns the post with its counts when the viewer may see it.
+	GetPost(ctx context.Context, postID, viewerID uuid.UUID) (*domain.Post, error)
+	// GetByID returns a post that has not been deleted, without counts.
+	GetByID(ctx context.Context, postID uuid.UUID) (*domain.Post, error)
+	// UpdateContent replaces the content and tags and files the old content
+	// as a revision, returning the time of the edit.
+	UpdateContent(ctx context.Context, postID, editorID uuid.UUID, content string, tags []string) (time.Time, error)
+	// SoftDelete marks the post deleted, clears its media and likes, and
+	// returns the URLs of the media it had.
+	SoftDelete(ctx context.Context, postID, deletedBy uuid.UUID) ([]string, error)
+	GetRevisions(ctx context.Context, postID uuid.UUID) ([]domain.PostRevision, error)
+
+	GetTagPosts(ctx context.Context, tag string, limit int, lastSeenTime *time.Time, viewerID uuid.UUID) ([]domain.Post, error)
+	// SearchTags counts only posts the viewer can see in any feed.
+	SearchTags(ctx context.Context, prefix string, limit int, viewerID uuid.UUID) ([]domain.Tag, error)
+	// TrendingTags scores the tags of public posts made since the window
+	// start, weighting each author's latest use by its age at now.
+	TrendingTags(ctx context.Context, now time.Time, cfg domain.TagConfig, limit int) ([]domain.TrendingTag, error)
+
+	// PendingTagBackfill returns up to limit posts still waiting to be
+	// tagged, with only their ID, content and creation time filled in.
+	PendingTagBackfill(ctx context.Context, limit int) ([]domain.Post, error)
+	// SetBackfilledTags replaces the post's tags and marks it as tagged.
+	SetBackfilledTags(ctx context.Context, postID uuid.UUID, createdAt time.Time, tags []string) error
 }
\ No newline at end of file
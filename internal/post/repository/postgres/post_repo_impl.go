@@ -3,10 +3,14 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"errors"
 	"time"
 
+	notificationDomain "github.com/Ramsi97/edu-social-backend/internal/notification/domain"
 	"github.com/Ramsi97/edu-social-backend/internal/post/domain"
 	"github.com/Ramsi97/edu-social-backend/internal/post/repository/interfaces"
+	sharedPostgres "github.com/Ramsi97/edu-social-backend/internal/shared/infrastructure/postgres"
 	"github.com/google/uuid"
 )
 
@@ -24,12 +28,18 @@ func (r *postRepo) CreatePost(ctx context.Context, post *domain.Post) error {
 	post.ID = uuid.New()
 	post.CreatedAt = time.Now()
 
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
 	query := `
 		INSERT INTO posts (id, author_id, content, media_url, created_at)
 		VALUES ($1, $2, $3, $4, $5)
 	`
 
-	_, err := r.db.ExecContext(
+	_, err = tx.ExecContext(
 		ctx,
 		query,
 		post.ID,
@@ -38,60 +48,201 @@ func (r *postRepo) CreatePost(ctx context.Context, post *domain.Post) error {
 		post.MediaUrl,
 		post.CreatedAt,
 	)
+	if err != nil {
+		return err
+	}
+
+	for i := range post.Attachments {
+		a := &post.Attachments[i]
+		a.ID = uuid.New()
+
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO post_media (
+				id, post_id, position, type, url, size_bytes,
+				width, height, duration_seconds, file_name
+			)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		`, a.ID, post.ID, i, a.Type, a.URL, a.SizeBytes, a.Width, a.Height, a.DurationSeconds, a.FileName)
+		if err != nil {
+			return err
+		}
+	}
 
-	return err
+	if err := addTags(ctx, tx, post.ID, post.CreatedAt, post.Tags); err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
 
+// visibleAuthor hides authors of row u whom the viewer ($2) may not see.
+var visibleAuthor = sharedPostgres.VisibleTo("u", "$2")
+
+// notBlocked hides authors who are on either side of a block with the
+// viewer ($2).
+var notBlocked = sharedPostgres.NotBlocked("u", "$2")
+
+// notMuted hides authors the viewer ($2) has muted. Mutes only apply to the
+// feeds; a muted user's own timeline stays reachable.
+const notMuted = `
+	NOT EXISTS (
+		SELECT 1 FROM user_mutes m
+		WHERE m.muter_id = $2 AND m.muted_id = u.id
+	)
+`
+
 func (r *postRepo) GetFeed(
 	ctx context.Context,
+	mode domain.FeedMode,
 	limit int,
 	lastSeenTime *time.Time,
 	currentUserID uuid.UUID, // current logged-in user
 ) ([]domain.Post, error) {
-	var rows *sql.Rows
-	var err error
-
-	// Base query with like count, liked_by_me, and comment count
-	baseQuery := `
-        SELECT 
-            p.id,
-            p.content,
-            p.media_url,
-            p.created_at,
-
-            u.id AS author_id,
-            u.first_name,
-            u.last_name,
-            u.profile_picture,
-            u.joined_year,
-
-            COUNT(DISTINCT pl.post_id) AS like_count,
-            CASE WHEN ul.user_id IS NOT NULL THEN TRUE ELSE FALSE END AS liked_by_me,
-            COUNT(DISTINCT c.id) AS comment_count
-        FROM posts p
-        JOIN users u ON p.author_id = u.id
-        LEFT JOIN posts_likes pl ON p.id = pl.post_id
-        LEFT JOIN posts_likes ul ON p.id = ul.post_id AND ul.user_id = $2
-        LEFT JOIN comments c ON p.id = c.post_id
-    `
-
-	if lastSeenTime == nil {
-		query := baseQuery + `
-            GROUP BY p.id, u.id, ul.user_id
-            ORDER BY p.created_at DESC
-            LIMIT $1
-        `
-		rows, err = r.db.QueryContext(ctx, query, limit, currentUserID)
-	} else {
-		query := baseQuery + `
-            WHERE p.created_at < $3
-            GROUP BY p.id, u.id, ul.user_id
-            ORDER BY p.created_at DESC
-            LIMIT $1
-        `
-		rows, err = r.db.QueryContext(ctx, query, limit, currentUserID, *lastSeenTime)
+	if mode == domain.FeedFollowing {
+		// A semi-join against follows lets the planner walk
+		// idx_posts_author_created_at per followed author instead of
+		// expanding a list of IDs.
+		return r.queryPosts(ctx, `
+			(
+				p.author_id = $2
+				OR p.author_id IN (
+					SELECT followee_id FROM follows
+					WHERE follower_id = $2 AND status = 'accepted'
+				)
+			)
+			AND u.suspended_at IS NULL
+			AND (p.author_id = $2 OR u.profile_visibility <> 'only_me')
+			AND`+notBlocked+`
+			AND`+notMuted, limit, currentUserID, lastSeenTime)
+	}
+
+	return r.queryPosts(ctx, visibleAuthor+"AND"+notMuted, limit, currentUserID, lastSeenTime)
+}
+
+func (r *postRepo) GetUserPosts(
+	ctx context.Context,
+	authorID uuid.UUID,
+	limit int,
+	lastSeenTime *time.Time,
+	currentUserID uuid.UUID,
+) ([]domain.Post, error) {
+	return r.queryPosts(ctx, "p.author_id = $4 AND"+visibleAuthor, limit, currentUserID, lastSeenTime, authorID)
+}
+
+func (r *postRepo) GetPost(ctx context.Context, postID, viewerID uuid.UUID) (*domain.Post, error) {
+	posts, err := r.queryPosts(ctx, "p.id = $4 AND"+visibleAuthor, 1, viewerID, nil, postID)
+	if err != nil {
+		return nil, err
+	}
+	if len(posts) == 0 {
+		return nil, domain.ErrPostNotFound
+	}
+
+	return &posts[0], nil
+}
+
+func (r *postRepo) CanViewAuthor(ctx context.Context, viewerID, authorID uuid.UUID) (bool, error) {
+	query := `
+		SELECT ` + visibleAuthor + `
+		FROM users u
+		WHERE u.id = $1
+	`
+
+	var visible bool
+	err := r.db.QueryRowContext(ctx, query, authorID, viewerID).Scan(&visible)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, domain.ErrAuthorNotFound
+		}
+		return false, err
 	}
 
+	return visible, nil
+}
+
+// attachmentsColumn selects the attachments of post p as a JSON array in
+// their display order.
+var attachmentsColumn = `
+	COALESCE((
+		SELECT json_agg(json_build_object(
+			'id', pm.id,
+			'type', pm.type,
+			'url', pm.url,
+			'size_bytes', pm.size_bytes,
+			'width', pm.width,
+			'height', pm.height,
+			'duration_seconds', pm.duration_seconds,
+			'file_name', pm.file_name,
+			'variants', ` + sharedPostgres.VariantsColumn("pm.url") + `
+		) ORDER BY pm.position)
+		FROM post_media pm
+		WHERE pm.post_id = p.id
+	), '[]') AS attachments
+`
+
+// tagsColumn selects the hashtags of post p as a JSON array.
+const tagsColumn = `
+	COALESCE((
+		SELECT json_agg(t.name ORDER BY t.name)
+		FROM post_tags pt
+		JOIN tags t ON t.id = pt.tag_id
+		WHERE pt.post_id = p.id
+	), '[]') AS tags
+`
+
+// queryPosts pages through the posts matching filter, newest first. The page
+// is picked before likes and comments are counted so the counting only
+// touches the posts actually returned. $1 is the limit, $2 the viewer and
+// $3 the optional created_at cursor; extra args start at $4.
+func (r *postRepo) queryPosts(
+	ctx context.Context,
+	filter string,
+	limit int,
+	currentUserID uuid.UUID,
+	lastSeenTime *time.Time,
+	extra ...any,
+) ([]domain.Post, error) {
+	query := `
+		WITH page AS (
+			SELECT p.id
+			FROM posts p
+			JOIN users u ON p.author_id = u.id
+			WHERE ` + filter + `
+				AND p.deleted_at IS NULL
+				AND ($3::timestamptz IS NULL OR p.created_at < $3)
+			ORDER BY p.created_at DESC
+			LIMIT $1
+		)
+		SELECT
+			p.id,
+			p.content,
+			p.media_url,
+			p.created_at,
+			p.edited_at,
+
+			u.id AS author_id,
+			u.first_name,
+			u.last_name,
+			COALESCE(u.profile_picture, ''),
+			` + sharedPostgres.VariantsColumn("u.profile_picture") + `,
+			u.joined_year,
+
+			(SELECT COUNT(*) FROM posts_likes pl WHERE pl.post_id = p.id) AS like_count,
+			EXISTS (
+				SELECT 1 FROM posts_likes ul WHERE ul.post_id = p.id AND ul.user_id = $2
+			) AS liked_by_me,
+			(SELECT COUNT(*) FROM comments c WHERE c.post_id = p.id) AS comment_count,
+			` + attachmentsColumn + `,
+			` + tagsColumn + `,
+			` + sharedPostgres.MentionsColumn(string(notificationDomain.SourcePost), "p.id") + `
+		FROM page
+		JOIN posts p ON p.id = page.id
+		JOIN users u ON p.author_id = u.id
+		ORDER BY p.created_at DESC
+	`
+
+	args := append([]any{limit, currentUserID, lastSeenTime}, extra...)
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -102,27 +253,225 @@ func (r *postRepo) GetFeed(
 	for rows.Next() {
 		var p domain.Post
 		var author domain.UserSummary
+		var attachments, tags []byte
 
 		if err := rows.Scan(
 			&p.ID,
 			&p.Content,
 			&p.MediaUrl,
 			&p.CreatedAt,
+			&p.EditedAt,
 			&author.ID,
 			&author.FirstName,
 			&author.LastName,
 			&author.ProfilePicture,
+			&author.ProfilePictureVariants,
 			&author.JoinedYear,
 			&p.LikeCount,
 			&p.LikedByMe,
 			&p.CommentCount,
+			&attachments,
+			&tags,
+			&p.Mentions,
 		); err != nil {
 			return nil, err
 		}
 
+		if err := json.Unmarshal(attachments, &p.Attachments); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(tags, &p.Tags); err != nil {
+			return nil, err
+		}
+
 		p.Author = author
+		p.Edited = p.EditedAt != nil
 		posts = append(posts, p)
 	}
 
-	return posts, nil
+	return posts, rows.Err()
+}
+
+func (r *postRepo) GetByID(ctx context.Context, postID uuid.UUID) (*domain.Post, error) {
+	query := `
+		SELECT p.id, p.author_id, p.content, p.media_url, p.created_at, p.edited_at,
+			` + attachmentsColumn + `,
+			` + tagsColumn + `,
+			` + sharedPostgres.MentionsColumn(string(notificationDomain.SourcePost), "p.id") + `
+		FROM posts p
+		WHERE p.id = $1 AND p.deleted_at IS NULL
+	`
+
+	var p domain.Post
+	var attachments, tags []byte
+	err := r.db.QueryRowContext(ctx, query, postID).Scan(
+		&p.ID,
+		&p.Author.ID,
+		&p.Content,
+		&p.MediaUrl,
+		&p.CreatedAt,
+		&p.EditedAt,
+		&attachments,
+		&tags,
+		&p.Mentions,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrPostNotFound
+		}
+		return nil, err
+	}
+
+	if err := json.Unmarshal(attachments, &p.Attachments); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(tags, &p.Tags); err != nil {
+		return nil, err
+	}
+
+	p.Edited = p.EditedAt != nil
+	return &p, nil
+}
+
+func (r *postRepo) UpdateContent(ctx context.Context, postID, editorID uuid.UUID, content string, tags []string) (time.Time, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return time.Time{}, err
+	}
+	defer tx.Rollback()
+
+	var previous string
+	var createdAt time.Time
+	err = tx.QueryRowContext(ctx, `
+		SELECT content, created_at FROM posts
+		WHERE id = $1 AND deleted_at IS NULL
+		FOR UPDATE
+	`, postID).Scan(&previous, &createdAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return time.Time{}, domain.ErrPostNotFound
+		}
+		return time.Time{}, err
+	}
+
+	editedAt := time.Now()
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO post_revisions (id, post_id, content, edited_by, edited_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`, uuid.New(), postID, previous, editorID, editedAt)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE posts SET content = $2, edited_at = $3 WHERE id = $1
+	`, postID, content, editedAt)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		DELETE FROM post_tags pt
+		USING tags t
+		WHERE pt.post_id = $1 AND t.id = pt.tag_id AND t.name <> ALL(COALESCE($2::text[], '{}'))
+	`, postID, tags)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	// Tags keep the post's own time, so an edit doesn't make a tag trend.
+	if err := addTags(ctx, tx, postID, createdAt, tags); err != nil {
+		return time.Time{}, err
+	}
+
+	return editedAt, tx.Commit()
+}
+
+func (r *postRepo) SoftDelete(ctx context.Context, postID, deletedBy uuid.UUID) ([]string, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// The sub-select sees the row as it was before the update.
+	var mediaURL string
+	err = tx.QueryRowContext(ctx, `
+		UPDATE posts p
+		SET deleted_at = NOW(), deleted_by = $2, media_url = ''
+		FROM (SELECT id, media_url FROM posts WHERE id = $1 AND deleted_at IS NULL FOR UPDATE) old
+		WHERE p.id = old.id
+		RETURNING old.media_url
+	`, postID, deletedBy).Scan(&mediaURL)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrPostNotFound
+		}
+		return nil, err
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM posts_likes WHERE post_id = $1`, postID); err != nil {
+		return nil, err
+	}
+
+	rows, err := tx.QueryContext(ctx, `DELETE FROM post_media WHERE post_id = $1 RETURNING url`, postID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// media_url repeats the first attachment, so skip duplicates.
+	urls := []string{}
+	seen := map[string]bool{"": true}
+	if !seen[mediaURL] {
+		seen[mediaURL] = true
+		urls = append(urls, mediaURL)
+	}
+	for rows.Next() {
+		var url string
+		if err := rows.Scan(&url); err != nil {
+			return nil, err
+		}
+		if !seen[url] {
+			seen[url] = true
+			urls = append(urls, url)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// Attachments that came from upload sessions leave their records too.
+	if _, err := tx.ExecContext(ctx, `DELETE FROM media WHERE url = ANY($1::text[])`, urls); err != nil {
+		return nil, err
+	}
+
+	return urls, tx.Commit()
+}
+
+func (r *postRepo) GetRevisions(ctx context.Context, postID uuid.UUID) ([]domain.PostRevision, error) {
+	query := `
+		SELECT id, post_id, content, edited_by, edited_at
+		FROM post_revisions
+		WHERE post_id = $1
+		ORDER BY edited_at DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, postID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	revisions := []domain.PostRevision{}
+	for rows.Next() {
+		var rev domain.PostRevision
+		if err := rows.Scan(&rev.ID, &rev.PostID, &rev.Content, &rev.EditedBy, &rev.EditedAt); err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, rev)
+	}
+
+	return revisions, rows.Err()
 }
@@ -0,0 +1,193 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/Ramsi97/edu-social-backend/internal/post/domain"
+	"github.com/google/uuid"
+)
+
+// addTags links the post to its tags, creating the ones not seen before.
+// createdAt is the post's, which tag pages and trending go by.
+func addTags(ctx context.Context, tx *sql.Tx, postID uuid.UUID, createdAt time.Time, tags []string) error {
+	if len(tags) == 0 {
+		return nil
+	}
+
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO tags (id, name)
+		SELECT gen_random_uuid(), name FROM unnest($1::text[]) AS name
+		ON CONFLICT (name) DO NOTHING
+	`, tags)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO post_tags (post_id, tag_id, created_at)
+		SELECT $1, t.id, $3
+		FROM tags t
+		WHERE t.name = ANY($2::text[])
+		ON CONFLICT (post_id, tag_id) DO NOTHING
+	`, postID, tags, createdAt)
+	return err
+}
+
+func (r *postRepo) GetTagPosts(
+	ctx context.Context,
+	tag string,
+	limit int,
+	lastSeenTime *time.Time,
+	viewerID uuid.UUID,
+) ([]domain.Post, error) {
+	return r.queryPosts(ctx, `
+		EXISTS (
+			SELECT 1 FROM post_tags pt
+			JOIN tags t ON t.id = pt.tag_id
+			WHERE pt.post_id = p.id AND t.name = $4
+		)
+		AND`+visibleAuthor+"AND"+notMuted, limit, viewerID, lastSeenTime, tag)
+}
+
+func (r *postRepo) SearchTags(ctx context.Context, prefix string, limit int, viewerID uuid.UUID) ([]domain.Tag, error) {
+	query := `
+		SELECT t.name, COUNT(*) AS post_count
+		FROM tags t
+		JOIN post_tags pt ON pt.tag_id = t.id
+		JOIN posts p ON p.id = pt.post_id AND p.deleted_at IS NULL
+		JOIN users u ON u.id = p.author_id
+		WHERE t.name LIKE $1
+			AND` + visibleAuthor + `
+		GROUP BY t.name
+		ORDER BY post_count DESC, t.name
+		LIMIT $3
+	`
+
+	// Tags can hold underscores, which LIKE would take as a wildcard.
+	pattern := strings.ReplaceAll(prefix, "_", `\_`) + "%"
+
+	rows, err := r.db.QueryContext(ctx, query, pattern, viewerID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tags := []domain.Tag{}
+	for rows.Next() {
+		var t domain.Tag
+		if err := rows.Scan(&t.Name, &t.PostCount); err != nil {
+			return nil, err
+		}
+		tags = append(tags, t)
+	}
+
+	return tags, rows.Err()
+}
+
+// TrendingTags only reads posts anyone may see, so the list is the same
+// for every viewer and gives nothing away about private accounts.
+func (r *postRepo) TrendingTags(ctx context.Context, now time.Time, cfg domain.TagConfig, limit int) ([]domain.TrendingTag, error) {
+	query := `
+		WITH latest AS (
+			SELECT DISTINCT ON (pt.tag_id, p.author_id) pt.tag_id, pt.created_at
+			FROM post_tags pt
+			JOIN posts p ON p.id = pt.post_id AND p.deleted_at IS NULL
+			JOIN users u ON u.id = p.author_id
+			WHERE pt.created_at > $1
+				AND pt.created_at <= $2
+				AND u.suspended_at IS NULL
+				AND NOT u.is_private
+				AND u.profile_visibility = 'everyone'
+			ORDER BY pt.tag_id, p.author_id, pt.created_at DESC
+		)
+		SELECT
+			t.name,
+			SUM(power(0.5, EXTRACT(EPOCH FROM ($2::timestamptz - l.created_at))::float8 / $3::float8)) AS score,
+			COUNT(*) AS authors
+		FROM latest l
+		JOIN tags t ON t.id = l.tag_id
+		GROUP BY t.name
+		HAVING COUNT(*) >= $4
+		ORDER BY score DESC, t.name
+		LIMIT $5
+	`
+
+	rows, err := r.db.QueryContext(ctx, query,
+		now.Add(-cfg.TrendingWindow),
+		now,
+		cfg.TrendingHalfLife.Seconds(),
+		cfg.TrendingMinAuthors,
+		limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tags := []domain.TrendingTag{}
+	for rows.Next() {
+		var t domain.TrendingTag
+		if err := rows.Scan(&t.Name, &t.Score, &t.Authors); err != nil {
+			return nil, err
+		}
+		tags = append(tags, t)
+	}
+
+	return tags, rows.Err()
+}
+
+func (r *postRepo) PendingTagBackfill(ctx context.Context, limit int) ([]domain.Post, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT p.id, p.content, p.created_at
+		FROM post_tag_backfill b
+		JOIN posts p ON p.id = b.post_id
+		ORDER BY b.post_id
+		LIMIT $1
+	`, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var posts []domain.Post
+	for rows.Next() {
+		var p domain.Post
+		if err := rows.Scan(&p.ID, &p.Content, &p.CreatedAt); err != nil {
+			return nil, err
+		}
+		posts = append(posts, p)
+	}
+
+	return posts, rows.Err()
+}
+
+func (r *postRepo) SetBackfilledTags(ctx context.Context, postID uuid.UUID, createdAt time.Time, tags []string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `
+		DELETE FROM post_tags pt
+		USING tags t
+		WHERE pt.post_id = $1 AND t.id = pt.tag_id AND t.name <> ALL(COALESCE($2::text[], '{}'))
+	`, postID, tags)
+	if err != nil {
+		return err
+	}
+
+	if err := addTags(ctx, tx, postID, createdAt, tags); err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM post_tag_backfill WHERE post_id = $1`, postID)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
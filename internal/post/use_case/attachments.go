@@ -0,0 +1,151 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"mime/multipart"
+
+	mediaDomain "github.com/Ramsi97/edu-social-backend/internal/media/domain"
+	"github.com/Ramsi97/edu-social-backend/internal/post/domain"
+	sharedInterfaces "github.com/Ramsi97/edu-social-backend/internal/shared/interfaces"
+	"github.com/Ramsi97/edu-social-backend/pkg/media"
+	"github.com/google/uuid"
+)
+
+// mediaTypes maps the accepted content types to the kind of attachment.
+// WebP is left out: it can't be re-encoded to strip its metadata.
+var mediaTypes = map[string]domain.MediaType{
+	"image/jpeg":         domain.MediaImage,
+	"image/png":          domain.MediaImage,
+	"image/gif":          domain.MediaImage,
+	"video/mp4":          domain.MediaVideo,
+	"video/quicktime":    domain.MediaVideo,
+	"video/webm":         domain.MediaVideo,
+	"application/pdf":    domain.MediaDocument,
+	"application/msword": domain.MediaDocument,
+	"application/vnd.openxmlformats-officedocument.wordprocessingml.document":   domain.MediaDocument,
+	"application/vnd.ms-powerpoint":                                             domain.MediaDocument,
+	"application/vnd.openxmlformats-officedocument.presentationml.presentation": domain.MediaDocument,
+}
+
+// uploadAttachments checks every file against the limits before uploading
+// any of them, so a rejected post leaves nothing behind in storage.
+func (u *postUseCase) uploadAttachments(ctx context.Context, files []*multipart.FileHeader) ([]domain.Attachment, error) {
+	attachments := make([]domain.Attachment, len(files))
+	for i, file := range files {
+		mediaType, ok := classify(file)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", domain.ErrUnsupportedMedia, file.Filename)
+		}
+
+		if limit := u.cfg.MaxBytes[mediaType]; limit > 0 && file.Size > limit {
+			return nil, fmt.Errorf("%w: %s exceeds %d MB", domain.ErrAttachmentTooLarge, file.Filename, limit>>20)
+		}
+
+		attachments[i] = domain.Attachment{
+			Type:      mediaType,
+			SizeBytes: file.Size,
+			FileName:  file.Filename,
+		}
+	}
+
+	for i, file := range files {
+		probe(file, &attachments[i])
+
+		url, err := u.media.Upload(ctx, file, sharedInterfaces.PurposePost)
+		if err != nil {
+			for _, uploaded := range attachments[:i] {
+				u.deleteMedia(ctx, uploaded.URL)
+			}
+			return nil, err
+		}
+		attachments[i].URL = url
+	}
+
+	return attachments, nil
+}
+
+// claimAttachments takes the author's finalized uploads, holding them to
+// the same size limits as files sent with the post. It also returns the
+// claimed media, for releasing should the post not be saved.
+func (u *postUseCase) claimAttachments(
+	ctx context.Context,
+	authorID uuid.UUID,
+	mediaIDs []uuid.UUID,
+) ([]domain.Attachment, []mediaDomain.Media, error) {
+	claimed, err := u.uploads.Claim(ctx, authorID, sharedInterfaces.PurposePost, mediaIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	attachments := make([]domain.Attachment, len(claimed))
+	for i, m := range claimed {
+		mediaType := domain.MediaType(m.Kind)
+		if limit := u.cfg.MaxBytes[mediaType]; limit > 0 && m.SizeBytes > limit {
+			u.uploads.Release(ctx, authorID, claimed)
+			return nil, nil, fmt.Errorf("%w: %s exceeds %d MB", domain.ErrAttachmentTooLarge, m.FileName, limit>>20)
+		}
+
+		attachments[i] = domain.Attachment{
+			Type:            mediaType,
+			URL:             m.URL,
+			SizeBytes:       m.SizeBytes,
+			Width:           m.Width,
+			Height:          m.Height,
+			DurationSeconds: m.DurationSeconds,
+			FileName:        m.FileName,
+		}
+	}
+	return attachments, claimed, nil
+}
+
+// classify goes by the file's content, not the type the client sent.
+func classify(file *multipart.FileHeader) (domain.MediaType, bool) {
+	f, err := file.Open()
+	if err != nil {
+		return "", false
+	}
+	defer f.Close()
+
+	contentType, err := media.Sniff(f)
+	if err != nil {
+		return "", false
+	}
+
+	mediaType, ok := mediaTypes[contentType]
+	return mediaType, ok
+}
+
+// probe fills in the dimensions and duration where the file allows it.
+// Metadata is best effort: a file that can't be read is still attached.
+func probe(file *multipart.FileHeader, a *domain.Attachment) {
+	if a.Type == domain.MediaDocument {
+		return
+	}
+
+	f, err := file.Open()
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	var info media.Info
+	if a.Type == domain.MediaImage {
+		info, err = media.ProbeImage(f)
+	} else {
+		info, err = media.ProbeVideo(f)
+	}
+	if err != nil {
+		log.Printf("could not read metadata of %s: %v", file.Filename, err)
+		return
+	}
+
+	a.Width, a.Height, a.DurationSeconds = info.Width, info.Height, info.Duration
+}
+
+func (u *postUseCase) deleteMedia(ctx context.Context, url string) {
+	if err := u.media.Delete(ctx, url); err != nil {
+		log.Printf("failed to delete media %s: %v", url, err)
+	}
+}
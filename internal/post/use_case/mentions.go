@@ -0,0 +1,34 @@
+package usecase
+
+import (
+	"context"
+	"log"
+
+	mentionDomain "github.com/Ramsi97/edu-social-backend/internal/mention/domain"
+	notificationDomain "github.com/Ramsi97/edu-social-backend/internal/notification/domain"
+	"github.com/Ramsi97/edu-social-backend/internal/post/domain"
+	"github.com/google/uuid"
+)
+
+// recordMentions links the post's @handles once its content is saved. The
+// post stands either way, so a failure is only logged and leaves it
+// without mentions.
+func (u *postUseCase) recordMentions(ctx context.Context, post *domain.Post) {
+	mentions, err := u.mentions.Record(ctx, mentionDomain.Source{
+		Source: notificationDomain.Source{
+			Type:     notificationDomain.SourcePost,
+			ID:       post.ID,
+			ParentID: post.ID,
+		},
+		AuthorID: post.Author.ID,
+		Text:     post.Content,
+		CanRead: func(ctx context.Context, userID uuid.UUID) (bool, error) {
+			return u.repo.CanViewAuthor(ctx, userID, post.Author.ID)
+		},
+	})
+	if err != nil {
+		log.Printf("failed to record mentions in post %s: %v", post.ID, err)
+		mentions = mentionDomain.Mentions{}
+	}
+	post.Mentions = mentions
+}
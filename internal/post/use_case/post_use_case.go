@@ -2,26 +2,59 @@ package usecase
 
 import (
 	"context"
-	"errors"
+	"fmt"
+	"log"
+	"mime/multipart"
+	"strings"
 	"time"
 
+	authDomain "github.com/Ramsi97/edu-social-backend/internal/auth/domain"
+	commentDomain "github.com/Ramsi97/edu-social-backend/internal/comment/domain"
+	mediaDomain "github.com/Ramsi97/edu-social-backend/internal/media/domain"
+	mentionDomain "github.com/Ramsi97/edu-social-backend/internal/mention/domain"
+	notificationDomain "github.com/Ramsi97/edu-social-backend/internal/notification/domain"
 	"github.com/Ramsi97/edu-social-backend/internal/post/domain"
 	"github.com/Ramsi97/edu-social-backend/internal/post/repository/interfaces"
+	sharedInterfaces "github.com/Ramsi97/edu-social-backend/internal/shared/interfaces"
 	"github.com/google/uuid"
 )
 
+// detailCommentsPage is how many comments a single post comes with.
+const detailCommentsPage = 20
+
 type postUseCase struct {
-	repo interfaces.PostRepository
+	repo     interfaces.PostRepository
+	comments commentDomain.CommentUseCase
+	media    sharedInterfaces.MediaStorage
+	uploads  mediaDomain.MediaUseCase
+	cfg      domain.AttachmentConfig
+	tags     domain.TagConfig
+	mentions mentionDomain.MentionUseCase
 }
 
-func NewPostUseCase(r interfaces.PostRepository) domain.PostUseCase {
+func NewPostUseCase(
+	r interfaces.PostRepository,
+	comments commentDomain.CommentUseCase,
+	media sharedInterfaces.MediaStorage,
+	uploads mediaDomain.MediaUseCase,
+	cfg domain.AttachmentConfig,
+	tags domain.TagConfig,
+	mentions mentionDomain.MentionUseCase,
+) domain.PostUseCase {
 	return &postUseCase{
-		repo: r,
+		repo:     r,
+		comments: comments,
+		media:    media,
+		uploads:  uploads,
+		cfg:      cfg,
+		tags:     tags,
+		mentions: mentions,
 	}
 }
 
 func (u *postUseCase) GetFeed(
 	ctx context.Context,
+	mode domain.FeedMode,
 	limit int,
 	lastSeenTime *time.Time,
 	authorID uuid.UUID,
@@ -31,13 +64,191 @@ func (u *postUseCase) GetFeed(
 		limit = 20
 	}
 
-	return u.repo.GetFeed(ctx, limit, lastSeenTime, authorID)
+	switch mode {
+	case "":
+		mode = domain.FeedGlobal
+	case domain.FeedGlobal, domain.FeedFollowing:
+	default:
+		return nil, domain.ErrInvalidFeed
+	}
+
+	return u.repo.GetFeed(ctx, mode, limit, lastSeenTime, authorID)
+}
+
+func (u *postUseCase) GetUserPosts(
+	ctx context.Context,
+	authorID uuid.UUID,
+	limit int,
+	lastSeenTime *time.Time,
+	viewerID uuid.UUID,
+) ([]domain.Post, error) {
+
+	if limit <= 0 {
+		limit = 20
+	}
+
+	visible, err := u.repo.CanViewAuthor(ctx, viewerID, authorID)
+	if err != nil {
+		return nil, err
+	}
+	if !visible {
+		return nil, domain.ErrPrivateAuthor
+	}
+
+	return u.repo.GetUserPosts(ctx, authorID, limit, lastSeenTime, viewerID)
 }
 
-func (u *postUseCase) CreatePost(ctx context.Context, post *domain.Post) error {
-	if post.Content == "" && post.MediaUrl == "" {
-		return errors.New("post cannot be empty")
+func (u *postUseCase) CreatePost(ctx context.Context, post *domain.Post, files []*multipart.FileHeader, mediaIDs []uuid.UUID) error {
+	if post.Content == "" && len(files) == 0 && len(mediaIDs) == 0 {
+		return domain.ErrEmptyPost
+	}
+	if len(files)+len(mediaIDs) > u.cfg.MaxAttachments {
+		return fmt.Errorf("%w: at most %d per post", domain.ErrTooManyAttachments, u.cfg.MaxAttachments)
+	}
+
+	claimed, claimedMedia, err := u.claimAttachments(ctx, post.Author.ID, mediaIDs)
+	if err != nil {
+		return err
+	}
+
+	uploaded, err := u.uploadAttachments(ctx, files)
+	if err != nil {
+		u.uploads.Release(ctx, post.Author.ID, claimedMedia)
+		return err
+	}
+
+	post.Attachments = append(uploaded, claimed...)
+	if len(post.Attachments) > 0 {
+		post.MediaUrl = post.Attachments[0].URL
+	}
+	post.Tags = extractTags(post.Content)
+
+	if err := u.repo.CreatePost(ctx, post); err != nil {
+		for _, a := range uploaded {
+			u.deleteMedia(ctx, a.URL)
+		}
+		u.uploads.Release(ctx, post.Author.ID, claimedMedia)
+		return err
+	}
+
+	u.recordMentions(ctx, post)
+	return nil
+}
+
+func (u *postUseCase) GetPost(ctx context.Context, postID, viewerID uuid.UUID) (*domain.PostDetail, error) {
+	post, err := u.repo.GetPost(ctx, postID, viewerID)
+	if err != nil {
+		return nil, err
+	}
+
+	comments, err := u.comments.GetByPostID(
+		ctx,
+		viewerID.String(),
+		postID.String(),
+		commentDomain.ListQuery{Limit: detailCommentsPage},
+	)
+	if err != nil {
+		return nil, err
+	}
+	if comments == nil {
+		comments = []commentDomain.Comment{}
+	}
+
+	detail := &domain.PostDetail{Post: *post, Comments: comments}
+	if len(comments) == detailCommentsPage {
+		last := comments[len(comments)-1]
+		detail.NextCommentsCursor = &last.CreatedAT
+		detail.NextCommentsCursorID = &last.ID
+	}
+
+	return detail, nil
+}
+
+func (u *postUseCase) UpdatePost(
+	ctx context.Context,
+	postID, actorID uuid.UUID,
+	role authDomain.Role,
+	content string,
+) (*domain.Post, error) {
+	post, err := u.ownPost(ctx, postID, actorID, role)
+	if err != nil {
+		return nil, err
+	}
+
+	content = strings.TrimSpace(content)
+	if content == "" && post.MediaUrl == "" {
+		return nil, domain.ErrEmptyPost
+	}
+
+	if content == post.Content {
+		return post, nil
+	}
+
+	tags := extractTags(content)
+	editedAt, err := u.repo.UpdateContent(ctx, postID, actorID, content, tags)
+	if err != nil {
+		return nil, err
+	}
+
+	post.Content = content
+	post.Tags = tags
+	post.Edited = true
+	post.EditedAt = &editedAt
+	u.recordMentions(ctx, post)
+	return post, nil
+}
+
+func (u *postUseCase) DeletePost(ctx context.Context, postID, actorID uuid.UUID, role authDomain.Role) error {
+	if _, err := u.ownPost(ctx, postID, actorID, role); err != nil {
+		return err
+	}
+
+	mediaURLs, err := u.repo.SoftDelete(ctx, postID, actorID)
+	if err != nil {
+		return err
+	}
+
+	// The post is already gone, so a failed cleanup only leaves an
+	// orphaned file behind.
+	for _, url := range mediaURLs {
+		u.deleteMedia(ctx, url)
+	}
+	if err := u.mentions.Remove(ctx, notificationDomain.SourcePost, postID); err != nil {
+		log.Printf("failed to remove mentions in post %s: %v", postID, err)
+	}
+
+	return nil
+}
+
+func (u *postUseCase) GetRevisions(ctx context.Context, postID, viewerID uuid.UUID) ([]domain.PostRevision, error) {
+	post, err := u.repo.GetByID(ctx, postID)
+	if err != nil {
+		return nil, err
+	}
+
+	visible, err := u.repo.CanViewAuthor(ctx, viewerID, post.Author.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !visible {
+		// Don't reveal that a hidden post exists.
+		return nil, domain.ErrPostNotFound
+	}
+
+	return u.repo.GetRevisions(ctx, postID)
+}
+
+// ownPost loads a post the actor is allowed to change: their own, or any
+// post for moderators and admins.
+func (u *postUseCase) ownPost(ctx context.Context, postID, actorID uuid.UUID, role authDomain.Role) (*domain.Post, error) {
+	post, err := u.repo.GetByID(ctx, postID)
+	if err != nil {
+		return nil, err
+	}
+
+	if post.Author.ID != actorID && role != authDomain.RoleModerator && role != authDomain.RoleAdmin {
+		return nil, domain.ErrNotPostAuthor
 	}
 
-	return u.repo.CreatePost(ctx, post)
+	return post, nil
 }
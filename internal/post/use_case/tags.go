@@ -0,0 +1,88 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/Ramsi97/edu-social-backend/internal/post/domain"
+	"github.com/Ramsi97/edu-social-backend/pkg/hashtag"
+	"github.com/google/uuid"
+)
+
+const (
+	defaultTagSuggestions = 10
+	maxTagSuggestions     = 20
+	defaultTrendingTags   = 10
+	maxTrendingTags       = 50
+	tagBackfillBatch      = 500
+)
+
+func (u *postUseCase) GetTagPosts(
+	ctx context.Context,
+	tag string,
+	limit int,
+	lastSeenTime *time.Time,
+	viewerID uuid.UUID,
+) ([]domain.Post, error) {
+	name, ok := hashtag.Normalize(tag)
+	if !ok {
+		return nil, domain.ErrInvalidTag
+	}
+
+	if limit <= 0 {
+		limit = 20
+	}
+
+	return u.repo.GetTagPosts(ctx, name, limit, lastSeenTime, viewerID)
+}
+
+func (u *postUseCase) SearchTags(ctx context.Context, prefix string, limit int, viewerID uuid.UUID) ([]domain.Tag, error) {
+	prefix, ok := hashtag.Prefix(prefix)
+	if !ok {
+		return nil, domain.ErrInvalidTag
+	}
+
+	return u.repo.SearchTags(ctx, prefix, clampLimit(limit, defaultTagSuggestions, maxTagSuggestions), viewerID)
+}
+
+func (u *postUseCase) TrendingTags(ctx context.Context, limit int) ([]domain.TrendingTag, error) {
+	return u.repo.TrendingTags(ctx, time.Now(), u.tags, clampLimit(limit, defaultTrendingTags, maxTrendingTags))
+}
+
+func (u *postUseCase) BackfillTags(ctx context.Context) error {
+	for {
+		posts, err := u.repo.PendingTagBackfill(ctx, tagBackfillBatch)
+		if err != nil {
+			return err
+		}
+
+		for _, p := range posts {
+			if err := u.repo.SetBackfilledTags(ctx, p.ID, p.CreatedAt, extractTags(p.Content)); err != nil {
+				return err
+			}
+		}
+
+		if len(posts) < tagBackfillBatch {
+			return nil
+		}
+	}
+}
+
+// extractTags never returns nil, so a post without tags lists them as [].
+func extractTags(content string) []string {
+	tags := hashtag.Extract(content)
+	if tags == nil {
+		return []string{}
+	}
+	return tags
+}
+
+func clampLimit(limit, def, maxLimit int) int {
+	if limit <= 0 {
+		return def
+	}
+	if limit > maxLimit {
+		return maxLimit
+	}
+	return limit
+}
@@ -0,0 +1,72 @@
+package http
+
+import (
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Ramsi97/edu-social-backend/internal/shared/infrastructure"
+	"github.com/Ramsi97/edu-social-backend/internal/shared/interfaces"
+	"github.com/Ramsi97/edu-social-backend/pkg/media"
+	"github.com/Ramsi97/edu-social-backend/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+type mediaHandler struct {
+	dir    string
+	secret []byte
+}
+
+// NewMediaHandler serves files kept by the local storage backend from the
+// path of its BaseURL.
+func NewMediaHandler(router gin.IRouter, cfg infrastructure.LocalStorageConfig) error {
+	base, err := url.Parse(cfg.BaseURL)
+	if err != nil {
+		return err
+	}
+
+	handler := &mediaHandler{dir: cfg.Dir, secret: cfg.Secret}
+	prefix := strings.TrimSuffix(base.Path, "/")
+	router.GET(prefix+"/*key", handler.Serve)
+	router.HEAD(prefix+"/*key", handler.Serve)
+	return nil
+}
+
+// Serve sends a stored file. Most uploads are public, as posts and profiles
+// link to them directly, but files of a private purpose need a signed link.
+// Any link that carries a signature must still be valid.
+func (h *mediaHandler) Serve(ctx *gin.Context) {
+	key := strings.TrimPrefix(ctx.Param("key"), "/")
+	if !fs.ValidPath(key) || key == "." {
+		response.Error(ctx, http.StatusNotFound, "File not found", "")
+		return
+	}
+
+	// Keys start with the folder of the purpose they were uploaded for.
+	purpose, _, _ := strings.Cut(key, "/")
+	signature := ctx.Query("signature")
+	if (signature != "" || interfaces.MediaPurpose(purpose).Private()) &&
+		!media.Verify(h.secret, key, ctx.Query("expires"), signature) {
+		response.Error(ctx, http.StatusForbidden, "Link expired or invalid", "")
+		return
+	}
+
+	full := filepath.Join(h.dir, filepath.FromSlash(key))
+	info, err := os.Stat(full)
+	if err != nil || info.IsDir() {
+		response.Error(ctx, http.StatusNotFound, "File not found", "")
+		return
+	}
+
+	// Names are random and never reused, so the content never changes.
+	if signature != "" {
+		ctx.Header("Cache-Control", "private, max-age=3600")
+	} else {
+		ctx.Header("Cache-Control", "public, max-age=31536000, immutable")
+	}
+	ctx.Header("X-Content-Type-Options", "nosniff")
+	ctx.File(full)
+}
@@ -2,10 +2,18 @@ package infrastructure
 
 import (
 	"context"
-	"mime/multipart"
+	"errors"
+	"fmt"
+	"mime"
+	"path"
+	"regexp"
+	"strings"
+	"time"
 
 	"github.com/Ramsi97/edu-social-backend/internal/shared/interfaces"
 	"github.com/cloudinary/cloudinary-go/v2"
+	"github.com/cloudinary/cloudinary-go/v2/api"
+	"github.com/cloudinary/cloudinary-go/v2/api/admin"
 	"github.com/cloudinary/cloudinary-go/v2/api/uploader"
 )
 
@@ -13,23 +21,143 @@ type cloudinaryUploader struct {
 	cld *cloudinary.Cloudinary
 }
 
-func NewCloudinaryUploader(cld *cloudinary.Cloudinary) interfaces.MediaStorage {
+func NewCloudinaryUploader(cld *cloudinary.Cloudinary) interfaces.MediaBackend {
 	return &cloudinaryUploader{cld: cld}
 }
 
-func (u *cloudinaryUploader) UploadToCloudinary(ctx context.Context, file *multipart.FileHeader) (string, error) {
-	f, err := file.Open()
+func (u *cloudinaryUploader) Put(ctx context.Context, file interfaces.MediaFile) (string, error) {
+	// "auto" lets Cloudinary accept videos and documents as well as images.
+	params := uploader.UploadParams{
+		Folder:       "edu_social/" + string(file.Purpose),
+		ResourceType: "auto",
+	}
+	if file.Purpose.Private() {
+		// Authenticated assets can only be fetched through signed URLs.
+		params.Type = api.Authenticated
+	}
+
+	uploadResult, err := u.cld.Upload.Upload(ctx, file.Body, params)
 	if err != nil {
 		return "", err
 	}
-	defer f.Close()
 
-	uploadResult, err := u.cld.Upload.Upload(ctx, f, uploader.UploadParams{
-		Folder: "edu_social/profile_pics",
+	return uploadResult.SecureURL, nil
+}
+
+func (u *cloudinaryUploader) Delete(ctx context.Context, url string) error {
+	asset, err := cloudinaryPublicID(url)
+	if err != nil {
+		return err
+	}
+
+	_, err = u.cld.Upload.Destroy(ctx, uploader.DestroyParams{
+		PublicID:     asset.publicID,
+		Type:         asset.deliveryType,
+		ResourceType: asset.resourceType,
+		Invalidate:   api.Bool(true),
 	})
+	return err
+}
+
+func (u *cloudinaryUploader) SignedURL(ctx context.Context, url string, ttl time.Duration) (string, error) {
+	asset, err := cloudinaryPublicID(url)
 	if err != nil {
 		return "", err
 	}
 
-	return uploadResult.SecureURL, nil
-}
\ No newline at end of file
+	// Raw public IDs already end in their extension.
+	format := strings.TrimPrefix(path.Ext(url), ".")
+	if asset.resourceType == "raw" {
+		format = ""
+	}
+
+	expiresAt := time.Now().Add(ttl)
+	return u.cld.Upload.PrivateDownloadURL(uploader.PrivateDownloadURLParams{
+		PublicID:     asset.publicID,
+		Format:       format,
+		DeliveryType: asset.deliveryType,
+		ExpiresAt:    &expiresAt,
+		ResourceType: api.AssetType(asset.resourceType),
+	})
+}
+
+func (u *cloudinaryUploader) Metadata(ctx context.Context, url string) (*interfaces.MediaObject, error) {
+	ref, err := cloudinaryPublicID(url)
+	if err != nil {
+		return nil, err
+	}
+
+	asset, err := u.cld.Admin.Asset(ctx, admin.AssetParams{
+		AssetType:    api.AssetType(ref.resourceType),
+		DeliveryType: api.DeliveryType(ref.deliveryType),
+		PublicID:     ref.publicID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if asset.Error.Message != "" {
+		if strings.Contains(asset.Error.Message, "not found") {
+			return nil, interfaces.ErrMediaNotFound
+		}
+		return nil, errors.New(asset.Error.Message)
+	}
+
+	contentType := mime.TypeByExtension("." + asset.Format)
+	if contentType == "" {
+		contentType = asset.ResourceType + "/" + asset.Format
+	}
+	return &interfaces.MediaObject{
+		URL:         asset.SecureURL,
+		ContentType: contentType,
+		Size:        int64(asset.Bytes),
+		UpdatedAt:   asset.CreatedAt,
+	}, nil
+}
+
+var (
+	cloudinaryVersion   = regexp.MustCompile(`^v[0-9]+$`)
+	cloudinarySignature = regexp.MustCompile(`^s--[A-Za-z0-9_-]+--$`)
+)
+
+// cloudinaryAsset identifies a stored file to the Cloudinary APIs.
+type cloudinaryAsset struct {
+	resourceType string
+	deliveryType string
+	publicID     string
+}
+
+// cloudinaryPublicID recovers the resource type, delivery type and public ID
+// from a delivery URL such as
+// https://res.cloudinary.com/<cloud>/image/upload/v123/edu_social/x/abc.jpg
+// Private files are delivered under /authenticated/ instead of /upload/.
+func cloudinaryPublicID(url string) (cloudinaryAsset, error) {
+	var asset cloudinaryAsset
+
+	var prefix, rest string
+	var ok bool
+	for _, deliveryType := range []string{string(api.Upload), api.Authenticated} {
+		if prefix, rest, ok = strings.Cut(url, "/"+deliveryType+"/"); ok {
+			asset.deliveryType = deliveryType
+			break
+		}
+	}
+	if !ok {
+		return asset, fmt.Errorf("not a cloudinary upload url: %s", url)
+	}
+	asset.resourceType = path.Base(prefix)
+
+	segments := strings.Split(rest, "/")
+	if len(segments) > 1 && cloudinarySignature.MatchString(segments[0]) {
+		segments = segments[1:]
+	}
+	if len(segments) > 1 && cloudinaryVersion.MatchString(segments[0]) {
+		segments = segments[1:]
+	}
+
+	asset.publicID = strings.Join(segments, "/")
+	if asset.resourceType != "raw" {
+		// Raw assets (documents) keep their extension in the public ID.
+		asset.publicID = strings.TrimSuffix(asset.publicID, path.Ext(asset.publicID))
+	}
+	return asset, nil
+}
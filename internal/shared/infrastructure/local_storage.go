@@ -0,0 +1,133 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Ramsi97/edu-social-backend/internal/shared/interfaces"
+	"github.com/Ramsi97/edu-social-backend/pkg/media"
+	"github.com/google/uuid"
+)
+
+// LocalStorageConfig places uploads on disk, served back by the API itself.
+type LocalStorageConfig struct {
+	Dir string
+	// BaseURL is where the media route is mounted, such as
+	// http://localhost:8080/media.
+	BaseURL string
+	// Secret signs time-limited links.
+	Secret []byte
+}
+
+type localStorage struct {
+	cfg LocalStorageConfig
+}
+
+func NewLocalStorage(cfg LocalStorageConfig) interfaces.MediaBackend {
+	cfg.BaseURL = strings.TrimSuffix(cfg.BaseURL, "/")
+	return &localStorage{cfg: cfg}
+}
+
+func (s *localStorage) Put(ctx context.Context, file interfaces.MediaFile) (string, error) {
+	key := objectKey(file)
+	full := filepath.Join(s.cfg.Dir, filepath.FromSlash(key))
+	if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+		return "", err
+	}
+
+	f, err := os.OpenFile(full, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return "", err
+	}
+	if _, err := io.Copy(f, file.Body); err != nil {
+		f.Close()
+		os.Remove(full)
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(full)
+		return "", err
+	}
+
+	return s.cfg.BaseURL + "/" + key, nil
+}
+
+func (s *localStorage) Delete(ctx context.Context, url string) error {
+	key, err := s.key(url)
+	if err != nil {
+		return err
+	}
+
+	err = os.Remove(filepath.Join(s.cfg.Dir, filepath.FromSlash(key)))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+func (s *localStorage) SignedURL(ctx context.Context, rawURL string, ttl time.Duration) (string, error) {
+	key, err := s.key(rawURL)
+	if err != nil {
+		return "", err
+	}
+
+	expires := time.Now().Add(ttl)
+	query := url.Values{
+		"expires":   {strconv.FormatInt(expires.Unix(), 10)},
+		"signature": {media.Sign(s.cfg.Secret, key, expires)},
+	}
+	return s.cfg.BaseURL + "/" + key + "?" + query.Encode(), nil
+}
+
+func (s *localStorage) Metadata(ctx context.Context, url string) (*interfaces.MediaObject, error) {
+	key, err := s.key(url)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := os.Stat(filepath.Join(s.cfg.Dir, filepath.FromSlash(key)))
+	if os.IsNotExist(err) {
+		return nil, interfaces.ErrMediaNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	contentType := mime.TypeByExtension(path.Ext(key))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	return &interfaces.MediaObject{
+		URL:         s.cfg.BaseURL + "/" + key,
+		ContentType: contentType,
+		Size:        info.Size(),
+		UpdatedAt:   info.ModTime(),
+	}, nil
+}
+
+// key turns a URL from Put back into the file's path under Dir, refusing
+// anything that would step outside it.
+func (s *localStorage) key(rawURL string) (string, error) {
+	rawURL, _, _ = strings.Cut(rawURL, "?")
+	key, ok := strings.CutPrefix(rawURL, s.cfg.BaseURL+"/")
+	if !ok || !fs.ValidPath(key) || key == "." {
+		return "", fmt.Errorf("not a local media url: %s", rawURL)
+	}
+	return key, nil
+}
+
+// objectKey names a new file: its purpose folder, a random name and the
+// extension of its real content type.
+func objectKey(file interfaces.MediaFile) string {
+	return path.Join(string(file.Purpose), uuid.NewString()+media.Extension(file.ContentType))
+}
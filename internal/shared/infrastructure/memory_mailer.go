@@ -0,0 +1,57 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/Ramsi97/edu-social-backend/internal/shared/interfaces"
+)
+
+// MemoryMailer keeps every sent mail in memory instead of delivering it. When
+// a directory is given each mail is also written there as a text file, which
+// is handy for clicking verification links during local development.
+type MemoryMailer struct {
+	dir string
+
+	mu   sync.Mutex
+	sent []interfaces.Mail
+}
+
+func NewMemoryMailer(dir string) *MemoryMailer {
+	return &MemoryMailer{dir: dir}
+}
+
+func (m *MemoryMailer) Send(ctx context.Context, mail interfaces.Mail) error {
+	m.mu.Lock()
+	m.sent = append(m.sent, mail)
+	m.mu.Unlock()
+
+	if m.dir == "" {
+		log.Printf("mail to %s: %s", mail.To, mail.Subject)
+		return nil
+	}
+
+	if err := os.MkdirAll(m.dir, 0o755); err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("%d-%s.txt", time.Now().UnixNano(), filepath.Base(mail.To))
+	content := fmt.Sprintf("To: %s\nSubject: %s\n\n%s\n", mail.To, mail.Subject, mail.Body)
+
+	return os.WriteFile(filepath.Join(m.dir, name), []byte(content), 0o644)
+}
+
+// Sent returns a copy of every mail sent so far.
+func (m *MemoryMailer) Sent() []interfaces.Mail {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	sent := make([]interfaces.Mail, len(m.sent))
+	copy(sent, m.sent)
+	return sent
+}
@@ -0,0 +1,58 @@
+// Package postgres holds SQL fragments the repositories of several
+// features select with, so none has to import another feature's.
+package postgres
+
+// VariantsColumn selects the variants of the image URL in column as a JSON
+// object, or NULL while there are none. It scans into a media Variants.
+func VariantsColumn(column string) string {
+	return `(
+		SELECT json_build_object(
+			'thumbnail_url', mv.thumbnail_url,
+			'medium_url', mv.medium_url,
+			'blurhash', mv.blurhash,
+			'dominant_color', mv.dominant_color
+		)
+		FROM media_variants mv
+		WHERE mv.url = ` + column + `
+	)`
+}
+
+// MediaColumn selects the media whose ID is in column as a JSON object, or
+// NULL when there is none. It scans into a media Media.
+func MediaColumn(column string) string {
+	return `(
+		SELECT json_build_object(
+			'id', md.id,
+			'type', md.type,
+			'url', md.url,
+			'content_type', md.content_type,
+			'size_bytes', md.size_bytes,
+			'width', md.width,
+			'height', md.height,
+			'duration_seconds', md.duration_seconds,
+			'file_name', md.file_name,
+			'variants', ` + VariantsColumn("md.url") + `,
+			'created_at', md.created_at
+		)
+		FROM media md
+		WHERE md.id = ` + column + `
+	)`
+}
+
+// MentionsColumn selects the mentions of the source of sourceType whose ID
+// is in column as a JSON array in the order they appear. It scans into
+// mention Mentions.
+func MentionsColumn(sourceType, column string) string {
+	return `
+		COALESCE((
+			SELECT json_agg(json_build_object(
+				'user_id', mn.user_id,
+				'username', mu.username,
+				'offset', mn.start_index,
+				'length', mn.length
+			) ORDER BY mn.start_index)
+			FROM mentions mn
+			JOIN users mu ON mu.id = mn.user_id
+			WHERE mn.source_type = '` + sourceType + `' AND mn.source_id = ` + column + `
+		), '[]')`
+}
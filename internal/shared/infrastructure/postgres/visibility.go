@@ -0,0 +1,36 @@
+package postgres
+
+// NotBlocked holds when the user row user and the viewer are on neither
+// side of a block. viewer is the SQL that gives the viewer's ID, usually a
+// parameter such as "$2".
+func NotBlocked(user, viewer string) string {
+	return `
+	NOT EXISTS (
+		SELECT 1 FROM user_blocks b
+		WHERE (b.blocker_id = ` + viewer + ` AND b.blocked_id = ` + user + `.id)
+			OR (b.blocker_id = ` + user + `.id AND b.blocked_id = ` + viewer + `)
+	)
+`
+}
+
+// VisibleTo holds when the viewer may see what the user row user has
+// posted: the user isn't suspended, neither has blocked the other, and the
+// user's privacy (a private account or a profile_visibility setting)
+// doesn't leave the viewer out.
+func VisibleTo(user, viewer string) string {
+	return `
+	` + user + `.suspended_at IS NULL
+	AND` + NotBlocked(user, viewer) + `
+	AND (
+		` + user + `.id = ` + viewer + `
+		OR (NOT ` + user + `.is_private AND ` + user + `.profile_visibility = 'everyone')
+		OR (
+			` + user + `.profile_visibility <> 'only_me'
+			AND EXISTS (
+				SELECT 1 FROM follows vf
+				WHERE vf.follower_id = ` + viewer + ` AND vf.followee_id = ` + user + `.id AND vf.status = 'accepted'
+			)
+		)
+	)
+`
+}
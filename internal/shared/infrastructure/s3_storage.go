@@ -0,0 +1,134 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Ramsi97/edu-social-backend/internal/shared/interfaces"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// S3Config points at a bucket on AWS S3 or any compatible server such as
+// MinIO.
+type S3Config struct {
+	Endpoint  string // host[:port], without scheme
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+	UseSSL    bool
+	// PublicURL is where objects are read from. It defaults to the bucket
+	// under the endpoint, which needs the bucket to allow public reads.
+	// Private files are kept under privatePrefix, which the bucket policy
+	// must leave out of any public read grant.
+	PublicURL string
+}
+
+// privatePrefix is the folder private files are stored under, so a bucket
+// policy can open everything else to anonymous reads.
+const privatePrefix = "private/"
+
+type s3Storage struct {
+	client    *minio.Client
+	bucket    string
+	publicURL string
+}
+
+func NewS3Storage(cfg S3Config) (interfaces.MediaBackend, error) {
+	client, err := minio.New(cfg.Endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(cfg.AccessKey, cfg.SecretKey, ""),
+		Secure: cfg.UseSSL,
+		Region: cfg.Region,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	publicURL := cfg.PublicURL
+	if publicURL == "" {
+		publicURL = client.EndpointURL().String() + "/" + cfg.Bucket
+	}
+
+	return &s3Storage{
+		client:    client,
+		bucket:    cfg.Bucket,
+		publicURL: strings.TrimSuffix(publicURL, "/"),
+	}, nil
+}
+
+func (s *s3Storage) Put(ctx context.Context, file interfaces.MediaFile) (string, error) {
+	key := objectKey(file)
+	opts := minio.PutObjectOptions{
+		ContentType:  file.ContentType,
+		CacheControl: "public, max-age=31536000, immutable",
+	}
+	if file.Purpose.Private() {
+		// Private files are only read through short-lived signed links, so
+		// they get a private ACL and must not sit in shared caches.
+		key = privatePrefix + key
+		opts.CacheControl = "private, no-store"
+		opts.UserMetadata = map[string]string{"x-amz-acl": "private"}
+	}
+
+	_, err := s.client.PutObject(ctx, s.bucket, key, file.Body, file.Size, opts)
+	if err != nil {
+		return "", err
+	}
+
+	return s.publicURL + "/" + key, nil
+}
+
+func (s *s3Storage) Delete(ctx context.Context, url string) error {
+	key, err := s.key(url)
+	if err != nil {
+		return err
+	}
+	return s.client.RemoveObject(ctx, s.bucket, key, minio.RemoveObjectOptions{})
+}
+
+func (s *s3Storage) SignedURL(ctx context.Context, url string, ttl time.Duration) (string, error) {
+	key, err := s.key(url)
+	if err != nil {
+		return "", err
+	}
+
+	signed, err := s.client.PresignedGetObject(ctx, s.bucket, key, ttl, nil)
+	if err != nil {
+		return "", err
+	}
+	return signed.String(), nil
+}
+
+func (s *s3Storage) Metadata(ctx context.Context, url string) (*interfaces.MediaObject, error) {
+	key, err := s.key(url)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := s.client.StatObject(ctx, s.bucket, key, minio.StatObjectOptions{})
+	if err != nil {
+		if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+			return nil, interfaces.ErrMediaNotFound
+		}
+		return nil, err
+	}
+
+	return &interfaces.MediaObject{
+		URL:         s.publicURL + "/" + key,
+		ContentType: info.ContentType,
+		Size:        info.Size,
+		UpdatedAt:   info.LastModified,
+	}, nil
+}
+
+func (s *s3Storage) key(rawURL string) (string, error) {
+	rawURL, _, _ = strings.Cut(rawURL, "?")
+	key, ok := strings.CutPrefix(rawURL, s.publicURL+"/")
+	if !ok || key == "" {
+		return "", fmt.Errorf("not a url in bucket %s: %s", s.bucket, rawURL)
+	}
+	return key, nil
+}
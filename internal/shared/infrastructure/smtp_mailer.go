@@ -0,0 +1,52 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strings"
+
+	"github.com/Ramsi97/edu-social-backend/internal/shared/interfaces"
+)
+
+type smtpMailer struct {
+	addr string
+	auth smtp.Auth
+	from string
+}
+
+func NewSMTPMailer(host, port, username, password, from string) interfaces.Mailer {
+	var auth smtp.Auth
+	if username != "" {
+		auth = smtp.PlainAuth("", username, password, host)
+	}
+
+	return &smtpMailer{
+		addr: net.JoinHostPort(host, port),
+		auth: auth,
+		from: from,
+	}
+}
+
+func (m *smtpMailer) Send(ctx context.Context, mail interfaces.Mail) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	// Refuse header injection through user-controlled addresses or subjects.
+	if strings.ContainsAny(mail.To+mail.Subject, "\r\n") {
+		return fmt.Errorf("invalid mail header")
+	}
+
+	var msg strings.Builder
+	fmt.Fprintf(&msg, "From: %s\r\n", m.from)
+	fmt.Fprintf(&msg, "To: %s\r\n", mail.To)
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mail.Subject)
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=\"utf-8\"\r\n")
+	msg.WriteString("\r\n")
+	msg.WriteString(mail.Body)
+
+	return smtp.SendMail(m.addr, m.auth, m.from, []string{mail.To}, []byte(msg.String()))
+}
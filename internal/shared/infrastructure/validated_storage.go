@@ -0,0 +1,150 @@
+package infrastructure
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"maps"
+	"mime/multipart"
+	"time"
+
+	"github.com/Ramsi97/edu-social-backend/internal/shared/interfaces"
+	"github.com/Ramsi97/edu-social-backend/pkg/media"
+)
+
+const mb = 1 << 20
+
+// DefaultMediaPolicies are the hard limits for each upload purpose. Feature
+// settings such as the post attachment limits can only tighten them.
+func DefaultMediaPolicies() map[interfaces.MediaPurpose]media.Policy {
+	images := map[string]int64{
+		"image/jpeg": 10 * mb,
+		"image/png":  10 * mb,
+		"image/gif":  10 * mb,
+	}
+
+	// Posts and messages carry lecture recordings and course documents too.
+	files := map[string]int64{
+		"video/mp4":          1024 * mb,
+		"video/quicktime":    1024 * mb,
+		"video/webm":         1024 * mb,
+		"application/pdf":    100 * mb,
+		"application/msword": 100 * mb,
+		"application/vnd.openxmlformats-officedocument.wordprocessingml.document":   100 * mb,
+		"application/vnd.ms-powerpoint":                                             100 * mb,
+		"application/vnd.openxmlformats-officedocument.presentationml.presentation": 100 * mb,
+	}
+	maps.Copy(files, images)
+
+	return map[interfaces.MediaPurpose]media.Policy{
+		interfaces.PurposeAvatar: {
+			MaxBytes: map[string]int64{
+				"image/jpeg": 5 * mb,
+				"image/png":  5 * mb,
+				"image/gif":  5 * mb,
+			},
+			MaxPixels: 25_000_000,
+		},
+		interfaces.PurposePost:    {MaxBytes: files, MaxPixels: 50_000_000},
+		interfaces.PurposeComment: {MaxBytes: images, MaxPixels: 50_000_000},
+		interfaces.PurposeMessage: {MaxBytes: files, MaxPixels: 50_000_000},
+	}
+}
+
+type validatedStorage struct {
+	backend  interfaces.MediaBackend
+	policies map[interfaces.MediaPurpose]media.Policy
+	images   interfaces.ImageProcessor
+}
+
+// NewValidatedStorage puts the upload policies in front of a backend, so
+// nothing reaches it without being sniffed, size checked and, for photos,
+// stripped of metadata. Stored images are handed on to images for their
+// variants.
+func NewValidatedStorage(
+	backend interfaces.MediaBackend,
+	policies map[interfaces.MediaPurpose]media.Policy,
+	images interfaces.ImageProcessor,
+) interfaces.MediaStorage {
+	return &validatedStorage{backend: backend, policies: policies, images: images}
+}
+
+func (s *validatedStorage) Upload(ctx context.Context, file *multipart.FileHeader, purpose interfaces.MediaPurpose) (string, error) {
+	f, err := file.Open()
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	return s.UploadReader(ctx, f, file.Filename, file.Size, purpose)
+}
+
+func (s *validatedStorage) UploadReader(ctx context.Context, f io.ReadSeeker, name string, size int64, purpose interfaces.MediaPurpose) (string, error) {
+	policy, ok := s.policies[purpose]
+	if !ok {
+		return "", fmt.Errorf("no media policy for %q", purpose)
+	}
+
+	contentType, err := media.Sniff(f)
+	if err != nil {
+		return "", err
+	}
+	limit, ok := policy.MaxBytes[contentType]
+	if !ok {
+		return "", fmt.Errorf("%w: %s is %s", media.ErrUnsupportedType, name, contentType)
+	}
+	if size > limit {
+		return "", fmt.Errorf("%w: %s exceeds %d MB", media.ErrFileTooLarge, name, limit/mb)
+	}
+
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+
+	var body io.Reader = f
+	var clean []byte
+	if media.IsImage(contentType) {
+		data, err := io.ReadAll(f)
+		if err != nil {
+			return "", err
+		}
+
+		clean, err = media.SanitizeImage(data, policy.MaxPixels)
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", name, err)
+		}
+		body, size = bytes.NewReader(clean), int64(len(clean))
+	}
+
+	url, err := s.backend.Put(ctx, interfaces.MediaFile{
+		Purpose:     purpose,
+		Name:        name,
+		ContentType: contentType,
+		Size:        size,
+		Body:        body,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if clean != nil {
+		s.images.Process(url, purpose, clean)
+	}
+	return url, nil
+}
+
+func (s *validatedStorage) Delete(ctx context.Context, url string) error {
+	if err := s.backend.Delete(ctx, url); err != nil {
+		return err
+	}
+	return s.images.Discard(ctx, url)
+}
+
+func (s *validatedStorage) SignedURL(ctx context.Context, url string, ttl time.Duration) (string, error) {
+	return s.backend.SignedURL(ctx, url, ttl)
+}
+
+func (s *validatedStorage) Metadata(ctx context.Context, url string) (*interfaces.MediaObject, error) {
+	return s.backend.Metadata(ctx, url)
+}
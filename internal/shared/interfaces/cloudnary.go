@@ -2,9 +2,69 @@ package interfaces
 
 import (
 	"context"
+	"errors"
+	"io"
 	"mime/multipart"
+	"time"
 )
 
+var ErrMediaNotFound = errors.New("media not found")
+
+// MediaPurpose says what an upload is for. It picks the validation rules
+// applied to the file and the folder it is stored under.
+type MediaPurpose string
+
+const (
+	PurposeAvatar  MediaPurpose = "avatars"
+	PurposePost    MediaPurpose = "posts"
+	PurposeComment MediaPurpose = "comments"
+	PurposeMessage MediaPurpose = "messages"
+)
+
+// Private reports whether files of the purpose are only served through
+// signed links. Chat attachments are meant for the room alone; everything
+// else is linked to directly from posts and profiles.
+func (p MediaPurpose) Private() bool {
+	return p == PurposeMessage
+}
+
+// MediaFile is an upload that has already been validated and sanitized.
+type MediaFile struct {
+	Purpose     MediaPurpose
+	Name        string
+	ContentType string
+	Size        int64
+	Body        io.Reader
+}
+
+// MediaObject describes a stored file.
+type MediaObject struct {
+	URL         string    `json:"url"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// MediaBackend stores files exactly as it is given them. Files are
+// addressed by the URL Put returned.
+type MediaBackend interface {
+	Put(ctx context.Context, file MediaFile) (string, error)
+	Delete(ctx context.Context, url string) error
+	// SignedURL returns a link to the file that stops working after ttl.
+	SignedURL(ctx context.Context, url string, ttl time.Duration) (string, error)
+	Metadata(ctx context.Context, url string) (*MediaObject, error)
+}
+
 type MediaStorage interface {
-	UploadToCloudinary(ctx context.Context, file *multipart.FileHeader)	(string, error)
+	// Upload checks the file against the rules for its purpose and stores a
+	// sanitized copy, returning the URL to serve it from.
+	Upload(ctx context.Context, file *multipart.FileHeader, purpose MediaPurpose) (string, error)
+	// UploadReader is Upload for a file that is already on the server, such
+	// as one assembled from an upload session.
+	UploadReader(ctx context.Context, r io.ReadSeeker, name string, size int64, purpose MediaPurpose) (string, error)
+	// Delete removes a previously uploaded asset given the URL returned by
+	// the upload.
+	Delete(ctx context.Context, url string) error
+	SignedURL(ctx context.Context, url string, ttl time.Duration) (string, error)
+	Metadata(ctx context.Context, url string) (*MediaObject, error)
 }
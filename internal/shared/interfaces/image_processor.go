@@ -0,0 +1,12 @@
+package interfaces
+
+import "context"
+
+// ImageProcessor derives smaller renditions of stored images.
+type ImageProcessor interface {
+	// Process queues the image stored at url; it returns at once and the
+	// work happens in the background.
+	Process(url string, purpose MediaPurpose, data []byte)
+	// Discard removes whatever was derived from url.
+	Discard(ctx context.Context, url string) error
+}
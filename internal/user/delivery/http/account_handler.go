@@ -0,0 +1,102 @@
+package http
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/Ramsi97/edu-social-backend/internal/user/domain"
+	"github.com/Ramsi97/edu-social-backend/pkg/response"
+	"github.com/gin-gonic/gin"
+)
+
+func (h *userHandler) RequestDeletion(ctx *gin.Context) {
+	userID, ok := currentUser(ctx)
+	if !ok {
+		return
+	}
+
+	var req domain.DeleteAccountRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.Error(ctx, http.StatusBadRequest, "password is required", err.Error())
+		return
+	}
+
+	scheduledFor, err := h.usecase.RequestDeletion(ctx.Request.Context(), userID, req.Password)
+	if err != nil {
+		writeUserError(ctx, err)
+		return
+	}
+
+	response.Success(ctx, http.StatusAccepted, "Account scheduled for deletion", domain.DeletionResponse{
+		ScheduledFor: scheduledFor,
+	})
+}
+
+func (h *userHandler) CancelDeletion(ctx *gin.Context) {
+	userID, ok := currentUser(ctx)
+	if !ok {
+		return
+	}
+
+	if err := h.usecase.CancelDeletion(ctx.Request.Context(), userID); err != nil {
+		writeUserError(ctx, err)
+		return
+	}
+
+	response.Success(ctx, http.StatusOK, "Account deletion cancelled", nil)
+}
+
+// Export streams a zip archive with one JSON file per kind of content.
+func (h *userHandler) Export(ctx *gin.Context) {
+	userID, ok := currentUser(ctx)
+	if !ok {
+		return
+	}
+
+	export, err := h.usecase.Export(ctx.Request.Context(), userID)
+	if err != nil {
+		writeUserError(ctx, err)
+		return
+	}
+
+	files := []struct {
+		name string
+		data any
+	}{
+		{"profile.json", export.Profile},
+		{"posts.json", export.Posts},
+		{"comments.json", export.Comments},
+		{"liked_posts.json", export.LikedPosts},
+		{"chat_messages.json", export.ChatMessages},
+		{"groups.json", export.Groups},
+		{"group_posts.json", export.GroupPosts},
+		{"media.json", export.Media},
+	}
+
+	filename := fmt.Sprintf("edu-social-export-%s.zip", export.GeneratedAt.Format("20060102"))
+	ctx.Header("Content-Type", "application/zip")
+	ctx.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	ctx.Status(http.StatusOK)
+
+	archive := zip.NewWriter(ctx.Writer)
+	for _, file := range files {
+		w, err := archive.Create(file.name)
+		if err == nil {
+			enc := json.NewEncoder(w)
+			enc.SetIndent("", "  ")
+			err = enc.Encode(file.data)
+		}
+		if err != nil {
+			// Headers are gone already; all we can do is cut the archive short.
+			log.Printf("failed to write export for %s: %v", userID, err)
+			return
+		}
+	}
+
+	if err := archive.Close(); err != nil {
+		log.Printf("failed to finish export for %s: %v", userID, err)
+	}
+}
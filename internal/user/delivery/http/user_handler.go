@@ -0,0 +1,262 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	authDomain "github.com/Ramsi97/edu-social-backend/internal/auth/domain"
+	"github.com/Ramsi97/edu-social-backend/internal/user/domain"
+	"github.com/Ramsi97/edu-social-backend/pkg/media"
+	"github.com/Ramsi97/edu-social-backend/pkg/response"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type userHandler struct {
+	usecase domain.UserUseCase
+}
+
+func NewUserHandler(rg *gin.RouterGroup, uc domain.UserUseCase) {
+	handler := &userHandler{
+		usecase: uc,
+	}
+
+	rg.GET("/me", handler.GetMe)
+	rg.PATCH("/me", handler.UpdateProfile)
+	rg.PUT("/me/avatar", handler.UpdateAvatar)
+	rg.DELETE("/me/avatar", handler.RemoveAvatar)
+	rg.PUT("/me/username", handler.SetUsername)
+	rg.DELETE("/me", handler.RequestDeletion)
+	rg.POST("/me/restore", handler.CancelDeletion)
+	rg.GET("/me/export", handler.Export)
+	rg.GET("/me/settings", handler.GetSettings)
+	rg.PATCH("/me/settings", handler.UpdateSettings)
+	rg.GET("/search", handler.Search)
+	rg.GET("/:user_id", handler.GetProfile)
+}
+
+func (h *userHandler) GetMe(ctx *gin.Context) {
+	userID, ok := currentUser(ctx)
+	if !ok {
+		return
+	}
+
+	profile, err := h.usecase.GetMe(ctx.Request.Context(), userID)
+	if err != nil {
+		writeUserError(ctx, err)
+		return
+	}
+
+	response.Success(ctx, http.StatusOK, "Profile fetched", profile)
+}
+
+func (h *userHandler) GetProfile(ctx *gin.Context) {
+	viewerID, ok := currentUser(ctx)
+	if !ok {
+		return
+	}
+
+	userID, err := uuid.Parse(ctx.Param("user_id"))
+	if err != nil {
+		response.Error(ctx, http.StatusBadRequest, "Invalid user ID", err.Error())
+		return
+	}
+
+	profile, err := h.usecase.GetProfile(ctx.Request.Context(), viewerID, userID)
+	if err != nil {
+		writeUserError(ctx, err)
+		return
+	}
+
+	response.Success(ctx, http.StatusOK, "Profile fetched", profile)
+}
+
+func (h *userHandler) GetSettings(ctx *gin.Context) {
+	userID, ok := currentUser(ctx)
+	if !ok {
+		return
+	}
+
+	settings, err := h.usecase.GetSettings(ctx.Request.Context(), userID)
+	if err != nil {
+		writeUserError(ctx, err)
+		return
+	}
+
+	response.Success(ctx, http.StatusOK, "Settings fetched", settings)
+}
+
+func (h *userHandler) UpdateSettings(ctx *gin.Context) {
+	userID, ok := currentUser(ctx)
+	if !ok {
+		return
+	}
+
+	var req domain.UpdateSettingsRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.Error(ctx, http.StatusBadRequest, "Invalid request", err.Error())
+		return
+	}
+
+	settings, err := h.usecase.UpdateSettings(ctx.Request.Context(), userID, &req)
+	if err != nil {
+		writeUserError(ctx, err)
+		return
+	}
+
+	response.Success(ctx, http.StatusOK, "Settings updated", settings)
+}
+
+func (h *userHandler) Search(ctx *gin.Context) {
+	viewerID, ok := currentUser(ctx)
+	if !ok {
+		return
+	}
+
+	q := domain.SearchQuery{
+		Query:  ctx.Query("q"),
+		Cursor: ctx.Query("cursor"),
+	}
+
+	if v := ctx.Query("joined_year"); v != "" {
+		year, err := strconv.Atoi(v)
+		if err != nil {
+			response.Error(ctx, http.StatusBadRequest, "Invalid joined_year", err.Error())
+			return
+		}
+		q.JoinedYear = year
+	}
+
+	if v := ctx.Query("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil {
+			response.Error(ctx, http.StatusBadRequest, "Invalid limit", err.Error())
+			return
+		}
+		q.Limit = limit
+	}
+
+	result, err := h.usecase.Search(ctx.Request.Context(), viewerID, q)
+	if err != nil {
+		writeUserError(ctx, err)
+		return
+	}
+
+	response.Success(ctx, http.StatusOK, "Users fetched", result)
+}
+
+func (h *userHandler) UpdateProfile(ctx *gin.Context) {
+	userID, ok := currentUser(ctx)
+	if !ok {
+		return
+	}
+
+	var req domain.UpdateProfileRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.Error(ctx, http.StatusBadRequest, "Invalid request", err.Error())
+		return
+	}
+
+	profile, err := h.usecase.UpdateProfile(ctx.Request.Context(), userID, &req)
+	if err != nil {
+		writeUserError(ctx, err)
+		return
+	}
+
+	response.Success(ctx, http.StatusOK, "Profile updated", profile)
+}
+
+func (h *userHandler) UpdateAvatar(ctx *gin.Context) {
+	userID, ok := currentUser(ctx)
+	if !ok {
+		return
+	}
+
+	file, err := ctx.FormFile("profile_picture")
+	if err != nil {
+		response.Error(ctx, http.StatusBadRequest, "profile_picture file is required", err.Error())
+		return
+	}
+
+	user, err := h.usecase.UpdateAvatar(ctx.Request.Context(), userID, file)
+	if err != nil {
+		writeUserError(ctx, err)
+		return
+	}
+
+	response.Success(ctx, http.StatusOK, "Profile picture updated", user.ToResponse())
+}
+
+func (h *userHandler) RemoveAvatar(ctx *gin.Context) {
+	userID, ok := currentUser(ctx)
+	if !ok {
+		return
+	}
+
+	user, err := h.usecase.RemoveAvatar(ctx.Request.Context(), userID)
+	if err != nil {
+		writeUserError(ctx, err)
+		return
+	}
+
+	response.Success(ctx, http.StatusOK, "Profile picture removed", user.ToResponse())
+}
+
+func (h *userHandler) SetUsername(ctx *gin.Context) {
+	userID, ok := currentUser(ctx)
+	if !ok {
+		return
+	}
+
+	var req domain.SetUsernameRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.Error(ctx, http.StatusBadRequest, "Invalid request", err.Error())
+		return
+	}
+
+	user, err := h.usecase.SetUsername(ctx.Request.Context(), userID, req.Username)
+	if err != nil {
+		writeUserError(ctx, err)
+		return
+	}
+
+	response.Success(ctx, http.StatusOK, "Username updated", user.ToResponse())
+}
+
+func currentUser(ctx *gin.Context) (uuid.UUID, bool) {
+	userID, err := uuid.Parse(ctx.GetString("user_id"))
+	if err != nil {
+		response.Error(ctx, http.StatusUnauthorized, "Invalid session", err.Error())
+		return uuid.Nil, false
+	}
+	return userID, true
+}
+
+func writeUserError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, authDomain.ErrUserNotFound):
+		response.Error(ctx, http.StatusNotFound, "User not found", err.Error())
+	case errors.Is(err, domain.ErrInvalidName), errors.Is(err, domain.ErrInvalidJoinedYear):
+		response.Error(ctx, http.StatusBadRequest, "Invalid profile", err.Error())
+	case errors.Is(err, domain.ErrInvalidUsername):
+		response.Error(ctx, http.StatusBadRequest, "Invalid username", err.Error())
+	case errors.Is(err, domain.ErrUsernameTaken):
+		response.Error(ctx, http.StatusConflict, "Username taken", err.Error())
+	case errors.Is(err, domain.ErrInvalidSettings):
+		response.Error(ctx, http.StatusBadRequest, "Invalid settings", err.Error())
+	case errors.Is(err, domain.ErrInvalidSearch), errors.Is(err, domain.ErrInvalidCursor):
+		response.Error(ctx, http.StatusBadRequest, "Invalid search", err.Error())
+	case errors.Is(err, media.ErrUnsupportedType), errors.Is(err, media.ErrCorruptImage),
+		errors.Is(err, media.ErrImageTooLarge), errors.Is(err, media.ErrPolyglot):
+		response.Error(ctx, http.StatusBadRequest, "Invalid image", err.Error())
+	case errors.Is(err, media.ErrFileTooLarge):
+		response.Error(ctx, http.StatusRequestEntityTooLarge, "Image too large", err.Error())
+	case errors.Is(err, domain.ErrInvalidPassword):
+		response.Error(ctx, http.StatusUnauthorized, "Incorrect password", err.Error())
+	case errors.Is(err, domain.ErrNoDeletionPending):
+		response.Error(ctx, http.StatusConflict, "Account is not scheduled for deletion", err.Error())
+	default:
+		response.Error(ctx, http.StatusInternalServerError, "Server Error", err.Error())
+	}
+}
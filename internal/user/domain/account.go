@@ -0,0 +1,89 @@
+package domain
+
+import (
+	"time"
+
+	authDomain "github.com/Ramsi97/edu-social-backend/internal/auth/domain"
+	"github.com/google/uuid"
+)
+
+// DeletionPolicy decides what happens to a deleted account's content.
+type DeletionPolicy string
+
+const (
+	// DeletionPolicyAnonymize keeps posts, comments and messages but strips
+	// every personal detail from the account they are attributed to.
+	DeletionPolicyAnonymize DeletionPolicy = "anonymize"
+	// DeletionPolicyDelete removes the account together with its content.
+	DeletionPolicyDelete DeletionPolicy = "delete"
+)
+
+type AccountConfig struct {
+	DeletionGracePeriod time.Duration
+	DeletionPolicy      DeletionPolicy
+}
+
+func DefaultAccountConfig() AccountConfig {
+	return AccountConfig{
+		DeletionGracePeriod: 30 * 24 * time.Hour,
+		DeletionPolicy:      DeletionPolicyAnonymize,
+	}
+}
+
+type DeleteAccountRequest struct {
+	Password string `json:"password" binding:"required"`
+}
+
+type DeletionResponse struct {
+	ScheduledFor time.Time `json:"scheduled_for"`
+}
+
+// DataExport is everything a user created on the platform. Media is listed
+// by URL rather than copied into the archive.
+type DataExport struct {
+	GeneratedAt  time.Time                 `json:"generated_at"`
+	Profile      authDomain.UserResponse   `json:"profile"`
+	Posts        []ExportedPost            `json:"posts"`
+	Comments     []ExportedComment         `json:"comments"`
+	LikedPosts   []uuid.UUID               `json:"liked_posts"`
+	ChatMessages []ExportedChatMessage     `json:"chat_messages"`
+	Groups       []ExportedGroupMembership `json:"groups"`
+	GroupPosts   []ExportedGroupPost       `json:"group_posts"`
+	Media        []string                  `json:"media"`
+}
+
+type ExportedPost struct {
+	ID          uuid.UUID `json:"id"`
+	Content     string    `json:"content"`
+	MediaURL    string    `json:"media_url"`
+	Attachments []string  `json:"attachments"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type ExportedComment struct {
+	ID        uuid.UUID `json:"id"`
+	PostID    uuid.UUID `json:"post_id"`
+	Content   string    `json:"content"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type ExportedChatMessage struct {
+	ID        uuid.UUID `json:"id"`
+	RoomID    string    `json:"room_id"`
+	Content   string    `json:"content"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type ExportedGroupMembership struct {
+	GroupID uuid.UUID `json:"group_id"`
+	Name    string    `json:"name"`
+	Role    string    `json:"role"`
+}
+
+type ExportedGroupPost struct {
+	ID        uuid.UUID `json:"id"`
+	GroupID   uuid.UUID `json:"group_id"`
+	Content   string    `json:"content"`
+	MediaURL  string    `json:"media_url"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,15 @@
+package domain
+
+import "errors"
+
+var (
+	ErrInvalidName       = errors.New("first and last name cannot be empty")
+	ErrInvalidJoinedYear = errors.New("joined year must be a date (YYYY-MM-DD) that is not in the future")
+	ErrInvalidPassword   = errors.New("password is incorrect")
+	ErrNoDeletionPending = errors.New("account is not scheduled for deletion")
+	ErrInvalidSearch     = errors.New("search query is too long or joined year is invalid")
+	ErrInvalidCursor     = errors.New("invalid cursor")
+	ErrInvalidSettings   = errors.New("invalid privacy settings")
+	ErrInvalidUsername   = errors.New("username must be 3 to 30 letters, digits or underscores, starting with a letter")
+	ErrUsernameTaken     = errors.New("username is already taken")
+)
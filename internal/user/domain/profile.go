@@ -0,0 +1,74 @@
+package domain
+
+import (
+	"context"
+	"mime/multipart"
+	"time"
+
+	authDomain "github.com/Ramsi97/edu-social-backend/internal/auth/domain"
+	followDomain "github.com/Ramsi97/edu-social-backend/internal/follow/domain"
+	"github.com/google/uuid"
+)
+
+// UpdateProfileRequest holds the fields a user may change about themselves.
+// Omitted fields are left untouched.
+type UpdateProfileRequest struct {
+	FirstName  *string `json:"first_name" binding:"omitempty,max=50"`
+	LastName   *string `json:"last_name" binding:"omitempty,max=50"`
+	JoinedYear *string `json:"joined_year"`
+	Gender     *string `json:"gender" binding:"omitempty,max=20"`
+	// IsPrivate turns new follows into requests the user has to approve.
+	IsPrivate *bool `json:"is_private"`
+	// Discoverable lists the user in search results. It is the same
+	// setting PATCH /users/me/settings changes.
+	Discoverable *bool `json:"discoverable"`
+}
+
+type SetUsernameRequest struct {
+	Username string `json:"username"`
+}
+
+// Profile is a user as shown on their profile page, with the follow
+// counts and relationship to the viewer.
+type Profile struct {
+	authDomain.UserResponse
+	followDomain.Stats
+}
+
+// UpdatedProfile is the user's own profile after an update, together with
+// the discoverable setting the same request may have changed.
+type UpdatedProfile struct {
+	authDomain.UserResponse
+	Discoverable bool `json:"discoverable"`
+}
+
+type UserUseCase interface {
+	PrivacyPolicy
+
+	GetMe(ctx context.Context, userID uuid.UUID) (*Profile, error)
+	// GetProfile returns another user's profile as seen by the viewer;
+	// suspended accounts are reported as not found.
+	GetProfile(ctx context.Context, viewerID, userID uuid.UUID) (*Profile, error)
+	UpdateProfile(ctx context.Context, userID uuid.UUID, req *UpdateProfileRequest) (*UpdatedProfile, error)
+	// UpdateAvatar uploads a new profile picture and deletes the old one.
+	UpdateAvatar(ctx context.Context, userID uuid.UUID, file *multipart.FileHeader) (*authDomain.User, error)
+	RemoveAvatar(ctx context.Context, userID uuid.UUID) (*authDomain.User, error)
+	// SetUsername claims the handle others @mention the user by, giving up
+	// the one held before.
+	SetUsername(ctx context.Context, userID uuid.UUID, username string) (*authDomain.User, error)
+	// Search finds discoverable users by name or student ID, hiding anyone
+	// on either side of a block with the viewer.
+	Search(ctx context.Context, viewerID uuid.UUID, q SearchQuery) (*SearchResult, error)
+
+	GetSettings(ctx context.Context, userID uuid.UUID) (*Settings, error)
+	UpdateSettings(ctx context.Context, userID uuid.UUID, req *UpdateSettingsRequest) (*Settings, error)
+
+	// RequestDeletion schedules the account for deletion once the grace
+	// period is over; until then CancelDeletion restores it.
+	RequestDeletion(ctx context.Context, userID uuid.UUID, password string) (time.Time, error)
+	CancelDeletion(ctx context.Context, userID uuid.UUID) error
+	// PurgeDeletedAccounts deletes or anonymizes every account whose grace
+	// period has run out.
+	PurgeDeletedAccounts(ctx context.Context) error
+	Export(ctx context.Context, userID uuid.UUID) (*DataExport, error)
+}
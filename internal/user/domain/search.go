@@ -0,0 +1,45 @@
+package domain
+
+import (
+	mediaDomain "github.com/Ramsi97/edu-social-backend/internal/media/domain"
+	"github.com/google/uuid"
+)
+
+// SearchQuery looks users up by name or student ID. Query may be empty to
+// browse the directory, e.g. everyone who joined in a given year.
+type SearchQuery struct {
+	Query string
+	// JoinedYear limits the results to one intake; zero means any year.
+	JoinedYear int
+	// Cursor is the NextCursor of the previous page.
+	Cursor string
+	Limit  int
+}
+
+// UserSummary is one search result. Contact details and the student ID are
+// never included, even when the match was on the student ID.
+type UserSummary struct {
+	ID                     uuid.UUID             `json:"id"`
+	FirstName              string                `json:"first_name"`
+	LastName               string                `json:"last_name"`
+	Username               *string               `json:"username"`
+	ProfilePicture         *string               `json:"profile_picture"`
+	ProfilePictureVariants *mediaDomain.Variants `json:"profile_picture_variants,omitempty"`
+	JoinedYear             string                `json:"joined_year"`
+	IsPrivate              bool                  `json:"is_private"`
+	Rank                   float32               `json:"-"`
+}
+
+type SearchResult struct {
+	Users      []UserSummary `json:"users"`
+	NextCursor string        `json:"next_cursor,omitempty"`
+}
+
+// SearchCursor is the position of the last result of a page. Results are
+// ordered by rank, then by name, so all of it is needed to resume.
+type SearchCursor struct {
+	Rank      float32   `json:"r"`
+	LastName  string    `json:"l"`
+	FirstName string    `json:"f"`
+	ID        uuid.UUID `json:"i"`
+}
@@ -0,0 +1,114 @@
+package domain
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// Visibility decides who gets to see a user's profile details and posts.
+type Visibility string
+
+const (
+	VisibleToEveryone  Visibility = "everyone"
+	VisibleToFollowers Visibility = "followers"
+	VisibleToOnlyMe    Visibility = "only_me"
+)
+
+func (v Visibility) Valid() bool {
+	switch v {
+	case VisibleToEveryone, VisibleToFollowers, VisibleToOnlyMe:
+		return true
+	}
+	return false
+}
+
+// Permission decides who may start an interaction with a user: message them,
+// comment on their posts or mention them. "following" means people the user
+// follows.
+type Permission string
+
+const (
+	AllowEveryone  Permission = "everyone"
+	AllowFollowing Permission = "following"
+	AllowNobody    Permission = "nobody"
+)
+
+func (p Permission) Valid() bool {
+	switch p {
+	case AllowEveryone, AllowFollowing, AllowNobody:
+		return true
+	}
+	return false
+}
+
+// Action is an interaction guarded by a Permission.
+type Action string
+
+const (
+	ActionMessage Action = "message"
+	ActionComment Action = "comment"
+	ActionMention Action = "mention"
+)
+
+// Settings are a user's privacy settings.
+type Settings struct {
+	ProfileVisibility Visibility `json:"profile_visibility"`
+	WhoCanMessage     Permission `json:"who_can_message"`
+	WhoCanComment     Permission `json:"who_can_comment"`
+	WhoCanMention     Permission `json:"who_can_mention"`
+	// Discoverable lists the user in search results.
+	Discoverable   bool `json:"discoverable"`
+	ShowJoinedYear bool `json:"show_joined_year"`
+}
+
+// ProfileVisibleTo reports whether someone other than the owner sees the
+// full profile, given whether they follow the owner.
+func (s *Settings) ProfileVisibleTo(viewerFollows bool) bool {
+	switch s.ProfileVisibility {
+	case VisibleToEveryone:
+		return true
+	case VisibleToFollowers:
+		return viewerFollows
+	}
+	return false
+}
+
+// Permits reports whether someone other than the owner may perform action,
+// given whether the owner follows them.
+func (s *Settings) Permits(action Action, ownerFollows bool) bool {
+	permission := s.WhoCanMessage
+	switch action {
+	case ActionComment:
+		permission = s.WhoCanComment
+	case ActionMention:
+		permission = s.WhoCanMention
+	}
+
+	switch permission {
+	case AllowEveryone:
+		return true
+	case AllowFollowing:
+		return ownerFollows
+	}
+	return false
+}
+
+// UpdateSettingsRequest changes the settings that are set and leaves the
+// others as they are.
+type UpdateSettingsRequest struct {
+	ProfileVisibility *Visibility `json:"profile_visibility"`
+	WhoCanMessage     *Permission `json:"who_can_message"`
+	WhoCanComment     *Permission `json:"who_can_comment"`
+	WhoCanMention     *Permission `json:"who_can_mention"`
+	Discoverable      *bool       `json:"discoverable"`
+	ShowJoinedYear    *bool       `json:"show_joined_year"`
+}
+
+// PrivacyPolicy lets other features check a user's settings before acting
+// on their behalf.
+type PrivacyPolicy interface {
+	// Allowed reports whether actorID may perform action towards ownerID.
+	// Users can always interact with themselves.
+	Allowed(ctx context.Context, actorID, ownerID uuid.UUID, action Action) (bool, error)
+}
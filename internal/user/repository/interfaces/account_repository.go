@@ -0,0 +1,23 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"github.com/Ramsi97/edu-social-backend/internal/user/domain"
+	"github.com/google/uuid"
+)
+
+type AccountRepository interface {
+	PasswordHash(ctx context.Context, userID uuid.UUID) (string, error)
+	ScheduleDeletion(ctx context.Context, userID uuid.UUID, at time.Time) error
+	CancelDeletion(ctx context.Context, userID uuid.UUID) error
+	// DueDeletions lists accounts whose scheduled deletion time has passed.
+	DueDeletions(ctx context.Context, now time.Time, limit int) ([]uuid.UUID, error)
+	// MediaURLs lists every uploaded file the user owns.
+	MediaURLs(ctx context.Context, userID uuid.UUID) ([]string, error)
+	AnonymizeUser(ctx context.Context, userID uuid.UUID) error
+	DeleteUser(ctx context.Context, userID uuid.UUID) error
+	// Export collects the user's content; the caller fills in the profile.
+	Export(ctx context.Context, userID uuid.UUID) (*domain.DataExport, error)
+}
@@ -0,0 +1,31 @@
+package interfaces
+
+import (
+	"context"
+
+	authDomain "github.com/Ramsi97/edu-social-backend/internal/auth/domain"
+	"github.com/Ramsi97/edu-social-backend/internal/user/domain"
+	"github.com/google/uuid"
+)
+
+type UserRepository interface {
+	FindByID(ctx context.Context, userID uuid.UUID) (*authDomain.User, error)
+	// UpdateProfile saves the profile fields and, when discoverable is set,
+	// the search opt-out in the same transaction. It returns the
+	// discoverable setting now in effect.
+	UpdateProfile(ctx context.Context, user *authDomain.User, discoverable *bool) (bool, error)
+	// SetProfilePicture stores the new picture URL (nil clears it) and
+	// returns the one it replaced.
+	SetProfilePicture(ctx context.Context, userID uuid.UUID, url *string) (*string, error)
+	Settings(ctx context.Context, userID uuid.UUID) (*domain.Settings, error)
+	UpdateSettings(ctx context.Context, userID uuid.UUID, settings *domain.Settings) error
+	// SetUsername claims the (lowercased) handle, or returns
+	// ErrUsernameTaken.
+	SetUsername(ctx context.Context, userID uuid.UUID, username string) error
+	// Relationship reports whether ownerID follows actorID and whether
+	// actorID follows ownerID. Pending requests don't count.
+	Relationship(ctx context.Context, actorID, ownerID uuid.UUID) (ownerFollows, actorFollows bool, err error)
+	// Search returns users matching the (lower-cased) query, best match
+	// first, starting after the cursor when one is given.
+	Search(ctx context.Context, viewerID uuid.UUID, q domain.SearchQuery, after *domain.SearchCursor) ([]domain.UserSummary, error)
+}
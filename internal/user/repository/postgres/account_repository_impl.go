@@ -0,0 +1,341 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	authDomain "github.com/Ramsi97/edu-social-backend/internal/auth/domain"
+	"github.com/Ramsi97/edu-social-backend/internal/user/domain"
+	"github.com/Ramsi97/edu-social-backend/internal/user/repository/interfaces"
+	"github.com/google/uuid"
+)
+
+type accountRepository struct {
+	db *sql.DB
+}
+
+func NewAccountRepository(db *sql.DB) interfaces.AccountRepository {
+	return &accountRepository{
+		db: db,
+	}
+}
+
+func (r *accountRepository) PasswordHash(ctx context.Context, userID uuid.UUID) (string, error) {
+	var hash string
+	err := r.db.QueryRowContext(ctx, `SELECT password_hash FROM users WHERE id = $1`, userID).Scan(&hash)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", authDomain.ErrUserNotFound
+		}
+		return "", err
+	}
+	return hash, nil
+}
+
+func (r *accountRepository) ScheduleDeletion(ctx context.Context, userID uuid.UUID, at time.Time) error {
+	query := `
+		UPDATE users
+		SET deletion_scheduled_at = $2
+		WHERE id = $1 AND deleted_at IS NULL
+	`
+
+	res, err := r.db.ExecContext(ctx, query, userID, at)
+	if err != nil {
+		return err
+	}
+
+	rows, _ := res.RowsAffected()
+	if rows == 0 {
+		return authDomain.ErrUserNotFound
+	}
+	return nil
+}
+
+func (r *accountRepository) CancelDeletion(ctx context.Context, userID uuid.UUID) error {
+	query := `
+		UPDATE users
+		SET deletion_scheduled_at = NULL
+		WHERE id = $1 AND deletion_scheduled_at IS NOT NULL AND deleted_at IS NULL
+	`
+
+	res, err := r.db.ExecContext(ctx, query, userID)
+	if err != nil {
+		return err
+	}
+
+	rows, _ := res.RowsAffected()
+	if rows == 0 {
+		return domain.ErrNoDeletionPending
+	}
+	return nil
+}
+
+func (r *accountRepository) DueDeletions(ctx context.Context, now time.Time, limit int) ([]uuid.UUID, error) {
+	query := `
+		SELECT id FROM users
+		WHERE deletion_scheduled_at <= $1 AND deleted_at IS NULL
+		ORDER BY deletion_scheduled_at
+		LIMIT $2
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, now, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+func (r *accountRepository) MediaURLs(ctx context.Context, userID uuid.UUID) ([]string, error) {
+	query := `
+		SELECT profile_picture FROM users WHERE id = $1 AND profile_picture <> ''
+		UNION
+		SELECT media_url FROM posts WHERE author_id = $1 AND media_url <> ''
+		UNION
+		SELECT pm.url FROM post_media pm JOIN posts p ON p.id = pm.post_id WHERE p.author_id = $1
+		UNION
+		SELECT media_url FROM group_posts WHERE author_id = $1 AND media_url <> ''
+		UNION
+		SELECT url FROM media WHERE owner_id = $1
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	urls := []string{}
+	for rows.Next() {
+		var url string
+		if err := rows.Scan(&url); err != nil {
+			return nil, err
+		}
+		urls = append(urls, url)
+	}
+
+	return urls, rows.Err()
+}
+
+func (r *accountRepository) AnonymizeUser(ctx context.Context, userID uuid.UUID) error {
+	return r.inTx(ctx, userID,
+		// Content stays but points at a nameless, unusable account.
+		`UPDATE users SET
+			first_name = 'Deleted',
+			last_name = 'User',
+			username = NULL,
+			email = 'deleted+' || id || '@invalid',
+			student_id = 'deleted-' || id,
+			password_hash = '',
+			profile_picture = NULL,
+			gender = '',
+			suspended_at = NOW(),
+			suspension_reason = 'account deleted',
+			deletion_scheduled_at = NULL,
+			deleted_at = NOW()
+		WHERE id = $1`,
+		`UPDATE posts SET media_url = '' WHERE author_id = $1`,
+		`DELETE FROM post_media WHERE post_id IN (SELECT id FROM posts WHERE author_id = $1)`,
+		`UPDATE group_posts SET media_url = '' WHERE author_id = $1`,
+		`DELETE FROM posts_likes WHERE user_id = $1`,
+		`DELETE FROM group_members WHERE user_id = $1 AND role <> 'owner'`,
+		`DELETE FROM follows WHERE follower_id = $1 OR followee_id = $1`,
+		`DELETE FROM user_blocks WHERE blocker_id = $1`,
+		`DELETE FROM user_mutes WHERE muter_id = $1`,
+		`DELETE FROM mentions WHERE user_id = $1`,
+		`DELETE FROM notifications WHERE user_id = $1 OR actor_id = $1`,
+		`DELETE FROM auth_sessions WHERE user_id = $1`,
+		`DELETE FROM one_time_tokens WHERE user_id = $1`,
+		`DELETE FROM user_mfa WHERE user_id = $1`,
+		`DELETE FROM mfa_recovery_codes WHERE user_id = $1`,
+		`DELETE FROM user_identities WHERE user_id = $1`,
+	)
+}
+
+func (r *accountRepository) DeleteUser(ctx context.Context, userID uuid.UUID) error {
+	return r.inTx(ctx, userID,
+		`DELETE FROM posts_likes
+		WHERE user_id = $1 OR post_id IN (SELECT id FROM posts WHERE author_id = $1)`,
+		`DELETE FROM comments
+		WHERE user_id = $1 OR post_id IN (SELECT id FROM posts WHERE author_id = $1)`,
+		`DELETE FROM posts WHERE author_id = $1`,
+		`DELETE FROM chat_messages WHERE sender_id = $1`,
+		`DELETE FROM group_posts WHERE author_id = $1`,
+		`DELETE FROM group_members WHERE user_id = $1`,
+		// Groups the user owned pass to one of their admins, or else to any
+		// remaining member; groups left empty are removed.
+		`WITH heirs AS (
+			SELECT DISTINCT ON (gm.group_id) gm.group_id, gm.user_id
+			FROM group_members gm
+			JOIN groups g ON g.id = gm.group_id
+			WHERE g.owner_id = $1
+			ORDER BY gm.group_id, (gm.role = 'admin') DESC, gm.user_id
+		)
+		UPDATE groups g SET owner_id = heirs.user_id
+		FROM heirs
+		WHERE g.id = heirs.group_id`,
+		`UPDATE group_members gm SET role = 'owner'
+		FROM groups g
+		WHERE g.id = gm.group_id AND g.owner_id = gm.user_id AND gm.role <> 'owner'`,
+		`DELETE FROM group_posts WHERE group_id IN (SELECT id FROM groups WHERE owner_id = $1)`,
+		`DELETE FROM groups WHERE owner_id = $1`,
+		`DELETE FROM users WHERE id = $1`,
+	)
+}
+
+// inTx runs every statement with the user ID as $1 in a single transaction.
+func (r *accountRepository) inTx(ctx context.Context, userID uuid.UUID, statements ...string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, stmt := range statements {
+		if _, err := tx.ExecContext(ctx, stmt, userID); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *accountRepository) Export(ctx context.Context, userID uuid.UUID) (*domain.DataExport, error) {
+	export := &domain.DataExport{
+		Posts:        []domain.ExportedPost{},
+		Comments:     []domain.ExportedComment{},
+		LikedPosts:   []uuid.UUID{},
+		ChatMessages: []domain.ExportedChatMessage{},
+		Groups:       []domain.ExportedGroupMembership{},
+		GroupPosts:   []domain.ExportedGroupPost{},
+	}
+
+	err := r.each(ctx, `
+		SELECT
+			p.id, p.content, COALESCE(p.media_url, ''), p.created_at,
+			COALESCE((
+				SELECT json_agg(pm.url ORDER BY pm.position)
+				FROM post_media pm WHERE pm.post_id = p.id
+			), '[]')
+		FROM posts p WHERE p.author_id = $1 ORDER BY p.created_at
+	`, userID, func(rows *sql.Rows) error {
+		var p domain.ExportedPost
+		var attachments []byte
+		if err := rows.Scan(&p.ID, &p.Content, &p.MediaURL, &p.CreatedAt, &attachments); err != nil {
+			return err
+		}
+		if err := json.Unmarshal(attachments, &p.Attachments); err != nil {
+			return err
+		}
+		export.Posts = append(export.Posts, p)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.each(ctx, `
+		SELECT id, post_id, content, created_at
+		FROM comments WHERE user_id = $1 ORDER BY created_at
+	`, userID, func(rows *sql.Rows) error {
+		var c domain.ExportedComment
+		if err := rows.Scan(&c.ID, &c.PostID, &c.Content, &c.CreatedAt); err != nil {
+			return err
+		}
+		export.Comments = append(export.Comments, c)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.each(ctx, `SELECT post_id FROM posts_likes WHERE user_id = $1`, userID, func(rows *sql.Rows) error {
+		var postID uuid.UUID
+		if err := rows.Scan(&postID); err != nil {
+			return err
+		}
+		export.LikedPosts = append(export.LikedPosts, postID)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.each(ctx, `
+		SELECT id, room_id::text, content, created_at
+		FROM chat_messages WHERE sender_id = $1 ORDER BY created_at
+	`, userID, func(rows *sql.Rows) error {
+		var m domain.ExportedChatMessage
+		if err := rows.Scan(&m.ID, &m.RoomID, &m.Content, &m.CreatedAt); err != nil {
+			return err
+		}
+		export.ChatMessages = append(export.ChatMessages, m)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.each(ctx, `
+		SELECT g.id, g.name, gm.role
+		FROM group_members gm
+		JOIN groups g ON g.id = gm.group_id
+		WHERE gm.user_id = $1
+		ORDER BY g.name
+	`, userID, func(rows *sql.Rows) error {
+		var g domain.ExportedGroupMembership
+		if err := rows.Scan(&g.GroupID, &g.Name, &g.Role); err != nil {
+			return err
+		}
+		export.Groups = append(export.Groups, g)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.each(ctx, `
+		SELECT id, group_id, content, COALESCE(media_url, ''), created_at
+		FROM group_posts WHERE author_id = $1 ORDER BY created_at
+	`, userID, func(rows *sql.Rows) error {
+		var p domain.ExportedGroupPost
+		if err := rows.Scan(&p.ID, &p.GroupID, &p.Content, &p.MediaURL, &p.CreatedAt); err != nil {
+			return err
+		}
+		export.GroupPosts = append(export.GroupPosts, p)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return export, nil
+}
+
+func (r *accountRepository) each(ctx context.Context, query string, userID uuid.UUID, scan func(*sql.Rows) error) error {
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		if err := scan(rows); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
@@ -0,0 +1,356 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	authDomain "github.com/Ramsi97/edu-social-backend/internal/auth/domain"
+	sharedPostgres "github.com/Ramsi97/edu-social-backend/internal/shared/infrastructure/postgres"
+	"github.com/Ramsi97/edu-social-backend/internal/user/domain"
+	"github.com/Ramsi97/edu-social-backend/internal/user/repository/interfaces"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+type userRepository struct {
+	db *sql.DB
+}
+
+func NewUserRepository(db *sql.DB) interfaces.UserRepository {
+	return &userRepository{
+		db: db,
+	}
+}
+
+func (r *userRepository) FindByID(ctx context.Context, userID uuid.UUID) (*authDomain.User, error) {
+	query := `
+		SELECT
+			id, first_name, last_name, student_id, email,
+			to_char(joined_year, 'YYYY-MM-DD'), profile_picture, gender, is_private,
+			email_verified_at, role, suspended_at, created_at, username
+		FROM users
+		WHERE id = $1
+	`
+
+	var user authDomain.User
+	err := r.db.QueryRowContext(ctx, query, userID).Scan(
+		&user.ID,
+		&user.FirstName,
+		&user.LastName,
+		&user.StudentID,
+		&user.Email,
+		&user.JoinedYear,
+		&user.ProfilePicture,
+		&user.Gender,
+		&user.IsPrivate,
+		&user.EmailVerifiedAt,
+		&user.Role,
+		&user.SuspendedAt,
+		&user.CreatedAt,
+		&user.Username,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, authDomain.ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+func (r *userRepository) UpdateProfile(ctx context.Context, user *authDomain.User, discoverable *bool) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		UPDATE users
+		SET first_name = $2, last_name = $3, joined_year = $4::date, gender = $5, is_private = $6,
+			discoverable = COALESCE($7, discoverable)
+		WHERE id = $1
+		RETURNING discoverable
+	`
+
+	var listed bool
+	err = tx.QueryRowContext(
+		ctx,
+		query,
+		user.ID,
+		user.FirstName,
+		user.LastName,
+		user.JoinedYear,
+		user.Gender,
+		user.IsPrivate,
+		discoverable,
+	).Scan(&listed)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, authDomain.ErrUserNotFound
+		}
+		return false, err
+	}
+
+	// A public account has nobody to approve requests, so let them all in.
+	if !user.IsPrivate {
+		_, err = tx.ExecContext(ctx, `
+			UPDATE follows
+			SET status = 'accepted', accepted_at = NOW()
+			WHERE followee_id = $1 AND status = 'pending'
+		`, user.ID)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	return listed, tx.Commit()
+}
+
+func (r *userRepository) SetProfilePicture(ctx context.Context, userID uuid.UUID, url *string) (*string, error) {
+	// The sub-select sees the row as it was before the update.
+	query := `
+		UPDATE users u
+		SET profile_picture = $2
+		FROM (SELECT id, profile_picture FROM users WHERE id = $1 FOR UPDATE) old
+		WHERE u.id = old.id
+		RETURNING old.profile_picture
+	`
+
+	var previous *string
+	err := r.db.QueryRowContext(ctx, query, userID, url).Scan(&previous)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, authDomain.ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	return previous, nil
+}
+
+func (r *userRepository) Settings(ctx context.Context, userID uuid.UUID) (*domain.Settings, error) {
+	query := `
+		SELECT profile_visibility, who_can_message, who_can_comment, who_can_mention,
+			discoverable, show_joined_year
+		FROM users
+		WHERE id = $1
+	`
+
+	var settings domain.Settings
+	err := r.db.QueryRowContext(ctx, query, userID).Scan(
+		&settings.ProfileVisibility,
+		&settings.WhoCanMessage,
+		&settings.WhoCanComment,
+		&settings.WhoCanMention,
+		&settings.Discoverable,
+		&settings.ShowJoinedYear,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, authDomain.ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	return &settings, nil
+}
+
+func (r *userRepository) UpdateSettings(ctx context.Context, userID uuid.UUID, settings *domain.Settings) error {
+	query := `
+		UPDATE users
+		SET profile_visibility = $2, who_can_message = $3, who_can_comment = $4,
+			who_can_mention = $5, discoverable = $6, show_joined_year = $7
+		WHERE id = $1
+	`
+
+	res, err := r.db.ExecContext(
+		ctx,
+		query,
+		userID,
+		settings.ProfileVisibility,
+		settings.WhoCanMessage,
+		settings.WhoCanComment,
+		settings.WhoCanMention,
+		settings.Discoverable,
+		settings.ShowJoinedYear,
+	)
+	if err != nil {
+		return err
+	}
+
+	rows, _ := res.RowsAffected()
+	if rows == 0 {
+		return authDomain.ErrUserNotFound
+	}
+
+	return nil
+}
+
+func (r *userRepository) SetUsername(ctx context.Context, userID uuid.UUID, username string) error {
+	res, err := r.db.ExecContext(ctx, `UPDATE users SET username = $2 WHERE id = $1`, userID, username)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+			return domain.ErrUsernameTaken
+		}
+		return err
+	}
+
+	rows, _ := res.RowsAffected()
+	if rows == 0 {
+		return authDomain.ErrUserNotFound
+	}
+
+	return nil
+}
+
+func (r *userRepository) Relationship(ctx context.Context, actorID, ownerID uuid.UUID) (bool, bool, error) {
+	query := `
+		SELECT
+			EXISTS (
+				SELECT 1 FROM follows
+				WHERE follower_id = $2 AND followee_id = $1 AND status = 'accepted'
+			),
+			EXISTS (
+				SELECT 1 FROM follows
+				WHERE follower_id = $1 AND followee_id = $2 AND status = 'accepted'
+			)
+	`
+
+	var ownerFollows, actorFollows bool
+	err := r.db.QueryRowContext(ctx, query, actorID, ownerID).Scan(&ownerFollows, &actorFollows)
+	return ownerFollows, actorFollows, err
+}
+
+// Search ranks prefix matches on a name above fuzzy (trigram) matches, and
+// an exact student ID above both. Ties are broken by name so the order is
+// stable across pages.
+func (r *userRepository) Search(
+	ctx context.Context,
+	viewerID uuid.UUID,
+	q domain.SearchQuery,
+	after *domain.SearchCursor,
+) ([]domain.UserSummary, error) {
+	query := `
+		SELECT id, first_name, last_name, username, profile_picture,
+			` + sharedPostgres.VariantsColumn("m.profile_picture") + `,
+			joined_year, is_private, rank
+		FROM (
+			SELECT
+				u.id, u.first_name, u.last_name, u.username, u.profile_picture,
+				CASE WHEN u.show_joined_year OR u.id = $4
+					THEN to_char(u.joined_year, 'YYYY') ELSE '' END AS joined_year,
+				u.is_private,
+				(CASE WHEN $1 = '' THEN 0 ELSE
+					similarity(lower(u.first_name || ' ' || u.last_name), $1)
+					+ CASE WHEN lower(u.first_name || ' ' || u.last_name) LIKE $2
+						OR lower(u.first_name || ' ' || u.last_name) LIKE $3 THEN 1 ELSE 0 END
+					+ CASE WHEN lower(u.student_id) = $1 THEN 2 ELSE 0 END
+					+ CASE WHEN u.username LIKE $11 THEN 1 ELSE 0 END
+				END)::real AS rank
+			FROM users u
+			WHERE u.suspended_at IS NULL
+				AND (u.discoverable OR u.id = $4)
+				AND NOT EXISTS (
+					SELECT 1 FROM user_blocks b
+					WHERE (b.blocker_id = $4 AND b.blocked_id = u.id)
+						OR (b.blocker_id = u.id AND b.blocked_id = $4)
+				)
+				AND ($5::int IS NULL OR (
+					EXTRACT(YEAR FROM u.joined_year) = $5
+					AND (u.show_joined_year OR u.id = $4)
+				))
+				AND (
+					$1 = ''
+					OR lower(u.first_name || ' ' || u.last_name) % $1
+					OR lower(u.first_name || ' ' || u.last_name) LIKE $2
+					OR lower(u.first_name || ' ' || u.last_name) LIKE $3
+					OR lower(u.student_id) = $1
+					OR u.username LIKE $11
+				)
+		) m
+		WHERE $6::real IS NULL
+			OR m.rank < $6
+			OR (m.rank = $6 AND (m.last_name, m.first_name, m.id) > ($7, $8, $9))
+		ORDER BY m.rank DESC, m.last_name, m.first_name, m.id
+		LIMIT $10
+	`
+
+	// Match the query at the start of the name or of any later word in it.
+	pattern := escapeLike(q.Query)
+	prefix := pattern + "%"
+	wordPrefix := "% " + pattern + "%"
+	// A leading "@" looks the query up as a handle.
+	handlePrefix := escapeLike(strings.TrimPrefix(q.Query, "@")) + "%"
+
+	var joinedYear *int
+	if q.JoinedYear != 0 {
+		joinedYear = &q.JoinedYear
+	}
+
+	var (
+		rank      *float32
+		lastName  string
+		firstName string
+		lastID    uuid.UUID
+	)
+	if after != nil {
+		rank = &after.Rank
+		lastName, firstName, lastID = after.LastName, after.FirstName, after.ID
+	}
+
+	rows, err := r.db.QueryContext(
+		ctx,
+		query,
+		q.Query,
+		prefix,
+		wordPrefix,
+		viewerID,
+		joinedYear,
+		rank,
+		lastName,
+		firstName,
+		lastID,
+		q.Limit,
+		handlePrefix,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := []domain.UserSummary{}
+	for rows.Next() {
+		var user domain.UserSummary
+		err := rows.Scan(
+			&user.ID,
+			&user.FirstName,
+			&user.LastName,
+			&user.Username,
+			&user.ProfilePicture,
+			&user.ProfilePictureVariants,
+			&user.JoinedYear,
+			&user.IsPrivate,
+			&user.Rank,
+		)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+
+	return users, rows.Err()
+}
+
+const uniqueViolation = "23505"
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// escapeLike makes s match itself literally inside a LIKE pattern.
+func escapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
@@ -0,0 +1,111 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/Ramsi97/edu-social-backend/internal/user/domain"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// purgeBatchSize bounds how many accounts one purge run processes.
+const purgeBatchSize = 50
+
+func (u *userUseCase) RequestDeletion(ctx context.Context, userID uuid.UUID, password string) (time.Time, error) {
+	hash, err := u.accountRepo.PasswordHash(ctx, userID)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)); err != nil {
+		return time.Time{}, domain.ErrInvalidPassword
+	}
+
+	scheduledFor := time.Now().UTC().Add(u.cfg.DeletionGracePeriod)
+	if err := u.accountRepo.ScheduleDeletion(ctx, userID, scheduledFor); err != nil {
+		return time.Time{}, err
+	}
+
+	return scheduledFor, nil
+}
+
+func (u *userUseCase) CancelDeletion(ctx context.Context, userID uuid.UUID) error {
+	return u.accountRepo.CancelDeletion(ctx, userID)
+}
+
+func (u *userUseCase) PurgeDeletedAccounts(ctx context.Context) error {
+	ids, err := u.accountRepo.DueDeletions(ctx, time.Now().UTC(), purgeBatchSize)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, id := range ids {
+		if err := u.purgeAccount(ctx, id); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (u *userUseCase) purgeAccount(ctx context.Context, userID uuid.UUID) error {
+	// Collect the media first: once the rows are gone so are the URLs.
+	media, err := u.accountRepo.MediaURLs(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if u.cfg.DeletionPolicy == domain.DeletionPolicyDelete {
+		err = u.accountRepo.DeleteUser(ctx, userID)
+	} else {
+		err = u.accountRepo.AnonymizeUser(ctx, userID)
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, url := range media {
+		u.deleteMedia(ctx, url)
+	}
+
+	log.Printf("purged account %s (%s)", userID, u.cfg.DeletionPolicy)
+	return nil
+}
+
+func (u *userUseCase) Export(ctx context.Context, userID uuid.UUID) (*domain.DataExport, error) {
+	user, err := u.repo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	export, err := u.accountRepo.Export(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	export.GeneratedAt = time.Now().UTC()
+	export.Profile = user.ToResponse()
+
+	export.Media = []string{}
+	if user.ProfilePicture != nil && *user.ProfilePicture != "" {
+		export.Media = append(export.Media, *user.ProfilePicture)
+	}
+	for _, p := range export.Posts {
+		// media_url repeats the first attachment of newer posts.
+		if p.MediaURL != "" && (len(p.Attachments) == 0 || p.Attachments[0] != p.MediaURL) {
+			export.Media = append(export.Media, p.MediaURL)
+		}
+		export.Media = append(export.Media, p.Attachments...)
+	}
+	for _, p := range export.GroupPosts {
+		if p.MediaURL != "" {
+			export.Media = append(export.Media, p.MediaURL)
+		}
+	}
+
+	return export, nil
+}
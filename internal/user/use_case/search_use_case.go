@@ -0,0 +1,85 @@
+package usecase
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Ramsi97/edu-social-backend/internal/user/domain"
+	"github.com/google/uuid"
+)
+
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 50
+	maxSearchLength    = 100
+	minJoinedYear      = 1900
+)
+
+func (u *userUseCase) Search(ctx context.Context, viewerID uuid.UUID, q domain.SearchQuery) (*domain.SearchResult, error) {
+	q.Query = strings.ToLower(strings.TrimSpace(q.Query))
+	if utf8.RuneCountInString(q.Query) > maxSearchLength {
+		return nil, domain.ErrInvalidSearch
+	}
+
+	if q.JoinedYear != 0 && (q.JoinedYear < minJoinedYear || q.JoinedYear > time.Now().Year()) {
+		return nil, domain.ErrInvalidSearch
+	}
+
+	if q.Limit <= 0 {
+		q.Limit = defaultSearchLimit
+	}
+	if q.Limit > maxSearchLimit {
+		q.Limit = maxSearchLimit
+	}
+
+	var after *domain.SearchCursor
+	if q.Cursor != "" {
+		cursor, err := decodeSearchCursor(q.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		after = cursor
+	}
+
+	users, err := u.repo.Search(ctx, viewerID, q, after)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &domain.SearchResult{Users: users}
+	if len(users) == q.Limit {
+		last := users[len(users)-1]
+		result.NextCursor = encodeSearchCursor(domain.SearchCursor{
+			Rank:      last.Rank,
+			LastName:  last.LastName,
+			FirstName: last.FirstName,
+			ID:        last.ID,
+		})
+	}
+
+	return result, nil
+}
+
+// Search cursors are opaque to clients: base64url-encoded JSON.
+func encodeSearchCursor(c domain.SearchCursor) string {
+	raw, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func decodeSearchCursor(s string) (*domain.SearchCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, domain.ErrInvalidCursor
+	}
+
+	var c domain.SearchCursor
+	if err := json.Unmarshal(raw, &c); err != nil || c.ID == uuid.Nil {
+		return nil, domain.ErrInvalidCursor
+	}
+
+	return &c, nil
+}
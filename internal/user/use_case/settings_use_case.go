@@ -0,0 +1,74 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/Ramsi97/edu-social-backend/internal/user/domain"
+	"github.com/google/uuid"
+)
+
+func (u *userUseCase) GetSettings(ctx context.Context, userID uuid.UUID) (*domain.Settings, error) {
+	return u.repo.Settings(ctx, userID)
+}
+
+func (u *userUseCase) UpdateSettings(ctx context.Context, userID uuid.UUID, req *domain.UpdateSettingsRequest) (*domain.Settings, error) {
+	settings, err := u.repo.Settings(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.ProfileVisibility != nil {
+		if !req.ProfileVisibility.Valid() {
+			return nil, domain.ErrInvalidSettings
+		}
+		settings.ProfileVisibility = *req.ProfileVisibility
+	}
+	if req.WhoCanMessage != nil {
+		if !req.WhoCanMessage.Valid() {
+			return nil, domain.ErrInvalidSettings
+		}
+		settings.WhoCanMessage = *req.WhoCanMessage
+	}
+	if req.WhoCanComment != nil {
+		if !req.WhoCanComment.Valid() {
+			return nil, domain.ErrInvalidSettings
+		}
+		settings.WhoCanComment = *req.WhoCanComment
+	}
+	if req.WhoCanMention != nil {
+		if !req.WhoCanMention.Valid() {
+			return nil, domain.ErrInvalidSettings
+		}
+		settings.WhoCanMention = *req.WhoCanMention
+	}
+	if req.Discoverable != nil {
+		settings.Discoverable = *req.Discoverable
+	}
+	if req.ShowJoinedYear != nil {
+		settings.ShowJoinedYear = *req.ShowJoinedYear
+	}
+
+	if err := u.repo.UpdateSettings(ctx, userID, settings); err != nil {
+		return nil, err
+	}
+
+	return settings, nil
+}
+
+func (u *userUseCase) Allowed(ctx context.Context, actorID, ownerID uuid.UUID, action domain.Action) (bool, error) {
+	if actorID == ownerID {
+		return true, nil
+	}
+
+	settings, err := u.repo.Settings(ctx, ownerID)
+	if err != nil {
+		return false, err
+	}
+
+	ownerFollows, _, err := u.repo.Relationship(ctx, actorID, ownerID)
+	if err != nil {
+		return false, err
+	}
+
+	return settings.Permits(action, ownerFollows), nil
+}
@@ -0,0 +1,215 @@
+package usecase
+
+import (
+	"context"
+	"log"
+	"mime/multipart"
+	"strings"
+	"time"
+
+	authDomain "github.com/Ramsi97/edu-social-backend/internal/auth/domain"
+	followDomain "github.com/Ramsi97/edu-social-backend/internal/follow/domain"
+	sharedInterfaces "github.com/Ramsi97/edu-social-backend/internal/shared/interfaces"
+	"github.com/Ramsi97/edu-social-backend/internal/user/domain"
+	"github.com/Ramsi97/edu-social-backend/internal/user/repository/interfaces"
+	"github.com/Ramsi97/edu-social-backend/pkg/mention"
+	"github.com/google/uuid"
+)
+
+const joinedYearLayout = "2006-01-02"
+
+type userUseCase struct {
+	repo        interfaces.UserRepository
+	accountRepo interfaces.AccountRepository
+	follows     followDomain.FollowUseCase
+	media       sharedInterfaces.MediaStorage
+	cfg         domain.AccountConfig
+}
+
+func NewUserUseCase(
+	repo interfaces.UserRepository,
+	accountRepo interfaces.AccountRepository,
+	follows followDomain.FollowUseCase,
+	media sharedInterfaces.MediaStorage,
+	cfg domain.AccountConfig,
+) domain.UserUseCase {
+	return &userUseCase{
+		repo:        repo,
+		accountRepo: accountRepo,
+		follows:     follows,
+		media:       media,
+		cfg:         cfg,
+	}
+}
+
+func (u *userUseCase) GetMe(ctx context.Context, userID uuid.UUID) (*domain.Profile, error) {
+	user, err := u.repo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.profile(ctx, userID, user.ToResponse())
+}
+
+func (u *userUseCase) GetProfile(ctx context.Context, viewerID, userID uuid.UUID) (*domain.Profile, error) {
+	user, err := u.repo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.Suspended() {
+		return nil, authDomain.ErrUserNotFound
+	}
+
+	res := user.ToPublicResponse()
+	if viewerID != userID {
+		if err := u.applySettings(ctx, viewerID, userID, &res); err != nil {
+			return nil, err
+		}
+	}
+
+	return u.profile(ctx, viewerID, res)
+}
+
+// applySettings strips the details the profile owner doesn't share with
+// the viewer. Name and picture are always shown so the profile can still
+// be recognised and followed.
+func (u *userUseCase) applySettings(ctx context.Context, viewerID, ownerID uuid.UUID, res *authDomain.UserResponse) error {
+	settings, err := u.repo.Settings(ctx, ownerID)
+	if err != nil {
+		return err
+	}
+
+	_, viewerFollows, err := u.repo.Relationship(ctx, viewerID, ownerID)
+	if err != nil {
+		return err
+	}
+
+	if !settings.ShowJoinedYear {
+		res.JoinedYear = ""
+	}
+	if !settings.ProfileVisibleTo(viewerFollows) {
+		res.JoinedYear = ""
+		res.Gender = ""
+		res.CreatedAt = ""
+	}
+
+	return nil
+}
+
+func (u *userUseCase) profile(ctx context.Context, viewerID uuid.UUID, user authDomain.UserResponse) (*domain.Profile, error) {
+	userID, err := uuid.Parse(user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	stats, err := u.follows.Stats(ctx, viewerID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.Profile{UserResponse: user, Stats: *stats}, nil
+}
+
+func (u *userUseCase) UpdateProfile(ctx context.Context, userID uuid.UUID, req *domain.UpdateProfileRequest) (*domain.UpdatedProfile, error) {
+	user, err := u.repo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.FirstName != nil {
+		user.FirstName = strings.TrimSpace(*req.FirstName)
+	}
+	if req.LastName != nil {
+		user.LastName = strings.TrimSpace(*req.LastName)
+	}
+	if req.Gender != nil {
+		user.Gender = strings.TrimSpace(*req.Gender)
+	}
+	if req.IsPrivate != nil {
+		user.IsPrivate = *req.IsPrivate
+	}
+	if req.JoinedYear != nil {
+		user.JoinedYear = strings.TrimSpace(*req.JoinedYear)
+	}
+
+	if user.FirstName == "" || user.LastName == "" {
+		return nil, domain.ErrInvalidName
+	}
+
+	joined, err := time.Parse(joinedYearLayout, user.JoinedYear)
+	if err != nil || joined.After(time.Now()) {
+		return nil, domain.ErrInvalidJoinedYear
+	}
+
+	discoverable, err := u.repo.UpdateProfile(ctx, user, req.Discoverable)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.UpdatedProfile{
+		UserResponse: user.ToResponse(),
+		Discoverable: discoverable,
+	}, nil
+}
+
+func (u *userUseCase) UpdateAvatar(ctx context.Context, userID uuid.UUID, file *multipart.FileHeader) (*authDomain.User, error) {
+	url, err := u.media.Upload(ctx, file, sharedInterfaces.PurposeAvatar)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.replaceAvatar(ctx, userID, &url)
+}
+
+func (u *userUseCase) RemoveAvatar(ctx context.Context, userID uuid.UUID) (*authDomain.User, error) {
+	return u.replaceAvatar(ctx, userID, nil)
+}
+
+// reservedUsernames could be mistaken for the app itself or its staff.
+var reservedUsernames = map[string]bool{
+	"admin":         true,
+	"administrator": true,
+	"moderator":     true,
+	"support":       true,
+	"system":        true,
+	"everyone":      true,
+	"here":          true,
+}
+
+func (u *userUseCase) SetUsername(ctx context.Context, userID uuid.UUID, username string) (*authDomain.User, error) {
+	handle, ok := mention.NormalizeHandle(strings.TrimSpace(username))
+	if !ok || reservedUsernames[handle] {
+		return nil, domain.ErrInvalidUsername
+	}
+
+	if err := u.repo.SetUsername(ctx, userID, handle); err != nil {
+		return nil, err
+	}
+
+	return u.repo.FindByID(ctx, userID)
+}
+
+func (u *userUseCase) replaceAvatar(ctx context.Context, userID uuid.UUID, url *string) (*authDomain.User, error) {
+	previous, err := u.repo.SetProfilePicture(ctx, userID, url)
+	if err != nil {
+		if url != nil {
+			u.deleteMedia(ctx, *url)
+		}
+		return nil, err
+	}
+
+	// The profile already points at the new picture, so a failed cleanup
+	// only leaves an orphaned file behind.
+	if previous != nil && *previous != "" {
+		u.deleteMedia(ctx, *previous)
+	}
+
+	return u.repo.FindByID(ctx, userID)
+}
+
+func (u *userUseCase) deleteMedia(ctx context.Context, url string) {
+	if err := u.media.Delete(ctx, url); err != nil {
+		log.Printf("failed to delete media %s: %v", url, err)
+	}
+}
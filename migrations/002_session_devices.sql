@@ -0,0 +1,6 @@
+-- Device details so users can recognise and end individual sessions.
+ALTER TABLE auth_sessions
+    ADD COLUMN IF NOT EXISTS device_label TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS user_agent   TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS ip_address   TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS last_seen_at TIMESTAMPTZ NOT NULL DEFAULT NOW();
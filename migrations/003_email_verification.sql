@@ -0,0 +1,31 @@
+-- New accounts start unverified until the emailed link is opened. Accounts
+-- created before verification existed keep working, so they are backfilled,
+-- but only in the run that adds the column: re-running this file must not
+-- verify people who signed up since.
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT 1 FROM information_schema.columns
+        WHERE table_schema = current_schema()
+          AND table_name = 'users'
+          AND column_name = 'email_verified_at'
+    ) THEN
+        ALTER TABLE users ADD COLUMN email_verified_at TIMESTAMPTZ;
+        UPDATE users SET email_verified_at = created_at;
+    END IF;
+END $$;
+
+-- Single-use tokens mailed to users (email verification, and later password
+-- resets). Only the SHA-256 hash of the token is stored.
+CREATE TABLE IF NOT EXISTS one_time_tokens (
+    id          UUID PRIMARY KEY,
+    user_id     UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    purpose     TEXT NOT NULL,
+    token_hash  TEXT NOT NULL UNIQUE,
+    expires_at  TIMESTAMPTZ NOT NULL,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    used_at     TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_one_time_tokens_user_purpose
+    ON one_time_tokens(user_id, purpose, created_at);
@@ -0,0 +1,7 @@
+-- Failed login tracking per account identifier and per client IP.
+CREATE TABLE IF NOT EXISTS login_attempts (
+    key              TEXT PRIMARY KEY,
+    failures         INT NOT NULL DEFAULT 0,
+    last_failure_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    locked_until     TIMESTAMPTZ
+);
@@ -0,0 +1,16 @@
+-- TOTP enrollment; a row without enabled_at is a pending enrollment.
+CREATE TABLE IF NOT EXISTS user_mfa (
+    user_id         UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    secret          TEXT NOT NULL,
+    last_used_step  BIGINT,
+    enabled_at      TIMESTAMPTZ,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS mfa_recovery_codes (
+    user_id     UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    code_hash   TEXT NOT NULL,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    used_at     TIMESTAMPTZ,
+    PRIMARY KEY (user_id, code_hash)
+);
@@ -0,0 +1,19 @@
+-- Pending OIDC authorization requests (state, nonce and PKCE verifier).
+CREATE TABLE IF NOT EXISTS oidc_login_states (
+    state_hash     TEXT PRIMARY KEY,
+    nonce          TEXT NOT NULL,
+    code_verifier  TEXT NOT NULL,
+    expires_at     TIMESTAMPTZ NOT NULL
+);
+
+-- External provider accounts linked to local users.
+CREATE TABLE IF NOT EXISTS user_identities (
+    issuer      TEXT NOT NULL,
+    subject     TEXT NOT NULL,
+    user_id     UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    email       TEXT NOT NULL DEFAULT '',
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (issuer, subject)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_identities_user_id ON user_identities(user_id);
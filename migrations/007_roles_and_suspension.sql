@@ -0,0 +1,12 @@
+-- Platform roles and account suspension.
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS role TEXT NOT NULL DEFAULT 'student'
+        CHECK (role IN ('student', 'staff', 'moderator', 'admin')),
+    ADD COLUMN IF NOT EXISTS suspended_at TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS suspension_reason TEXT;
+
+-- Admin user listing pages through users newest first.
+CREATE INDEX IF NOT EXISTS idx_users_created_at ON users(created_at DESC);
+
+-- The first administrator has to be promoted by hand, e.g.
+-- UPDATE users SET role = 'admin' WHERE email = 'someone@example.edu';
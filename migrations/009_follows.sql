@@ -0,0 +1,20 @@
+-- Private accounts turn new follows into requests awaiting approval.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS is_private BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TABLE IF NOT EXISTS follows (
+    follower_id  UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    followee_id  UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    status       TEXT NOT NULL CHECK (status IN ('pending', 'accepted')),
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    accepted_at  TIMESTAMPTZ,
+    PRIMARY KEY (follower_id, followee_id),
+    CHECK (follower_id <> followee_id)
+);
+
+-- Followers and pending requests of a user, newest first.
+CREATE INDEX IF NOT EXISTS idx_follows_followee
+    ON follows(followee_id, status, created_at DESC);
+
+-- Accounts a user follows, newest first.
+CREATE INDEX IF NOT EXISTS idx_follows_follower
+    ON follows(follower_id, status, created_at DESC);
@@ -0,0 +1,9 @@
+-- The following feed is read with a semi-join on follows rather than a
+-- fan-out inbox: for each followed author the newest posts come straight
+-- off this index, and the global feed walks posts by created_at.
+CREATE INDEX IF NOT EXISTS idx_posts_author_created_at ON posts(author_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_posts_created_at ON posts(created_at DESC);
+
+-- Per-post like and comment counts of a feed page.
+CREATE INDEX IF NOT EXISTS idx_posts_likes_post_id ON posts_likes(post_id);
+CREATE INDEX IF NOT EXISTS idx_comments_post_id ON comments(post_id);
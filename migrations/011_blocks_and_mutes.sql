@@ -0,0 +1,20 @@
+-- A block hides the two users from each other and stops any interaction
+-- between them; it is checked in both directions.
+CREATE TABLE IF NOT EXISTS user_blocks (
+    blocker_id  UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    blocked_id  UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (blocker_id, blocked_id),
+    CHECK (blocker_id <> blocked_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_blocks_blocked ON user_blocks(blocked_id, blocker_id);
+
+-- A mute only filters the muted user's posts out of the muter's feeds.
+CREATE TABLE IF NOT EXISTS user_mutes (
+    muter_id    UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    muted_id    UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (muter_id, muted_id),
+    CHECK (muter_id <> muted_id)
+);
@@ -0,0 +1,11 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- Users can opt out of appearing in search results.
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS discoverable BOOLEAN NOT NULL DEFAULT TRUE;
+
+-- Serves both the fuzzy (%) and the prefix (LIKE) name matches.
+CREATE INDEX IF NOT EXISTS idx_users_name_trgm
+    ON users USING GIN (lower(first_name || ' ' || last_name) gin_trgm_ops);
+
+CREATE INDEX IF NOT EXISTS idx_users_student_id_lower ON users(lower(student_id));
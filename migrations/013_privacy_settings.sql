@@ -0,0 +1,10 @@
+-- Privacy settings live on the user row next to is_private and
+-- discoverable. Defaults keep accounts as open as they were before.
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS profile_visibility TEXT NOT NULL DEFAULT 'everyone'
+        CHECK (profile_visibility IN ('everyone', 'followers', 'only_me')),
+    ADD COLUMN IF NOT EXISTS who_can_message TEXT NOT NULL DEFAULT 'everyone'
+        CHECK (who_can_message IN ('everyone', 'following', 'nobody')),
+    ADD COLUMN IF NOT EXISTS who_can_comment TEXT NOT NULL DEFAULT 'everyone'
+        CHECK (who_can_comment IN ('everyone', 'following', 'nobody')),
+    ADD COLUMN IF NOT EXISTS show_joined_year BOOLEAN NOT NULL DEFAULT TRUE;
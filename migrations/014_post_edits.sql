@@ -0,0 +1,20 @@
+-- Posts can be edited and soft-deleted. Deleted posts keep their row (and
+-- comments) for moderation but drop out of every listing.
+ALTER TABLE posts
+    ADD COLUMN IF NOT EXISTS edited_at TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS deleted_by UUID REFERENCES users(id) ON DELETE SET NULL;
+
+-- Each row is the content a post had before an edit.
+CREATE TABLE IF NOT EXISTS post_revisions (
+    id          UUID PRIMARY KEY,
+    post_id     UUID NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    content     TEXT NOT NULL,
+    edited_by   UUID REFERENCES users(id) ON DELETE SET NULL,
+    edited_at   TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_post_revisions_post ON post_revisions(post_id, edited_at DESC);
+
+-- Feed pages only ever read live posts.
+CREATE INDEX IF NOT EXISTS idx_posts_live_created_at ON posts(created_at DESC) WHERE deleted_at IS NULL;
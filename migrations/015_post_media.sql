@@ -0,0 +1,22 @@
+-- Posts carry an ordered list of attachments. posts.media_url stays as the
+-- first attachment's URL for older clients.
+CREATE TABLE IF NOT EXISTS post_media (
+    id                UUID PRIMARY KEY,
+    post_id           UUID NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    position          INT NOT NULL,
+    type              TEXT NOT NULL CHECK (type IN ('image', 'video', 'document')),
+    url               TEXT NOT NULL,
+    size_bytes        BIGINT NOT NULL DEFAULT 0,
+    width             INT,
+    height            INT,
+    duration_seconds  DOUBLE PRECISION,
+    file_name         TEXT NOT NULL DEFAULT '',
+    UNIQUE (post_id, position)
+);
+
+-- Posts from before attachments had at most one image.
+INSERT INTO post_media (id, post_id, position, type, url)
+SELECT gen_random_uuid(), p.id, 0, 'image', p.media_url
+FROM posts p
+WHERE p.media_url <> '' AND p.deleted_at IS NULL
+ON CONFLICT (post_id, position) DO NOTHING;
@@ -0,0 +1,10 @@
+-- Smaller renditions of uploaded images, keyed by the original's URL so
+-- post attachments and profile pictures share them.
+CREATE TABLE IF NOT EXISTS media_variants (
+    url             TEXT PRIMARY KEY,
+    thumbnail_url   TEXT NOT NULL,
+    medium_url      TEXT NOT NULL,
+    blurhash        TEXT NOT NULL,
+    dominant_color  TEXT NOT NULL,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT now()
+);
@@ -0,0 +1,37 @@
+-- Resumable uploads: a session collects chunks until it is finalized into a
+-- media record, which a post, comment or message then claims by ID.
+CREATE TABLE IF NOT EXISTS upload_sessions (
+    id              UUID PRIMARY KEY,
+    owner_id        UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    purpose         TEXT NOT NULL,
+    file_name       TEXT NOT NULL DEFAULT '',
+    content_type    TEXT NOT NULL,
+    size_bytes      BIGINT NOT NULL,
+    received_bytes  BIGINT NOT NULL DEFAULT 0,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+    expires_at      TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_upload_sessions_expires_at ON upload_sessions (expires_at);
+
+CREATE TABLE IF NOT EXISTS media (
+    id                UUID PRIMARY KEY,
+    owner_id          UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    purpose           TEXT NOT NULL,
+    type              TEXT NOT NULL CHECK (type IN ('image', 'video', 'document')),
+    url               TEXT NOT NULL,
+    content_type      TEXT NOT NULL,
+    size_bytes        BIGINT NOT NULL,
+    width             INT,
+    height            INT,
+    duration_seconds  DOUBLE PRECISION,
+    file_name         TEXT NOT NULL DEFAULT '',
+    created_at        TIMESTAMPTZ NOT NULL DEFAULT now(),
+    -- Set once a post, comment or message has claimed it.
+    attached_at       TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_media_unattached ON media (created_at) WHERE attached_at IS NULL;
+
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS media_id UUID REFERENCES media(id) ON DELETE SET NULL;
+ALTER TABLE chat_messages ADD COLUMN IF NOT EXISTS media_id UUID REFERENCES media(id) ON DELETE SET NULL;
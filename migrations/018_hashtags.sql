@@ -0,0 +1,35 @@
+-- Hashtags found in post content. Names are stored lowercased, so #CS201
+-- and #cs201 are the same tag.
+CREATE TABLE IF NOT EXISTS tags (
+    id          UUID PRIMARY KEY,
+    name        TEXT NOT NULL UNIQUE,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- Serves the prefix (LIKE 'abc%') lookups of tag autocomplete.
+CREATE INDEX IF NOT EXISTS idx_tags_name_pattern ON tags (name text_pattern_ops);
+
+-- created_at repeats the post's, so a tag page and the trending window can
+-- both be read off idx_post_tags_tag_created_at.
+CREATE TABLE IF NOT EXISTS post_tags (
+    post_id     UUID NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    tag_id      UUID NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+    created_at  TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (post_id, tag_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_post_tags_tag_created_at ON post_tags (tag_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_post_tags_created_at ON post_tags (created_at DESC);
+
+-- Posts written before hashtags were extracted. The server tags them at
+-- startup through pkg/hashtag, the same rules new posts go through; SQL
+-- character classes can't follow its Unicode letter and mark rules and
+-- depend on the database locale.
+CREATE TABLE IF NOT EXISTS post_tag_backfill (
+    post_id     UUID PRIMARY KEY REFERENCES posts(id) ON DELETE CASCADE
+);
+
+INSERT INTO post_tag_backfill (post_id)
+SELECT id FROM posts
+WHERE deleted_at IS NULL AND content LIKE '%#%'
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,42 @@
+-- Users can claim a unique handle to be @mentioned by. Handles are stored
+-- lowercased, so uniqueness ignores case.
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS username TEXT
+        CHECK (username = lower(username)),
+    ADD COLUMN IF NOT EXISTS who_can_mention TEXT NOT NULL DEFAULT 'everyone'
+        CHECK (who_can_mention IN ('everyone', 'following', 'nobody'));
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_username ON users (username);
+
+-- A mention links a span of a post, comment or chat message to a user.
+-- start_index and length count Unicode code points.
+CREATE TABLE IF NOT EXISTS mentions (
+    id           UUID PRIMARY KEY,
+    source_type  TEXT NOT NULL CHECK (source_type IN ('post', 'comment', 'message', 'group_message')),
+    source_id    UUID NOT NULL,
+    user_id      UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    start_index  INT NOT NULL,
+    length       INT NOT NULL,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_mentions_source ON mentions (source_type, source_id);
+CREATE INDEX IF NOT EXISTS idx_mentions_user ON mentions (user_id);
+
+-- parent_id is what a client opens to show the source: the post of a
+-- comment, the room of a message or the group of a group message.
+CREATE TABLE IF NOT EXISTS notifications (
+    id           UUID PRIMARY KEY,
+    user_id      UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    actor_id     UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    type         TEXT NOT NULL,
+    source_type  TEXT NOT NULL,
+    source_id    UUID NOT NULL,
+    parent_id    UUID NOT NULL,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT now(),
+    read_at      TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_notifications_user_created_at ON notifications (user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_notifications_unread ON notifications (user_id) WHERE read_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_notifications_source ON notifications (source_type, source_id);
@@ -0,0 +1,7 @@
+-- Rooms have no member list, so the recipient a message was addressed to
+-- is kept with it. Together with the senders it tells who is in a room,
+-- including someone who has never replied.
+ALTER TABLE chat_messages ADD COLUMN IF NOT EXISTS recipient_id UUID REFERENCES users(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_chat_messages_room_recipient ON chat_messages (room_id, recipient_id)
+    WHERE recipient_id IS NOT NULL;
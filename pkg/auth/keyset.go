@@ -0,0 +1,203 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/Ramsi97/edu-social-backend/pkg/oidc"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// SigningKey is one private key of the key set, identified by its kid.
+type SigningKey struct {
+	ID      string
+	Method  jwt.SigningMethod
+	Private crypto.Signer
+}
+
+func NewSigningKey(id string, private crypto.Signer) (*SigningKey, error) {
+	switch private.(type) {
+	case *rsa.PrivateKey:
+		return &SigningKey{ID: id, Method: jwt.SigningMethodRS256, Private: private}, nil
+	case ed25519.PrivateKey:
+		return &SigningKey{ID: id, Method: jwt.SigningMethodEdDSA, Private: private}, nil
+	}
+	return nil, fmt.Errorf("key %s: unsupported key type %T", id, private)
+}
+
+// JWK returns the public half of the key in JWK form.
+func (k *SigningKey) JWK() oidc.JWK {
+	switch pub := k.Private.Public().(type) {
+	case *rsa.PublicKey:
+		return oidc.JWK{
+			Kty: "RSA",
+			Kid: k.ID,
+			Use: "sig",
+			Alg: k.Method.Alg(),
+			N:   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+		}
+	case ed25519.PublicKey:
+		return oidc.JWK{
+			Kty: "OKP",
+			Kid: k.ID,
+			Use: "sig",
+			Alg: k.Method.Alg(),
+			Crv: "Ed25519",
+			X:   base64.RawURLEncoding.EncodeToString(pub),
+		}
+	}
+	return oidc.JWK{}
+}
+
+// KeySet holds the key new tokens are signed with plus the keys that signed
+// tokens which may still be in circulation. Rotating means adding a new key,
+// making it active, and dropping the old one once its tokens have expired.
+type KeySet struct {
+	active *SigningKey
+	keys   map[string]*SigningKey
+}
+
+func NewKeySet(active *SigningKey, previous ...*SigningKey) *KeySet {
+	ks := &KeySet{
+		active: active,
+		keys:   map[string]*SigningKey{active.ID: active},
+	}
+	for _, k := range previous {
+		ks.keys[k.ID] = k
+	}
+	return ks
+}
+
+func (ks *KeySet) Active() *SigningKey {
+	return ks.active
+}
+
+func (ks *KeySet) Lookup(kid string) (*SigningKey, bool) {
+	k, ok := ks.keys[kid]
+	return k, ok
+}
+
+// JWKS returns every public key of the set, active key first.
+func (ks *KeySet) JWKS() oidc.JWKS {
+	set := oidc.JWKS{Keys: []oidc.JWK{ks.active.JWK()}}
+
+	ids := make([]string, 0, len(ks.keys))
+	for id := range ks.keys {
+		if id != ks.active.ID {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		set.Keys = append(set.Keys, ks.keys[id].JWK())
+	}
+	return set
+}
+
+// LoadKeySet reads every *.pem file in dir as a PKCS#8 private key, using
+// the file name without extension as the kid. The key named activeID signs
+// new tokens; when activeID is empty the last file in name order is used, so
+// date-prefixed names rotate naturally.
+func LoadKeySet(dir, activeID string) (*KeySet, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no *.pem keys found in %s", dir)
+	}
+	sort.Strings(files)
+
+	var keys []*SigningKey
+	for _, file := range files {
+		id := strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+
+		raw, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+
+		private, err := parsePrivateKey(raw)
+		if err != nil {
+			return nil, fmt.Errorf("key %s: %w", id, err)
+		}
+
+		key, err := NewSigningKey(id, private)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+
+	if activeID == "" {
+		activeID = keys[len(keys)-1].ID
+	}
+
+	var active *SigningKey
+	var previous []*SigningKey
+	for _, k := range keys {
+		if k.ID == activeID {
+			active = k
+		} else {
+			previous = append(previous, k)
+		}
+	}
+	if active == nil {
+		return nil, fmt.Errorf("active key %q not found in %s", activeID, dir)
+	}
+
+	return NewKeySet(active, previous...), nil
+}
+
+// GenerateEphemeralKeySet creates a throwaway Ed25519 key. Tokens signed
+// with it die with the process, so it is only meant for local development.
+func GenerateEphemeralKeySet() (*KeySet, error) {
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := NewSigningKey("ephemeral", private)
+	if err != nil {
+		return nil, err
+	}
+	return NewKeySet(key), nil
+}
+
+func parsePrivateKey(raw []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(raw)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	switch block.Type {
+	case "PRIVATE KEY":
+		key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		signer, ok := key.(crypto.Signer)
+		if !ok {
+			return nil, fmt.Errorf("unsupported key type %T", key)
+		}
+		return signer, nil
+	case "RSA PRIVATE KEY":
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	}
+
+	return nil, fmt.Errorf("unsupported PEM block %q", block.Type)
+}
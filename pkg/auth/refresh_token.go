@@ -0,0 +1,26 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+)
+
+// GenerateRefreshToken returns a random opaque token for the client and the
+// hash that should be persisted instead of the raw value.
+func GenerateRefreshToken() (string, string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", err
+	}
+
+	raw := base64.RawURLEncoding.EncodeToString(buf)
+	return raw, HashToken(raw), nil
+}
+
+// HashToken hashes an opaque token for storage and lookup.
+func HashToken(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
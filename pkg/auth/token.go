@@ -8,40 +8,105 @@ import (
 	"github.com/google/uuid"
 )
 
-var secretKey []byte
+var (
+	keySet   *KeySet
+	issuer   string
+	audience string
+)
+
+// SetSigningKeys installs the key set tokens are signed and verified with.
+func SetSigningKeys(keys *KeySet) {
+	keySet = keys
+}
 
-func SetJWTSecret(secret string) {
-    secretKey = []byte(secret)
+// SetIssuer sets the iss and aud claims put into and required from tokens.
+func SetIssuer(iss, aud string) {
+	issuer = iss
+	audience = aud
 }
 
-func GenerateToken(userID uuid.UUID, duration time.Duration) (string, error) {
-	claims := jwt.MapClaims{
-		"user_id": userID.String(),
-		"exp":     time.Now().Add(duration).Unix(),
+// PublicKeys returns the key set for publishing as a JWKS document.
+func PublicKeys() *KeySet {
+	return keySet
+}
+
+// Claims are the claims carried by every access token. SessionID ties the
+// token to the server-side session so it can be revoked before it expires.
+type Claims struct {
+	UserID    string `json:"user_id"`
+	SessionID string `json:"sid"`
+	Role      string `json:"role"`
+	jwt.RegisteredClaims
+}
+
+func GenerateToken(userID, sessionID uuid.UUID, role string, duration time.Duration) (string, error) {
+	if keySet == nil {
+		return "", errors.New("signing keys not configured")
+	}
+
+	now := time.Now()
+	claims := Claims{
+		UserID:    userID.String(),
+		SessionID: sessionID.String(),
+		Role:      role,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    issuer,
+			Subject:   userID.String(),
+			Audience:  jwt.ClaimStrings{audience},
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(duration)),
+		},
 	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(secretKey)
+
+	key := keySet.Active()
+	token := jwt.NewWithClaims(key.Method, claims)
+	token.Header["kid"] = key.ID
+	return token.SignedString(key.Private)
 }
 
-func ValidateToken(tokenStr string) (string, error) {
-	token, err := jwt.Parse(tokenStr, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+func ValidateToken(tokenStr string) (*Claims, error) {
+	if keySet == nil {
+		return nil, errors.New("signing keys not configured")
+	}
+
+	claims := &Claims{}
+	token, err := jwt.ParseWithClaims(tokenStr, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		key, ok := keySet.Lookup(kid)
+		if !ok {
+			return nil, errors.New("unknown signing key")
+		}
+		if token.Method.Alg() != key.Method.Alg() {
 			return nil, errors.New("unexpected signing method")
 		}
-		return secretKey, nil
-	})
+		return key.Private.Public(), nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodRS256.Alg(), jwt.SigningMethodEdDSA.Alg()}),
+		jwt.WithIssuer(issuer),
+		jwt.WithAudience(audience),
+		jwt.WithExpirationRequired(),
+	)
 
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
-		userID, ok := claims["user_id"].(string)
-		if !ok {
-			return "", errors.New("user_id not found in token")
-		}
-		return userID, nil
+	if !token.Valid {
+		return nil, errors.New("invalid token")
+	}
+
+	if claims.NotBefore == nil {
+		return nil, errors.New("nbf not found in token")
+	}
+
+	if claims.UserID == "" {
+		return nil, errors.New("user_id not found in token")
+	}
+
+	if claims.SessionID == "" {
+		return nil, errors.New("sid not found in token")
 	}
 
-	return "", errors.New("invalid token")
+	return claims, nil
 }
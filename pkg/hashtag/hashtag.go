@@ -0,0 +1,90 @@
+// Package hashtag finds the #tags in free text.
+package hashtag
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// MaxLength is the longest tag, in runes, that is recognised.
+const MaxLength = 64
+
+// Extract returns the normalized tags in text in the order they first
+// appear, each once. A tag is "#" followed by letters, digits or
+// underscores with at least one letter, so "#1" or a "#" inside a word or
+// URL fragment like "page#top" is left alone.
+func Extract(text string) []string {
+	var tags []string
+	seen := map[string]bool{}
+
+	prev := ' '
+	for i := 0; i < len(text); {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		if r != '#' || isTagRune(prev) || prev == '#' || prev == '&' {
+			prev = r
+			i += size
+			continue
+		}
+
+		end := i + size
+		for end < len(text) {
+			next, n := utf8.DecodeRuneInString(text[end:])
+			if !isTagRune(next) {
+				break
+			}
+			end += n
+		}
+
+		if tag, ok := Normalize(text[i+size : end]); ok && !seen[tag] {
+			seen[tag] = true
+			tags = append(tags, tag)
+		}
+
+		prev = r
+		i += size
+	}
+
+	return tags
+}
+
+// Normalize lowercases a tag given with or without its "#", reporting
+// false when it isn't a valid tag.
+func Normalize(tag string) (string, bool) {
+	tag = strings.ToLower(strings.TrimPrefix(tag, "#"))
+
+	n, letters := 0, 0
+	for _, r := range tag {
+		if !isTagRune(r) {
+			return "", false
+		}
+		if unicode.IsLetter(r) {
+			letters++
+		}
+		n++
+	}
+	if letters == 0 || n > MaxLength {
+		return "", false
+	}
+
+	return tag, true
+}
+
+// Prefix lowercases the start of a tag typed so far, reporting false when
+// no tag could begin with it.
+func Prefix(prefix string) (string, bool) {
+	prefix = strings.ToLower(strings.TrimPrefix(prefix, "#"))
+	if prefix == "" || utf8.RuneCountInString(prefix) > MaxLength {
+		return "", false
+	}
+	for _, r := range prefix {
+		if !isTagRune(r) {
+			return "", false
+		}
+	}
+	return prefix, true
+}
+
+func isTagRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.Is(unicode.Mn, r)
+}
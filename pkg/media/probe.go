@@ -0,0 +1,234 @@
+// Package media inspects uploaded files.
+package media
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	"io"
+
+	// Decoders for image.DecodeConfig.
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+)
+
+// Info is what could be learned about a file. Fields are nil when they
+// don't apply or the format isn't understood.
+type Info struct {
+	Width    *int
+	Height   *int
+	Duration *float64 // seconds
+}
+
+// ProbeImage reads the dimensions from an image header, as displayed: a
+// JPEG rotated by its EXIF orientation reports the rotated size.
+func ProbeImage(r io.Reader) (Info, error) {
+	var head bytes.Buffer
+	cfg, format, err := image.DecodeConfig(io.TeeReader(r, &head))
+	if err != nil {
+		return Info{}, err
+	}
+	if format == "jpeg" && jpegOrientation(head.Bytes()) >= 5 {
+		cfg.Width, cfg.Height = cfg.Height, cfg.Width
+	}
+	return Info{Width: &cfg.Width, Height: &cfg.Height}, nil
+}
+
+// ProbeVideo reads the duration and frame size of an MP4/QuickTime file
+// from its moov box. Other containers yield an empty Info.
+func ProbeVideo(r io.ReadSeeker) (Info, error) {
+	var info Info
+
+	moov, err := findBox(r, "moov", -1)
+	if err != nil || moov < 0 {
+		return info, err
+	}
+
+	err = walkBoxes(r, moov, 1, func(kind string, size int64) (bool, error) {
+		switch kind {
+		case "mvhd":
+			d, err := readMovieHeader(r)
+			if err != nil {
+				return false, err
+			}
+			info.Duration = d
+		case "trak":
+			// Descend: the video track's tkhd carries the frame size.
+			return true, nil
+		case "tkhd":
+			w, h, err := readTrackHeader(r)
+			if err != nil {
+				return false, err
+			}
+			if w > 0 && h > 0 && info.Width == nil {
+				info.Width, info.Height = &w, &h
+			}
+		}
+		return false, nil
+	})
+
+	return info, err
+}
+
+// findBox scans sibling boxes from the current offset for the given type
+// and returns the size of its body, leaving r at the start of the body.
+// limit bounds the scan to that many bytes; -1 scans to the end.
+func findBox(r io.ReadSeeker, kind string, limit int64) (int64, error) {
+	var scanned int64
+	for limit < 0 || scanned < limit {
+		size, name, header, err := readBoxHeader(r)
+		if errors.Is(err, io.EOF) {
+			return -1, nil
+		}
+		if err != nil {
+			return -1, err
+		}
+
+		if name == kind {
+			return size - header, nil
+		}
+
+		if size == 0 {
+			// Box runs to the end of the file.
+			return -1, nil
+		}
+		if _, err := r.Seek(size-header, io.SeekCurrent); err != nil {
+			return -1, err
+		}
+		scanned += size
+	}
+	return -1, nil
+}
+
+// maxBoxDepth is how deeply boxes may nest. Real files go no further than
+// moov/trak/mdia/minf/stbl and what it holds; a crafted file nesting
+// deeper would otherwise recurse until the stack runs out.
+const maxBoxDepth = 6
+
+// walkBoxes visits the boxes inside a container body of the given length,
+// which sits depth levels down. visit returns true to descend into a box
+// instead of skipping it.
+func walkBoxes(r io.ReadSeeker, length int64, depth int, visit func(kind string, size int64) (bool, error)) error {
+	if depth > maxBoxDepth {
+		return errors.New("media: boxes nested too deeply")
+	}
+
+	end, err := r.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+	end += length
+
+	for {
+		pos, err := r.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return err
+		}
+		if pos >= end {
+			return nil
+		}
+
+		size, kind, header, err := readBoxHeader(r)
+		if err != nil {
+			return err
+		}
+		if size == 0 {
+			size = end - pos
+		}
+		body := size - header
+
+		descend, err := visit(kind, body)
+		if err != nil {
+			return err
+		}
+		if descend {
+			if err := walkBoxes(r, body, depth+1, visit); err != nil {
+				return err
+			}
+		}
+
+		if _, err := r.Seek(pos+size, io.SeekStart); err != nil {
+			return err
+		}
+	}
+}
+
+func readBoxHeader(r io.Reader) (size int64, kind string, header int64, err error) {
+	var buf [8]byte
+	if _, err := io.ReadFull(r, buf[:]); err != nil {
+		return 0, "", 0, err
+	}
+
+	size = int64(binary.BigEndian.Uint32(buf[:4]))
+	kind = string(buf[4:8])
+	header = 8
+
+	if size == 1 {
+		var large [8]byte
+		if _, err := io.ReadFull(r, large[:]); err != nil {
+			return 0, "", 0, err
+		}
+		size = int64(binary.BigEndian.Uint64(large[:]))
+		header = 16
+	}
+
+	if size != 0 && size < header {
+		return 0, "", 0, errors.New("media: malformed box")
+	}
+
+	return size, kind, header, nil
+}
+
+func readMovieHeader(r io.Reader) (*float64, error) {
+	var version [4]byte
+	if _, err := io.ReadFull(r, version[:]); err != nil {
+		return nil, err
+	}
+
+	var timescale uint32
+	var duration uint64
+	if version[0] == 1 {
+		var buf [28]byte // creation, modification (8 each), timescale, duration (8)
+		if _, err := io.ReadFull(r, buf[:]); err != nil {
+			return nil, err
+		}
+		timescale = binary.BigEndian.Uint32(buf[16:20])
+		duration = binary.BigEndian.Uint64(buf[20:28])
+	} else {
+		var buf [16]byte // creation, modification, timescale, duration
+		if _, err := io.ReadFull(r, buf[:]); err != nil {
+			return nil, err
+		}
+		timescale = binary.BigEndian.Uint32(buf[8:12])
+		duration = uint64(binary.BigEndian.Uint32(buf[12:16]))
+	}
+
+	if timescale == 0 {
+		return nil, nil
+	}
+	seconds := float64(duration) / float64(timescale)
+	return &seconds, nil
+}
+
+func readTrackHeader(r io.Reader) (int, int, error) {
+	var version [4]byte
+	if _, err := io.ReadFull(r, version[:]); err != nil {
+		return 0, 0, err
+	}
+
+	// Skip to the 16.16 fixed-point width and height at the end of the box.
+	skip := 72
+	if version[0] == 1 {
+		skip = 84
+	}
+	buf := make([]byte, skip+8)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return 0, 0, err
+	}
+
+	width := int(binary.BigEndian.Uint32(buf[skip:skip+4]) >> 16)
+	height := int(binary.BigEndian.Uint32(buf[skip+4:skip+8]) >> 16)
+	return width, height, nil
+}
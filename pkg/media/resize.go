@@ -0,0 +1,83 @@
+package media
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+)
+
+// Resize scales img down so its longer side is at most maxSide, averaging
+// every source pixel into the one it lands on. Smaller images come back
+// unchanged.
+func Resize(img image.Image, maxSide int) image.Image {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w <= maxSide && h <= maxSide {
+		return img
+	}
+
+	dw, dh := maxSide, h*maxSide/w
+	if h > w {
+		dw, dh = w*maxSide/h, maxSide
+	}
+	dw, dh = max(dw, 1), max(dh, 1)
+
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	for dy := 0; dy < dh; dy++ {
+		y0, y1 := dy*h/dh, max((dy+1)*h/dh, dy*h/dh+1)
+		for dx := 0; dx < dw; dx++ {
+			x0, x1 := dx*w/dw, max((dx+1)*w/dw, dx*w/dw+1)
+
+			var r, g, bl, a, n uint64
+			for y := y0; y < y1; y++ {
+				for x := x0; x < x1; x++ {
+					pr, pg, pb, pa := img.At(b.Min.X+x, b.Min.Y+y).RGBA()
+					r, g, bl, a = r+uint64(pr), g+uint64(pg), bl+uint64(pb), a+uint64(pa)
+					n++
+				}
+			}
+			dst.SetRGBA(dx, dy, color.RGBA{
+				R: uint8(r / n >> 8),
+				G: uint8(g / n >> 8),
+				B: uint8(bl / n >> 8),
+				A: uint8(a / n >> 8),
+			})
+		}
+	}
+	return dst
+}
+
+// DominantColor returns the most common colour of img as #rrggbb, counting
+// colours in coarse buckets and skipping mostly transparent pixels. Meant
+// for small images such as thumbnails.
+func DominantColor(img image.Image) string {
+	type bucket struct{ r, g, b, n uint64 }
+	buckets := map[uint32]*bucket{}
+	var best *bucket
+
+	b := img.Bounds()
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			c := color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+			if c.A < 128 {
+				continue
+			}
+
+			key := uint32(c.R>>4)<<8 | uint32(c.G>>4)<<4 | uint32(c.B>>4)
+			bk := buckets[key]
+			if bk == nil {
+				bk = &bucket{}
+				buckets[key] = bk
+			}
+			bk.r, bk.g, bk.b, bk.n = bk.r+uint64(c.R), bk.g+uint64(c.G), bk.b+uint64(c.B), bk.n+1
+			if best == nil || bk.n > best.n {
+				best = bk
+			}
+		}
+	}
+
+	if best == nil {
+		return "#000000"
+	}
+	return fmt.Sprintf("#%02x%02x%02x", best.r/best.n, best.g/best.n, best.b/best.n)
+}
@@ -0,0 +1,226 @@
+package media
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"strings"
+
+	"github.com/gabriel-vasile/mimetype"
+)
+
+var (
+	ErrUnsupportedType = errors.New("unsupported file type")
+	ErrFileTooLarge    = errors.New("file too large")
+	ErrImageTooLarge   = errors.New("image dimensions too large")
+	ErrPolyglot        = errors.New("file contains embedded markup")
+	ErrCorruptImage    = errors.New("image could not be decoded")
+)
+
+// Policy is what an upload purpose accepts.
+type Policy struct {
+	// MaxBytes maps every accepted content type to its size cap.
+	MaxBytes map[string]int64
+	// MaxPixels caps width*height of images, so a small file can't expand
+	// into gigabytes once decoded.
+	MaxPixels int
+}
+
+// Sniff detects the content type from the file's leading bytes, ignoring
+// whatever the client claimed.
+func Sniff(r io.Reader) (string, error) {
+	mtype, err := mimetype.DetectReader(r)
+	if err != nil {
+		return "", err
+	}
+	contentType, _, _ := strings.Cut(mtype.String(), ";")
+	return contentType, nil
+}
+
+// extensions are the file extensions stored files get, by content type.
+var extensions = map[string]string{
+	"image/jpeg":         ".jpg",
+	"image/png":          ".png",
+	"image/gif":          ".gif",
+	"video/mp4":          ".mp4",
+	"video/quicktime":    ".mov",
+	"video/webm":         ".webm",
+	"application/pdf":    ".pdf",
+	"application/msword": ".doc",
+	"application/vnd.openxmlformats-officedocument.wordprocessingml.document":   ".docx",
+	"application/vnd.ms-powerpoint":                                             ".ppt",
+	"application/vnd.openxmlformats-officedocument.presentationml.presentation": ".pptx",
+}
+
+// Extension returns the file extension for a sniffed content type, or ""
+// when it has none.
+func Extension(contentType string) string {
+	return extensions[contentType]
+}
+
+// IsImage reports whether the content type is one SanitizeImage handles.
+func IsImage(contentType string) bool {
+	switch contentType {
+	case "image/jpeg", "image/png", "image/gif":
+		return true
+	}
+	return false
+}
+
+// markup that turns an image into something a browser or server may run.
+// Markers are long enough not to turn up by chance in compressed pixels.
+var markup = [][]byte{
+	[]byte("<script"), []byte("<html"), []byte("<body"), []byte("<iframe"), []byte("<?php"),
+}
+
+// SanitizeImage re-encodes a JPEG or PNG so that only pixels survive: EXIF,
+// GPS and any trailing payload are dropped. JPEG orientation is applied to
+// the pixels first so photos keep displaying the right way up. GIFs carry
+// no EXIF and are only checked: decoding every frame of an animation costs
+// more than it would remove.
+func SanitizeImage(data []byte, maxPixels int) ([]byte, error) {
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrCorruptImage, err)
+	}
+	if maxPixels > 0 && cfg.Width*cfg.Height > maxPixels {
+		return nil, fmt.Errorf("%w: %dx%d", ErrImageTooLarge, cfg.Width, cfg.Height)
+	}
+
+	lower := bytes.ToLower(data)
+	for _, m := range markup {
+		if bytes.Contains(lower, m) {
+			return nil, ErrPolyglot
+		}
+	}
+
+	var buf bytes.Buffer
+	switch format {
+	case "jpeg":
+		img, err := jpeg.Decode(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrCorruptImage, err)
+		}
+		img = orient(img, jpegOrientation(data))
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: 90})
+		if err != nil {
+			return nil, err
+		}
+	case "png":
+		img, err := png.Decode(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrCorruptImage, err)
+		}
+		if err := png.Encode(&buf, img); err != nil {
+			return nil, err
+		}
+	case "gif":
+		return data, nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedType, format)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// jpegOrientation returns the EXIF orientation (1-8) of a JPEG, or 1 when
+// there is none.
+func jpegOrientation(data []byte) int {
+	i := 2 // past SOI
+	for i+4 <= len(data) && data[i] == 0xFF {
+		marker := data[i+1]
+		length := int(binary.BigEndian.Uint16(data[i+2:]))
+		if marker == 0xDA || length < 2 { // start of scan: no more metadata
+			break
+		}
+		end := i + 2 + length
+		if end > len(data) {
+			break
+		}
+		segment := data[i+4 : end]
+		if marker == 0xE1 && bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+			return tiffOrientation(segment[6:])
+		}
+		i = end
+	}
+	return 1
+}
+
+func tiffOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+
+	ifd := int(order.Uint32(tiff[4:]))
+	if ifd+2 > len(tiff) {
+		return 1
+	}
+	entries := int(order.Uint16(tiff[ifd:]))
+	for n := 0; n < entries; n++ {
+		entry := ifd + 2 + n*12
+		if entry+12 > len(tiff) {
+			break
+		}
+		if order.Uint16(tiff[entry:]) == 0x0112 {
+			if o := int(order.Uint16(tiff[entry+8:])); o >= 1 && o <= 8 {
+				return o
+			}
+			break
+		}
+	}
+	return 1
+}
+
+// orient turns the stored pixels into the way the camera meant them to be
+// seen. Orientations 5-8 swap width and height.
+func orient(src image.Image, orientation int) image.Image {
+	if orientation < 2 || orientation > 8 {
+		return src
+	}
+
+	b := src.Bounds()
+	w, h := b.Dx(), b.Dy()
+	dw, dh := w, h
+	if orientation >= 5 {
+		dw, dh = h, w
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			var dx, dy int
+			switch orientation {
+			case 2:
+				dx, dy = w-1-x, y
+			case 3:
+				dx, dy = w-1-x, h-1-y
+			case 4:
+				dx, dy = x, h-1-y
+			case 5:
+				dx, dy = y, x
+			case 6:
+				dx, dy = h-1-y, x
+			case 7:
+				dx, dy = h-1-y, w-1-x
+			case 8:
+				dx, dy = y, w-1-x
+			}
+			dst.Set(dx, dy, src.At(b.Min.X+x, b.Min.Y+y))
+		}
+	}
+	return dst
+}
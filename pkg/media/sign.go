@@ -0,0 +1,30 @@
+package media
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"strconv"
+	"time"
+)
+
+// Sign returns the signature that lets key be fetched until expires.
+func Sign(secret []byte, key string, expires time.Time) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(key + "\n" + strconv.FormatInt(expires.Unix(), 10)))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks a signature made by Sign against the expires query value
+// it was sent with.
+func Verify(secret []byte, key, expires, signature string) bool {
+	unix, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil {
+		return false
+	}
+	at := time.Unix(unix, 0)
+	if time.Now().After(at) {
+		return false
+	}
+	return hmac.Equal([]byte(Sign(secret, key, at)), []byte(signature))
+}
@@ -0,0 +1,83 @@
+// Package mention finds the @handles in free text.
+package mention
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	MinHandleLength = 3
+	MaxHandleLength = 30
+)
+
+// Match is one "@handle" in a text. Offset and Length count Unicode code
+// points and cover the "@" as well as the handle.
+type Match struct {
+	Handle string
+	Offset int
+	Length int
+}
+
+// Find returns every well-formed mention in text, in order. Handle is
+// lowercased; an "@" inside a word, as in an email address, is not a
+// mention.
+func Find(text string) []Match {
+	var matches []Match
+
+	prev := ' '
+	runes := 0
+	for i := 0; i < len(text); {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		if r != '@' || isHandleByte(prev) || prev == '@' {
+			prev = r
+			i += size
+			runes++
+			continue
+		}
+
+		end := i + 1
+		for end < len(text) && isHandleByte(rune(text[end])) {
+			end++
+		}
+
+		// A run that is too long or followed by another "@" is not a handle.
+		if handle, ok := NormalizeHandle(text[i+1 : end]); ok && (end == len(text) || text[end] != '@') {
+			matches = append(matches, Match{
+				Handle: handle,
+				Offset: runes,
+				Length: end - i,
+			})
+		}
+
+		// Handles are ASCII, so bytes and code points agree from here.
+		runes += end - i
+		prev = rune(text[end-1])
+		i = end
+	}
+
+	return matches
+}
+
+// NormalizeHandle lowercases a handle given with or without its "@",
+// reporting false unless it is 3 to 30 letters, digits or underscores
+// starting with a letter.
+func NormalizeHandle(handle string) (string, bool) {
+	handle = strings.ToLower(strings.TrimPrefix(handle, "@"))
+	if len(handle) < MinHandleLength || len(handle) > MaxHandleLength {
+		return "", false
+	}
+	if handle[0] < 'a' || handle[0] > 'z' {
+		return "", false
+	}
+	for i := 0; i < len(handle); i++ {
+		if !isHandleByte(rune(handle[i])) {
+			return "", false
+		}
+	}
+	return handle, true
+}
+
+func isHandleByte(r rune) bool {
+	return r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9')
+}
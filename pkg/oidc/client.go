@@ -0,0 +1,255 @@
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// ClaimMapping names the ID token claims that carry our user fields, since
+// campus identity providers rarely agree on them.
+type ClaimMapping struct {
+	StudentID     string
+	Email         string
+	EmailVerified string
+	FirstName     string
+	LastName      string
+}
+
+func DefaultClaimMapping() ClaimMapping {
+	return ClaimMapping{
+		StudentID:     "student_id",
+		Email:         "email",
+		EmailVerified: "email_verified",
+		FirstName:     "given_name",
+		LastName:      "family_name",
+	}
+}
+
+type Config struct {
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+	Claims       ClaimMapping
+	HTTPClient   *http.Client
+}
+
+// Identity is the verified subset of an ID token we care about.
+type Identity struct {
+	Issuer        string
+	Subject       string
+	Email         string
+	EmailVerified bool
+	StudentID     string
+	FirstName     string
+	LastName      string
+}
+
+type discovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// Client implements the authorization code flow with PKCE against a single
+// OpenID Connect provider. Provider metadata is discovered lazily from
+// {Issuer}/.well-known/openid-configuration.
+type Client struct {
+	cfg Config
+
+	mu   sync.Mutex
+	meta *discovery
+	keys *keyCache
+}
+
+func NewClient(cfg Config) *Client {
+	if cfg.HTTPClient == nil {
+		cfg.HTTPClient = &http.Client{Timeout: 10 * time.Second}
+	}
+	if len(cfg.Scopes) == 0 {
+		cfg.Scopes = []string{"openid", "email", "profile"}
+	}
+	cfg.Issuer = strings.TrimRight(cfg.Issuer, "/")
+
+	return &Client{cfg: cfg}
+}
+
+func (c *Client) Issuer() string {
+	return c.cfg.Issuer
+}
+
+func (c *Client) metadata(ctx context.Context) (*discovery, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.meta != nil {
+		return c.meta, nil
+	}
+
+	var meta discovery
+	if err := getJSON(ctx, c.cfg.HTTPClient, c.cfg.Issuer+"/.well-known/openid-configuration", &meta); err != nil {
+		return nil, fmt.Errorf("oidc discovery: %w", err)
+	}
+
+	if strings.TrimRight(meta.Issuer, "/") != c.cfg.Issuer {
+		return nil, fmt.Errorf("oidc discovery: issuer mismatch %q", meta.Issuer)
+	}
+
+	c.meta = &meta
+	c.keys = &keyCache{
+		url:        meta.JWKSURI,
+		httpClient: c.cfg.HTTPClient,
+		minRefresh: time.Minute,
+	}
+	return c.meta, nil
+}
+
+// AuthCodeURL returns the provider URL the user agent is sent to.
+func (c *Client) AuthCodeURL(ctx context.Context, state, nonce, codeChallenge string) (string, error) {
+	meta, err := c.metadata(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	q := url.Values{}
+	q.Set("response_type", "code")
+	q.Set("client_id", c.cfg.ClientID)
+	q.Set("redirect_uri", c.cfg.RedirectURL)
+	q.Set("scope", strings.Join(c.cfg.Scopes, " "))
+	q.Set("state", state)
+	q.Set("nonce", nonce)
+	q.Set("code_challenge", codeChallenge)
+	q.Set("code_challenge_method", "S256")
+
+	sep := "?"
+	if strings.Contains(meta.AuthorizationEndpoint, "?") {
+		sep = "&"
+	}
+	return meta.AuthorizationEndpoint + sep + q.Encode(), nil
+}
+
+// Exchange redeems an authorization code and verifies the returned ID token.
+func (c *Client) Exchange(ctx context.Context, code, codeVerifier, nonce string) (*Identity, error) {
+	meta, err := c.metadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", c.cfg.RedirectURL)
+	form.Set("client_id", c.cfg.ClientID)
+	form.Set("code_verifier", codeVerifier)
+	if c.cfg.ClientSecret != "" {
+		form.Set("client_secret", c.cfg.ClientSecret)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, meta.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	res, err := c.cfg.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	var body struct {
+		IDToken          string `json:"id_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("oidc token response: %w", err)
+	}
+
+	if res.StatusCode != http.StatusOK || body.Error != "" {
+		return nil, fmt.Errorf("oidc token exchange failed: %s %s", body.Error, body.ErrorDescription)
+	}
+
+	if body.IDToken == "" {
+		return nil, errors.New("oidc token response has no id_token")
+	}
+
+	return c.verifyIDToken(ctx, body.IDToken, nonce)
+}
+
+func (c *Client) verifyIDToken(ctx context.Context, raw, nonce string) (*Identity, error) {
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(raw, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return c.keys.key(ctx, kid)
+	},
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "ES256", "ES384", "ES512", "EdDSA"}),
+		jwt.WithIssuer(c.cfg.Issuer),
+		jwt.WithAudience(c.cfg.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(time.Minute),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("invalid id_token: %w", err)
+	}
+
+	if got, _ := claims["nonce"].(string); got == "" || got != nonce {
+		return nil, errors.New("invalid id_token: nonce mismatch")
+	}
+
+	sub, _ := claims["sub"].(string)
+	if sub == "" {
+		return nil, errors.New("invalid id_token: missing sub")
+	}
+
+	m := c.cfg.Claims
+	return &Identity{
+		Issuer:        c.cfg.Issuer,
+		Subject:       sub,
+		Email:         stringClaim(claims, m.Email),
+		EmailVerified: boolClaim(claims, m.EmailVerified),
+		StudentID:     stringClaim(claims, m.StudentID),
+		FirstName:     stringClaim(claims, m.FirstName),
+		LastName:      stringClaim(claims, m.LastName),
+	}, nil
+}
+
+func stringClaim(claims jwt.MapClaims, name string) string {
+	if name == "" {
+		return ""
+	}
+	switch v := claims[name].(type) {
+	case string:
+		return v
+	case float64:
+		return fmt.Sprintf("%.0f", v)
+	}
+	return ""
+}
+
+// boolClaim accepts both JSON booleans and the "true" strings some
+// providers send.
+func boolClaim(claims jwt.MapClaims, name string) bool {
+	if name == "" {
+		return false
+	}
+	switch v := claims[name].(type) {
+	case bool:
+		return v
+	case string:
+		return v == "true"
+	}
+	return false
+}
@@ -0,0 +1,350 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	testClientID    = "edu-social"
+	testRedirectURL = "https://app.example/auth/sso/callback"
+)
+
+// stubIssuer is a minimal OpenID provider: it serves discovery and JWKS,
+// answers the authorize endpoint with a redirect carrying a one-time code,
+// and redeems that code at the token endpoint after checking PKCE.
+type stubIssuer struct {
+	t      *testing.T
+	server *httptest.Server
+	key    *rsa.PrivateKey
+
+	mu      sync.Mutex
+	pending map[string]url.Values
+
+	// claims lets a test add or override ID token claims; tokenNonce, when
+	// set, replaces the nonce the client sent.
+	claims     jwt.MapClaims
+	tokenNonce string
+	issuer     string
+	audience   string
+}
+
+func newStubIssuer(t *testing.T) *stubIssuer {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := &stubIssuer{t: t, key: key, pending: map[string]url.Values{}}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", s.discovery)
+	mux.HandleFunc("/jwks", s.jwks)
+	mux.HandleFunc("/authorize", s.authorize)
+	mux.HandleFunc("/token", s.token)
+
+	s.server = httptest.NewServer(mux)
+	t.Cleanup(s.server.Close)
+	return s
+}
+
+func (s *stubIssuer) client(claims ClaimMapping) *Client {
+	return NewClient(Config{
+		Issuer:      s.server.URL,
+		ClientID:    testClientID,
+		RedirectURL: testRedirectURL,
+		Claims:      claims,
+		HTTPClient:  s.server.Client(),
+	})
+}
+
+func (s *stubIssuer) discovery(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, discovery{
+		Issuer:                s.server.URL,
+		AuthorizationEndpoint: s.server.URL + "/authorize",
+		TokenEndpoint:         s.server.URL + "/token",
+		JWKSURI:               s.server.URL + "/jwks",
+	})
+}
+
+func (s *stubIssuer) jwks(w http.ResponseWriter, r *http.Request) {
+	pub := s.key.PublicKey
+	writeJSON(w, http.StatusOK, JWKS{Keys: []JWK{{
+		Kty: "RSA",
+		Kid: "test",
+		Use: "sig",
+		Alg: "RS256",
+		N:   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+		E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+	}}})
+}
+
+func (s *stubIssuer) authorize(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	if q.Get("client_id") != testClientID || q.Get("code_challenge_method") != "S256" {
+		http.Error(w, "bad authorization request", http.StatusBadRequest)
+		return
+	}
+
+	code, err := RandomString()
+	if err != nil {
+		s.t.Fatal(err)
+	}
+	s.mu.Lock()
+	s.pending[code] = q
+	s.mu.Unlock()
+
+	back, _ := url.Parse(q.Get("redirect_uri"))
+	back.RawQuery = url.Values{"code": {code}, "state": {q.Get("state")}}.Encode()
+	http.Redirect(w, r, back.String(), http.StatusFound)
+}
+
+func (s *stubIssuer) token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	req, ok := s.pending[r.PostForm.Get("code")]
+	delete(s.pending, r.PostForm.Get("code"))
+	s.mu.Unlock()
+
+	switch {
+	case !ok:
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+		return
+	case S256Challenge(r.PostForm.Get("code_verifier")) != req.Get("code_challenge"):
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant", "error_description": "pkce"})
+		return
+	case r.PostForm.Get("redirect_uri") != req.Get("redirect_uri"):
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant", "error_description": "redirect_uri"})
+		return
+	}
+
+	nonce := req.Get("nonce")
+	if s.tokenNonce != "" {
+		nonce = s.tokenNonce
+	}
+	iss := s.server.URL
+	if s.issuer != "" {
+		iss = s.issuer
+	}
+	aud := testClientID
+	if s.audience != "" {
+		aud = s.audience
+	}
+
+	claims := jwt.MapClaims{
+		"iss":         iss,
+		"aud":         aud,
+		"sub":         "user-123",
+		"exp":         time.Now().Add(time.Hour).Unix(),
+		"iat":         time.Now().Unix(),
+		"nonce":       nonce,
+		"email":       "abebe@students.example",
+		"given_name":  "Abebe",
+		"family_name": "Kebede",
+		"student_id":  "UGR/1234/15",
+	}
+	for k, v := range s.claims {
+		claims[k] = v
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = "test"
+	signed, err := token.SignedString(s.key)
+	if err != nil {
+		s.t.Fatal(err)
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"id_token": signed, "token_type": "Bearer"})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// signIn drives the user agent side of the flow: it follows the
+// authorization URL to the provider and returns the code and state from the
+// redirect back to us.
+func signIn(t *testing.T, s *stubIssuer, authURL string) (code, state string) {
+	t.Helper()
+
+	hc := s.server.Client()
+	hc.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+
+	res, err := hc.Get(authURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	if res.StatusCode != http.StatusFound {
+		t.Fatalf("authorize returned %d", res.StatusCode)
+	}
+
+	loc, err := url.Parse(res.Header.Get("Location"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(loc.String(), testRedirectURL+"?") {
+		t.Fatalf("redirected to %q", loc)
+	}
+	return loc.Query().Get("code"), loc.Query().Get("state")
+}
+
+type flow struct {
+	state, nonce, verifier string
+}
+
+func newFlow(t *testing.T) flow {
+	t.Helper()
+
+	var f flow
+	for _, p := range []*string{&f.state, &f.nonce, &f.verifier} {
+		v, err := RandomString()
+		if err != nil {
+			t.Fatal(err)
+		}
+		*p = v
+	}
+	return f
+}
+
+func (f flow) run(t *testing.T, s *stubIssuer, c *Client) (*Identity, error) {
+	t.Helper()
+
+	ctx := context.Background()
+	authURL, err := c.AuthCodeURL(ctx, f.state, f.nonce, S256Challenge(f.verifier))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	code, state := signIn(t, s, authURL)
+	if state != f.state {
+		t.Fatalf("state = %q, want %q", state, f.state)
+	}
+
+	return c.Exchange(ctx, code, f.verifier, f.nonce)
+}
+
+func TestClientFlow(t *testing.T) {
+	s := newStubIssuer(t)
+	s.claims = jwt.MapClaims{"email_verified": true}
+
+	identity, err := newFlow(t).run(t, s, s.client(DefaultClaimMapping()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := Identity{
+		Issuer:        s.server.URL,
+		Subject:       "user-123",
+		Email:         "abebe@students.example",
+		EmailVerified: true,
+		StudentID:     "UGR/1234/15",
+		FirstName:     "Abebe",
+		LastName:      "Kebede",
+	}
+	if *identity != want {
+		t.Fatalf("identity = %+v, want %+v", *identity, want)
+	}
+}
+
+func TestClientEmailVerifiedClaimMapping(t *testing.T) {
+	s := newStubIssuer(t)
+	// The provider only vouches through its own claim; a plain
+	// email_verified must not be trusted once the mapping points elsewhere.
+	s.claims = jwt.MapClaims{"email_verified": true, "mail_confirmed": "false"}
+
+	claims := DefaultClaimMapping()
+	claims.EmailVerified = "mail_confirmed"
+
+	identity, err := newFlow(t).run(t, s, s.client(claims))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if identity.EmailVerified {
+		t.Fatal("email_verified read from the default claim instead of the mapped one")
+	}
+
+	s.claims = jwt.MapClaims{"mail_confirmed": "true"}
+	identity, err = newFlow(t).run(t, s, s.client(claims))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !identity.EmailVerified {
+		t.Fatal("mapped email_verified claim was ignored")
+	}
+}
+
+func TestClientRejectsBadTokens(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(s *stubIssuer)
+	}{
+		{"nonce mismatch", func(s *stubIssuer) { s.tokenNonce = "replayed" }},
+		{"wrong issuer", func(s *stubIssuer) { s.issuer = "https://evil.example" }},
+		{"wrong audience", func(s *stubIssuer) { s.audience = "someone-else" }},
+		{"expired", func(s *stubIssuer) { s.claims = jwt.MapClaims{"exp": time.Now().Add(-time.Hour).Unix()} }},
+		{"missing sub", func(s *stubIssuer) { s.claims = jwt.MapClaims{"sub": ""} }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newStubIssuer(t)
+			tt.setup(s)
+
+			if _, err := newFlow(t).run(t, s, s.client(DefaultClaimMapping())); err == nil {
+				t.Fatal("Exchange accepted a bad id_token")
+			}
+		})
+	}
+}
+
+func TestClientRejectsWrongVerifier(t *testing.T) {
+	s := newStubIssuer(t)
+	c := s.client(DefaultClaimMapping())
+	f := newFlow(t)
+
+	ctx := context.Background()
+	authURL, err := c.AuthCodeURL(ctx, f.state, f.nonce, S256Challenge(f.verifier))
+	if err != nil {
+		t.Fatal(err)
+	}
+	code, _ := signIn(t, s, authURL)
+
+	if _, err := c.Exchange(ctx, code, "not-the-verifier", f.nonce); err == nil {
+		t.Fatal("Exchange succeeded with the wrong PKCE verifier")
+	}
+}
+
+func TestClientRejectsIssuerMismatch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, discovery{Issuer: "https://evil.example"})
+	}))
+	defer server.Close()
+
+	c := NewClient(Config{Issuer: server.URL, ClientID: testClientID, HTTPClient: server.Client()})
+	if _, err := c.AuthCodeURL(context.Background(), "state", "nonce", "challenge"); err == nil {
+		t.Fatal("discovery accepted a document for another issuer")
+	}
+}
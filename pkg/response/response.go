@@ -1,6 +1,11 @@
 package response
 
-import "github.com/gin-gonic/gin"
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
 
 type Response struct{
 	Success bool `json:"success"`
@@ -24,4 +29,15 @@ func Error(c *gin.Context, code int, message string, err string){
 		Message: message,
 		Error: err,
 	})
-}
\ No newline at end of file
+}
+
+// ErrorRetryAfter writes an error response with a Retry-After header, for
+// 429 and 423 responses the client is expected to back off from.
+func ErrorRetryAfter(c *gin.Context, code int, message string, err string, retryAfter time.Duration) {
+	seconds := int(retryAfter.Round(time.Second) / time.Second)
+	if seconds < 1 {
+		seconds = 1
+	}
+	c.Header("Retry-After", strconv.Itoa(seconds))
+	Error(c, code, message, err)
+}
@@ -0,0 +1,14 @@
+package websocket
+
+// SessionRoom is the Socket.IO room every connection joins for the auth
+// session it was opened with, so all of a session's sockets can be dropped
+// together when it is terminated.
+func SessionRoom(sessionID string) string {
+	return "session:" + sessionID
+}
+
+// UserRoom is the Socket.IO room every connection of a user joins, so
+// events meant for the user reach all of their devices.
+func UserRoom(userID string) string {
+	return "user:" + userID
+}